@@ -1,12 +1,42 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
 	"github.com/jfavo/integra-partners-assessment-backend/internal/app"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
 )
 
 // @title IP Assessment API
 // @version 1.0
 // @description RESTful API to support the IP Assessment Front end application
 func main() {
-	app.StartServer()
-}
\ No newline at end of file
+	configFile := flag.String("config", os.Getenv(config.FileEnv), "path of a YAML or TOML config file")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-config file] [config print]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid config:\n%s\n", err)
+		os.Exit(1)
+	}
+
+	switch command := flag.Args(); {
+	case len(command) == 0:
+		app.StartServer(cfg)
+	case len(command) == 2 && command[0] == "config" && command[1] == "print":
+		// Prints the effective config with its secrets masked
+		if err := cfg.Print(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
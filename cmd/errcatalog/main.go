@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+)
+
+// Writes the error code catalog as errors.md and errors.json so the docs
+// are always generated from the codes in internal/errors.
+func main() {
+	out := flag.String("out", "./docs", "directory to write the catalog to")
+	flag.Parse()
+
+	if err := writeFile(filepath.Join(*out, "errors.json"), errors.WriteCatalogJSON); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := writeFile(filepath.Join(*out, "errors.md"), errors.WriteCatalogMarkdown); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeFile creates or truncates the file at path and writes to it with write.
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := write(f); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
@@ -1,41 +1,113 @@
 package app
 
 import (
+	"context"
+	"crypto/tls"
+	stdErrors "errors"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	_ "github.com/jfavo/integra-partners-assessment-backend/docs"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/certs"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/controllers"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/cors"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/features"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/health"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/identity"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mailer"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/metrics"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/ratelimit"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/requestid"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/scheduler"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/tracing"
 
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
-	"github.com/labstack/gommon/log"
 	"github.com/swaggo/echo-swagger"
 )
 
-// StartServer will create a new server instance and all dependent resources.
-//
-// Will throw panic if the DB repository fails to initialize.
-func StartServer() {
-	e := echo.New()
+// ShutdownSignals are the signals WaitForShutdown stops the app on
+var ShutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
 
-	// Configure middlewares
-	e.Use(middleware.CORS())
-	e.Use(middleware.Logger())
+// mailQueueSize is how many emails can be waiting to be sent
+const mailQueueSize = 100
 
-	// Add swagger documentation page
-	e.GET("/docs/*", echoSwagger.WrapHandler)
+// App is the API server along with the resources it owns, which are
+// released when it is stopped.
+type App struct {
+	// Config the app was started with. Reloaded settings are not applied
+	// to it.
+	Config *config.Config
+	Echo   *echo.Echo
+	Health *health.Checker
+
+	repo      database.Repo
+	admin     *echo.Echo
+	mailer    *mailer.Queue
+	scheduler *scheduler.Scheduler
+	monitor   *database.Monitor
+	// Set once started when serving over TLS
+	certs *certs.Reloader
+
+	// Settings that are applied again when the config is reloaded
+	cors         *cors.Policy
+	resetLimiter *ratelimit.Limiter
+
+	// Config as last loaded, which reloads are compared against so a
+	// change needing a restart is only logged when it is made
+	loaded *config.Config
+	// Config with the reloaded settings that have been applied, which
+	// guards reloading so one is applied at a time
+	runtime    *config.Config
+	reloadMu   sync.Mutex
+	reloadStop chan struct{}
+	reloadWG   sync.WaitGroup
+
+	// Receives the error of a server that stopped without being asked to
+	errs chan error
+}
+
+// StartServer will create a new server instance and all dependent resources
+// with the config, serving until the process is sent SIGINT or SIGTERM.
+// The config is reloaded on SIGHUP or when its file changes.
+//
+// Will throw panic if the DB repository fails to initialize, or the server
+// fails to start.
+func StartServer(config *config.Config) {
+	// Configure logging before anything else logs, keeping the defaults
+	// if the config is invalid
+	if err := logging.Configure(config.Logging.Level, config.Logging.Format); err != nil {
+		logging.Error("StartServer", "invalid logging config, using defaults", err)
+	}
+	logging.SetRedaction(config.Logging.RedactFields, config.Logging.RedactAllow)
 
-	config := config.New()
+	// Start exporting spans, carrying on without them if the exporter
+	// fails to start so tracing can't take the API down
+	shutdownTracing, err := tracing.Setup(context.Background(), config.Tracing)
+	if err != nil {
+		logging.Error("StartServer", "failed to set up tracing, spans will not be exported", err)
+	} else {
+		defer shutdownTracing(context.Background())
+	}
 
-	// Initialize Database client
-	repo, err := database.CreateNewRepo(config.Database)
+	// Initialize Database client, waiting for the DB to come up unless
+	// the process is asked to stop first
+	connectCtx, stop := signal.NotifyContext(context.Background(), ShutdownSignals...)
+	repo, err := database.CreateNewRepo(connectCtx, config.Database, config.Users.StatusTransitions)
+	stop()
 	if err != nil {
 		code := errors.DBRepoFailedToInitialize
-		errMessage := errors.GetErrorMessage(code)
+		errMessage := errors.GetErrorMessage(code, errors.DefaultLocale)
 		logging.ErrorWithCode(
 			code,
 			errMessage,
@@ -43,11 +115,281 @@ func StartServer() {
 		panic(errMessage)
 	}
 
+	app := New(config, repo)
+	if err := app.Start(); err != nil {
+		// Nothing else has been started that needs stopping, only the
+		// pool is left open
+		if closeErr := repo.Close(); closeErr != nil {
+			logging.Error("StartServer", "failed to close DB", closeErr)
+		}
+		logging.Error("StartServer", "failed to start server", err)
+		panic(fmt.Sprintf("failed to start server: %s", err))
+	}
+
+	if err := app.WaitForShutdown(context.Background()); err != nil {
+		logging.Error("StartServer", "failed to shut down cleanly", err)
+	}
+}
+
+// New creates an App serving the API with the repo, which it closes
+// when stopped.
+func New(config *config.Config, repo database.Repo) *App {
+	e := echo.New()
+
+	app := &App{
+		Config: config,
+		Echo:   e,
+		Health: health.NewChecker(repo, time.Duration(config.Health.Timeout)*time.Second),
+		repo:   repo,
+		errs:   make(chan error, 2),
+		cors:   cors.NewPolicy(config.Server.CORS),
+		resetLimiter: ratelimit.NewLimiter(
+			config.PasswordReset.RateLimit,
+			time.Duration(config.PasswordReset.RateLimitWindow)*time.Minute),
+		runtime: config,
+		loaded:  config,
+		// Emails are sent in the background so the time taken to send
+		// them can't be measured by clients
+		mailer: mailer.NewQueue(mailer.New(config.Mailer), mailQueueSize),
+	}
+	features.Set(config.Features)
+
+	// Configure middlewares
+	// The request id is assigned first so every later middleware and
+	// handler can log it
+	e.Use(requestid.Middleware())
+	e.Use(identity.Middleware())
+	e.Use(tracing.Middleware())
+	e.Use(logging.AccessLogger())
+	if config.Metrics.Enabled {
+		e.Use(metrics.Middleware())
+	}
+	e.Use(app.cors.Middleware())
+	// Only the allowed clients can call the API, while health checks are
+	// left open to the orchestrator probing them
+	if allowed := config.Server.TLS.AllowedClients; len(allowed) > 0 {
+		e.Use(identity.Authorize(allowed, "/healthz", "/readyz"))
+	}
+
+	// Add swagger documentation page
+	e.GET("/docs/*", echoSwagger.WrapHandler)
+
+	// Serve metrics on the API port, unless an admin port is configured
+	// to keep them off the public listener
+	if config.Metrics.Enabled {
+		if config.Metrics.Port == "" {
+			e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+		} else {
+			app.admin = newAdminServer()
+		}
+	}
+
+	// Errors returned by handlers are logged and rendered with their status,
+	// as problem documents when configured, otherwise only when the client
+	// asks for them
+	e.HTTPErrorHandler = response.NewHTTPErrorHandler(e, config.Server.ErrorFormat)
+
+	deps := controllers.Dependencies{
+		Repo:   repo,
+		Mailer: app.mailer,
+		Config: config,
+		Health: app.Health,
+
+		ResetLimiter: app.resetLimiter,
+	}
+
 	// Initialize Controllers
 	// This will create a new UserController struct, attach our DB repository
 	// to it, and register its routes
-	controllers.Initialize[controllers.UserController](repo, e)
+	controllers.Initialize[controllers.UserController](deps, e)
+	controllers.Initialize[controllers.PasswordResetController](deps, e)
+	controllers.Initialize[controllers.AuditController](deps, e)
+	controllers.Initialize[controllers.StatusChangeController](deps, e)
+	controllers.Initialize[controllers.ErrorController](deps, e)
+	controllers.Initialize[controllers.HealthController](deps, e)
+
+	app.scheduler = scheduler.New(repo, time.Duration(config.Scheduler.Interval)*time.Second)
+	if config.Database.MonitorInterval > 0 {
+		app.monitor = database.NewMonitor(
+			repo,
+			time.Duration(config.Database.MonitorInterval)*time.Second,
+			database.ConnectBackoff(config.Database),
+			app.Health)
+	}
+
+	return app
+}
+
+// Start listens on the configured ports and serves in the background,
+// along with applying scheduled status changes, monitoring the DB
+// connection and reloading the config. The API is served over TLS when a
+// certificate is configured.
+//
+// Returns an error if a port can't be listened on, or the TLS files can't
+// be loaded.
+func (a *App) Start() error {
+	var tlsConfig *tls.Config
+	if tlsSettings := a.Config.Server.TLS; tlsSettings.Enabled() {
+		reloader, err := certs.NewReloader(tlsSettings)
+		if err != nil {
+			return err
+		}
+		a.certs = reloader
+		tlsConfig = reloader.TLSConfig()
+
+		logging.Logger.Info("Serving over TLS",
+			"min_version", tlsSettings.MinVersion,
+			"client_certs", tlsSettings.ClientCAFile != "")
+	}
+
+	if err := a.listen(a.Echo, a.Config.Server.Port, tlsConfig); err != nil {
+		return err
+	}
+
+	if a.admin != nil {
+		// Kept on plain HTTP, as it is only reachable internally
+		if err := a.listen(a.admin, a.Config.Metrics.Port, nil); err != nil {
+			a.Echo.Close()
+			return err
+		}
+		logging.Logger.Info("Serving metrics on admin port", "port", a.Config.Metrics.Port)
+	}
+
+	a.mailer.Start()
+
+	// Start applying scheduled status changes in the background
+	a.scheduler.Start()
+
+	if a.monitor != nil {
+		a.monitor.Start()
+	}
+
+	a.watchConfig()
+
+	if a.certs != nil {
+		a.certs.Start()
+	}
+
+	return nil
+}
+
+// Addr returns the address the API is being served on once started.
+func (a *App) Addr() net.Addr {
+	return a.Echo.Listener.Addr()
+}
+
+// WaitForShutdown blocks until the process is sent a ShutdownSignal, the
+// context is done or a server stops unexpectedly, then stops the app,
+// giving in-flight requests the configured shutdown timeout to finish
+// once the drain delay has passed.
+//
+// Returns the error the server stopped with, or that stopping failed with.
+func (a *App) WaitForShutdown(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, ShutdownSignals...)
+	defer stop()
+
+	var serveErr error
+	select {
+	case <-ctx.Done():
+		logging.Logger.Info("Shutting down")
+	case serveErr = <-a.errs:
+		logging.Error("WaitForShutdown", "server stopped unexpectedly, shutting down", serveErr)
+	}
+
+	drainCtx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(a.Config.Server.ShutdownDrainDelay+a.Config.Server.ShutdownTimeout)*time.Second)
+	defer cancel()
+
+	return stdErrors.Join(serveErr, a.Stop(drainCtx))
+}
+
+// Stop reports the app as not ready, so no new traffic is routed to it,
+// and keeps serving for the configured drain delay while load balancers
+// catch up. It then stops accepting connections and waits for in-flight
+// requests to finish until the context is done. Queued emails are then sent, reloading
+// the config and certificates, the scheduler and the DB monitor are stopped
+// and the DB pool closed.
+//
+// Returns an error if requests were still in flight when the context was
+// done, or if closing a resource fails.
+func (a *App) Stop(ctx context.Context) error {
+	a.Health.SetShuttingDown()
+
+	// Requests routed before the load balancer saw the app as not ready
+	// would be refused if connections stopped being accepted straight away
+	if delay := time.Duration(a.Config.Server.ShutdownDrainDelay) * time.Second; delay > 0 {
+		logging.Logger.Info("Draining before shutting down", "delay", delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	var errs []error
+	if err := a.Echo.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain requests: %w", err))
+	}
+
+	if a.admin != nil {
+		if err := a.admin.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop admin server: %w", err))
+		}
+	}
+
+	// Only stopped once requests have finished, so none are left queuing
+	// emails
+	a.mailer.Stop()
+
+	a.stopWatchingConfig()
+	if a.certs != nil {
+		a.certs.Stop()
+	}
+	a.scheduler.Stop()
+	if a.monitor != nil {
+		a.monitor.Stop()
+	}
+
+	if err := a.repo.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close DB: %w", err))
+	}
+
+	logging.Logger.Info("Server stopped")
+
+	return stdErrors.Join(errs...)
+}
+
+// listen listens on the port and serves e in the background, over TLS if
+// it has a config, sending any error other than being shut down to a.errs
+func (a *App) listen(e *echo.Echo, port string, tlsConfig *tls.Config) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	e.Listener = listener
+
+	go func() {
+		if err := e.Start(""); err != nil && err != http.ErrServerClosed {
+			a.errs <- err
+		}
+	}()
+
+	return nil
+}
+
+// newAdminServer creates the server /metrics is served on when an admin
+// port is configured
+func newAdminServer() *echo.Echo {
+	admin := echo.New()
+	admin.HideBanner = true
+	admin.HidePort = true
+	admin.GET("/metrics", echo.WrapHandler(metrics.Handler()))
 
-	// Start the HTTP server, if it returns an error we will log it
-	log.Fatal(e.Start(fmt.Sprintf(":%s", config.Server.Port)))
+	return admin
 }
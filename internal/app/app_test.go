@@ -0,0 +1,214 @@
+package app_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/app"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+)
+
+var _ = Describe("App", func() {
+	var (
+		mockCtrl *gomock.Controller
+		mockRepo *mocks.MockIRepo
+		cfg      *config.Config
+		a        *app.App
+
+		// Closed once the slow handler has been entered, and to let it return
+		entered, release chan struct{}
+	)
+
+	// get requests the path from the running app
+	get := func(path string) (*http.Response, error) {
+		return http.Get(fmt.Sprintf("http://%s%s", a.Addr(), path))
+	}
+
+	BeforeEach(func() {
+		logging.Logger = mocks.NewMockLogger().Logger
+
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockIRepo(mockCtrl)
+		mockRepo.EXPECT().ApplyDueStatusChanges(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+
+		cfg = config.Default()
+		cfg.Server.Port = "0"
+		cfg.Server.ShutdownTimeout = 5
+		cfg.Server.ShutdownDrainDelay = 0
+
+		a = app.New(cfg, mockRepo)
+		a.Echo.HideBanner = true
+		a.Echo.HidePort = true
+
+		entered, release = make(chan struct{}), make(chan struct{})
+		a.Echo.GET("/slow", func(ctx echo.Context) error {
+			close(entered)
+			<-release
+			return ctx.String(http.StatusOK, "done")
+		})
+
+		Expect(a.Start()).To(Succeed())
+	})
+
+	It("should serve requests once started", func() {
+		resp, err := get("/healthz")
+
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp.Body.Close()
+
+		mockRepo.EXPECT().Close().Return(nil)
+		Expect(a.Stop(context.Background())).To(Succeed())
+	})
+
+	It("should report not ready and let in-flight requests finish before closing the DB", func() {
+		responses := make(chan *http.Response, 1)
+		go func() {
+			defer GinkgoRecover()
+			resp, err := get("/slow")
+			Expect(err).To(BeNil())
+			responses <- resp
+		}()
+		Eventually(entered).Should(BeClosed())
+
+		closed := make(chan struct{})
+		mockRepo.EXPECT().Close().DoAndReturn(func() error {
+			close(closed)
+			return nil
+		})
+
+		stopped := make(chan error, 1)
+		go func() { stopped <- a.Stop(context.Background()) }()
+
+		Eventually(a.Health.ShuttingDown).Should(BeTrue())
+		Consistently(closed, 50*time.Millisecond).ShouldNot(BeClosed())
+
+		close(release)
+
+		var resp *http.Response
+		Eventually(responses).Should(Receive(&resp))
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(string(body)).To(Equal("done"))
+
+		Eventually(stopped).Should(Receive(BeNil()))
+		Expect(closed).To(BeClosed())
+
+		// New connections are refused once stopped
+		_, err := get("/healthz")
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should keep serving requests for the drain delay after reporting not ready", func() {
+		a.Config.Server.ShutdownDrainDelay = 1
+		mockRepo.EXPECT().Ping(gomock.Any()).Return(nil).AnyTimes()
+		mockRepo.EXPECT().SchemaChangeDeployed(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+
+		closed := make(chan struct{})
+		mockRepo.EXPECT().Close().DoAndReturn(func() error {
+			close(closed)
+			return nil
+		})
+
+		stopped := make(chan error, 1)
+		go func() { stopped <- a.Stop(context.Background()) }()
+
+		Eventually(a.Health.ShuttingDown).Should(BeTrue())
+
+		resp, err := get("/readyz")
+		Expect(err).To(BeNil())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+
+		resp, err = get("/healthz")
+		Expect(err).To(BeNil())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(closed).NotTo(BeClosed())
+
+		Eventually(stopped, 3*time.Second).Should(Receive(BeNil()))
+		Expect(closed).To(BeClosed())
+	})
+
+	It("should cut the drain delay short once the context is done", func() {
+		a.Config.Server.ShutdownDrainDelay = 60
+
+		mockRepo.EXPECT().Close().Return(nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		stopped := make(chan error, 1)
+		go func() { stopped <- a.Stop(ctx) }()
+
+		Eventually(stopped, time.Second).Should(Receive())
+	})
+
+	It("should give up on in-flight requests once the drain timeout passes", func() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if resp, err := get("/slow"); err == nil {
+				resp.Body.Close()
+			}
+		}()
+		Eventually(entered).Should(BeClosed())
+
+		mockRepo.EXPECT().Close().Return(nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := a.Stop(ctx)
+
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+
+		// Let the request finish so it doesn't outlive the spec
+		close(release)
+		Eventually(done).Should(BeClosed())
+	})
+
+	Describe("WaitForShutdown", func() {
+		It("should stop the app when the context is done", func() {
+			mockRepo.EXPECT().Close().Return(nil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			Expect(a.WaitForShutdown(ctx)).To(Succeed())
+			Expect(a.Health.ShuttingDown()).To(BeTrue())
+		})
+
+		It("should stop the app when sent a shutdown signal", func() {
+			// SIGINT and SIGTERM would be caught by ginkgo's own handler
+			previous := app.ShutdownSignals
+			app.ShutdownSignals = []os.Signal{syscall.SIGUSR1}
+			defer func() { app.ShutdownSignals = previous }()
+
+			mockRepo.EXPECT().Close().Return(nil)
+
+			stopped := make(chan error, 1)
+			go func() { stopped <- a.WaitForShutdown(context.Background()) }()
+
+			// Keep signalling until the handler is registered, so the
+			// signal can't reach the process before it is
+			Eventually(func() <-chan error {
+				syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+				return stopped
+			}).WithPolling(10 * time.Millisecond).Should(Receive(BeNil()))
+		})
+	})
+})
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/features"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+)
+
+// ReloadSignals are the signals the config is reloaded on
+var ReloadSignals = []os.Signal{syscall.SIGHUP}
+
+// Reload loads the config again from the same file and environment
+// variables, then applies the changes to the reloadable settings without
+// restarting. Every change is logged, with changes to any other setting
+// logged as needing a restart and otherwise ignored.
+//
+// Returns an error, keeping the current config, if the config can't be
+// loaded or is invalid, so a mistake is never partly applied.
+func (a *App) Reload() error {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	reloaded, err := config.Load(a.Config.Path())
+	if err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	changes := reloaded.Diff(*a.loaded)
+	a.loaded = reloaded
+	if len(changes) == 0 {
+		logging.Logger.Info("Reloaded config, nothing changed")
+		return nil
+	}
+
+	runtime := a.runtime.WithReloaded(*reloaded)
+	a.apply(&runtime)
+	a.runtime = &runtime
+
+	for _, change := range changes {
+		if change.Reloadable {
+			logging.Logger.Info("Config changed",
+				"setting", change.Setting, "old", change.Old, "new", change.New)
+		} else {
+			logging.Logger.Warn("Config change needs a restart to take effect",
+				"setting", change.Setting, "old", change.Old, "new", change.New)
+		}
+	}
+
+	return nil
+}
+
+// apply applies the reloadable settings of the config. Each is swapped as
+// a whole, so requests see either the old or the new setting.
+func (a *App) apply(c *config.Config) {
+	// Already validated, so the level is known
+	logging.SetLevel(c.Logging.Level)
+	logging.SetRedaction(c.Logging.RedactFields, c.Logging.RedactAllow)
+	a.cors.Set(c.Server.CORS)
+	a.resetLimiter.SetLimit(
+		c.PasswordReset.RateLimit,
+		time.Duration(c.PasswordReset.RateLimitWindow)*time.Minute)
+	features.Set(c.Features)
+}
+
+// watchConfig reloads the config in the background whenever the process
+// is sent a ReloadSignal, or the config file changes if it is watched,
+// until stopWatchingConfig is called
+func (a *App) watchConfig() {
+	a.reloadStop = make(chan struct{})
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, ReloadSignals...)
+
+	path := a.Config.Path()
+	interval := time.Duration(a.Config.Server.ConfigWatchInterval) * time.Second
+	lastModified := modified(path)
+
+	a.reloadWG.Add(1)
+
+	go func() {
+		defer a.reloadWG.Done()
+		defer signal.Stop(signals)
+
+		// The file is polled rather than watched for events, as mounted
+		// config maps are updated by swapping a symlink
+		var changed <-chan time.Time
+		if path != "" && interval > 0 {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			changed = ticker.C
+		}
+
+		for {
+			select {
+			case <-signals:
+				logging.Logger.Info("Reloading config")
+			case <-changed:
+				m := modified(path)
+				if m.IsZero() || m.Equal(lastModified) {
+					continue
+				}
+				lastModified = m
+				logging.Logger.Info("Config file changed, reloading config", "path", path)
+			case <-a.reloadStop:
+				return
+			}
+
+			if err := a.Reload(); err != nil {
+				logging.Error("watchConfig", "failed to reload config, keeping the current config", err)
+			}
+		}
+	}()
+}
+
+// stopWatchingConfig stops reloading the config and waits for a reload in
+// progress to finish
+func (a *App) stopWatchingConfig() {
+	close(a.reloadStop)
+	a.reloadWG.Wait()
+}
+
+// modified returns when the file at the path was last modified, or the
+// zero time if it can't be read, e.g. while it is being replaced
+func modified(path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
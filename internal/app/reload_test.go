@@ -0,0 +1,204 @@
+package app_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/app"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/features"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+)
+
+var _ = Describe("Reload", func() {
+	var (
+		mockLogger mocks.MockLogger
+		mockRepo   *mocks.MockIRepo
+		path       string
+		a          *app.App
+	)
+
+	const initial = `
+server:
+  port: "0"
+  config_watch_interval: %d
+  cors:
+    allow_origins: ["https://app.example.com"]
+logging:
+  level: info
+`
+
+	writeConfig := func(contents string) {
+		Expect(os.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+	}
+
+	// allowedOrigin requests the app from the origin, returning the origin
+	// it is allowed for
+	allowedOrigin := func(origin string) string {
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/healthz", a.Addr()), nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).To(BeNil())
+		resp.Body.Close()
+
+		return resp.Header.Get(echo.HeaderAccessControlAllowOrigin)
+	}
+
+	// start starts the app with the config file watched every interval
+	start := func(watchInterval int) {
+		writeConfig(fmt.Sprintf(initial, watchInterval))
+
+		cfg, err := config.Load(path)
+		Expect(err).To(BeNil())
+
+		a = app.New(cfg, mockRepo)
+		a.Echo.HideBanner = true
+		a.Echo.HidePort = true
+		Expect(a.Start()).To(Succeed())
+	}
+
+	// stop stops the app, so the logs can be read once it stops reloading
+	stop := func() {
+		mockRepo.EXPECT().Close().Return(nil)
+		Expect(a.Stop(context.Background())).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		mockLogger = mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+
+		mockRepo = mocks.NewMockIRepo(gomock.NewController(GinkgoT()))
+		mockRepo.EXPECT().ApplyDueStatusChanges(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+
+		path = filepath.Join(GinkgoT().TempDir(), "config.yml")
+
+		// Set for every load, so reloading doesn't see it change
+		GinkgoT().Setenv("SHUTDOWN_DRAIN_DELAY", "0")
+
+		// SIGHUP would stop the test process if sent before the app is
+		// listening for it
+		previous := app.ReloadSignals
+		app.ReloadSignals = []os.Signal{syscall.SIGUSR2}
+		DeferCleanup(func() {
+			app.ReloadSignals = previous
+			logging.SetLevel("info")
+			features.Set(nil)
+		})
+	})
+
+	It("should apply the reloadable settings and log what changed when sent a reload signal", func() {
+		start(0)
+		Expect(allowedOrigin("https://admin.example.com")).To(BeEmpty())
+
+		writeConfig(`
+server:
+  port: "9000"
+  cors:
+    allow_origins: ["https://admin.example.com"]
+logging:
+  level: debug
+features:
+  bulk_import: true
+`)
+		Expect(syscall.Kill(os.Getpid(), syscall.SIGUSR2)).To(Succeed())
+
+		Eventually(func() string {
+			return allowedOrigin("https://admin.example.com")
+		}).Should(Equal("https://admin.example.com"))
+		Eventually(logging.Level).Should(Equal(slog.LevelDebug))
+		Expect(features.Enabled("bulk_import")).To(BeTrue())
+		Expect(allowedOrigin("https://app.example.com")).To(BeEmpty())
+
+		// The port can't change without a restart
+		Expect(a.Addr().String()).NotTo(HaveSuffix(":9000"))
+
+		stop()
+		logs := mockLogger.GetBufferValue()
+		Expect(logs).To(ContainSubstring(`"msg":"Config changed","setting":"logging.level","old":"info","new":"debug"`))
+		Expect(logs).To(ContainSubstring(`"msg":"Config changed","setting":"server.cors.allow_origins","old":"https://app.example.com","new":"https://admin.example.com"`))
+		Expect(logs).To(ContainSubstring(`"msg":"Config changed","setting":"features.bulk_import","old":"","new":"true"`))
+		Expect(logs).To(ContainSubstring(`"level":"WARN","msg":"Config change needs a restart to take effect","setting":"server.port","old":"0","new":"9000"`))
+	})
+
+	It("should only warn about a change needing a restart when it is made", func() {
+		start(0)
+
+		writeConfig(fmt.Sprintf(initial, 0) + "features:\n  bulk_import: true\n")
+		Expect(a.Reload()).To(Succeed())
+
+		writeConfig(`
+server:
+  port: "9000"
+  cors:
+    allow_origins: ["https://app.example.com"]
+logging:
+  level: info
+features:
+  bulk_import: true
+`)
+		Expect(a.Reload()).To(Succeed())
+		Expect(a.Reload()).To(Succeed())
+
+		stop()
+		logs := mockLogger.GetBufferValue()
+		Expect(strings.Count(logs, `"msg":"Config change needs a restart to take effect","setting":"server.port"`)).To(Equal(1))
+		Expect(strings.Count(logs, `"msg":"Config changed","setting":"features.bulk_import"`)).To(Equal(1))
+		Expect(logs).To(ContainSubstring(`"msg":"Reloaded config, nothing changed"`))
+	})
+
+	It("should reload the config when its file changes", func() {
+		start(1)
+
+		writeConfig(fmt.Sprintf(initial, 1) + "features:\n  bulk_import: true\n")
+
+		Eventually(func() bool {
+			return features.Enabled("bulk_import")
+		}, 5*time.Second).Should(BeTrue())
+
+		stop()
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"msg":"Config file changed, reloading config"`))
+	})
+
+	It("should keep the current config if the reloaded config is invalid", func() {
+		start(0)
+
+		writeConfig(`
+server:
+  cors:
+    allow_origins: ["https://admin.example.com"]
+logging:
+  level: verbose
+`)
+
+		err := a.Reload()
+
+		Expect(err).To(MatchError(ContainSubstring(`LOG_LEVEL must be one of debug, info, warn, error, got "verbose"`)))
+		Expect(allowedOrigin("https://app.example.com")).To(Equal("https://app.example.com"))
+		Expect(allowedOrigin("https://admin.example.com")).To(BeEmpty())
+
+		stop()
+	})
+
+	It("should log that nothing changed", func() {
+		start(0)
+
+		Expect(a.Reload()).To(Succeed())
+
+		stop()
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"msg":"Reloaded config, nothing changed"`))
+	})
+})
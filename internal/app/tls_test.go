@@ -0,0 +1,270 @@
+package app_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/app"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/identity"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+)
+
+var _ = Describe("TLS", func() {
+	var (
+		ca       *mocks.MockCA
+		mockRepo *mocks.MockIRepo
+		cfg      *config.Config
+		a        *app.App
+	)
+
+	// writeFile writes the contents to a file named name, returning its path
+	writeFile := func(dir string, name string, contents []byte) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, contents, 0600)).To(Succeed())
+		return path
+	}
+
+	// client returns a client trusting the CA, presenting a certificate
+	// issued to the common name by the issuer if it is set
+	client := func(issuer *mocks.MockCA, commonName string) *http.Client {
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.Cert)
+		tlsConfig := &tls.Config{RootCAs: roots}
+
+		if issuer != nil {
+			certPEM, keyPEM := issuer.Issue(pkix.Name{CommonName: commonName})
+			cert, err := tls.X509KeyPair(certPEM, keyPEM)
+			Expect(err).To(BeNil())
+			// Always presented, rather than only when the app lists the
+			// issuer as acceptable, so the app's verification is tested
+			tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return &cert, nil
+			}
+		}
+
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	}
+
+	// whoami requests the identity the app gave the client
+	whoami := func(c *http.Client) (string, error) {
+		port := a.Addr().(*net.TCPAddr).Port
+		resp, err := c.Get(fmt.Sprintf("https://localhost:%d/whoami", port))
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	// status requests the path, returning the status the app responded with
+	status := func(c *http.Client, path string) (int, error) {
+		port := a.Addr().(*net.TCPAddr).Port
+		resp, err := c.Get(fmt.Sprintf("https://localhost:%d%s", port, path))
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+
+		return resp.StatusCode, nil
+	}
+
+	// createUser creates a user claiming to be made by the actor in the
+	// header, returning the actor the change was recorded with
+	createUser := func(c *http.Client, actor string) string {
+		var recorded models.AuditInfo
+		mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, user models.User, info models.AuditInfo) (*models.User, error) {
+				recorded = info
+				return &user, nil
+			})
+
+		port := a.Addr().(*net.TCPAddr).Port
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("https://localhost:%d/users", port),
+			strings.NewReader(`{"user_name":"test","first_name":"Test","last_name":"User","email":"test@user.com","user_status":"A","department":"IT"}`))
+		Expect(err).To(BeNil())
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(constants.AuditActorHeader, actor)
+
+		resp, err := c.Do(req)
+		Expect(err).To(BeNil())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		return recorded.Actor
+	}
+
+	// start starts the app with its certificate issued by the CA, verifying
+	// client certificates against it too if clientAuth is set
+	start := func(clientAuth string) {
+		dir := GinkgoT().TempDir()
+		certPEM, keyPEM := ca.Issue(pkix.Name{CommonName: "localhost"})
+
+		cfg.Server.TLS.CertFile = writeFile(dir, "tls.crt", certPEM)
+		cfg.Server.TLS.KeyFile = writeFile(dir, "tls.key", keyPEM)
+		if clientAuth != "" {
+			cfg.Server.TLS.ClientCAFile = writeFile(dir, "ca.crt", ca.PEM)
+			cfg.Server.TLS.ClientAuth = clientAuth
+		}
+		Expect(cfg.Validate()).To(Succeed())
+
+		a = app.New(cfg, mockRepo)
+		a.Echo.HideBanner = true
+		a.Echo.HidePort = true
+		a.Echo.GET("/whoami", func(ctx echo.Context) error {
+			return ctx.String(http.StatusOK, identity.FromContext(ctx.Request().Context()))
+		})
+
+		Expect(a.Start()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		logging.Logger = mocks.NewMockLogger().Logger
+
+		mockRepo = mocks.NewMockIRepo(gomock.NewController(GinkgoT()))
+		mockRepo.EXPECT().ApplyDueStatusChanges(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+
+		ca = mocks.NewMockCA("Test CA")
+
+		cfg = config.Default()
+		cfg.Server.Port = "0"
+		cfg.Server.ConfigWatchInterval = 0
+		cfg.Server.ShutdownDrainDelay = 0
+	})
+
+	AfterEach(func() {
+		mockRepo.EXPECT().Close().Return(nil)
+		Expect(a.Stop(context.Background())).To(Succeed())
+	})
+
+	It("should serve over TLS", func() {
+		start("")
+
+		id, err := whoami(client(nil, ""))
+
+		Expect(err).To(BeNil())
+		Expect(id).To(BeEmpty())
+	})
+
+	It("should identify clients by their verified certificate", func() {
+		start("require")
+
+		id, err := whoami(client(ca, "billing-service"))
+
+		Expect(err).To(BeNil())
+		Expect(id).To(Equal("billing-service"))
+	})
+
+	It("should refuse clients without a certificate when they are required", func() {
+		start("require")
+
+		_, err := whoami(client(nil, ""))
+
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should refuse clients with a certificate from another CA", func() {
+		start("optional")
+
+		_, err := whoami(client(mocks.NewMockCA("Other CA"), "billing-service"))
+
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should serve clients without a certificate unidentified when they are optional", func() {
+		start("optional")
+
+		id, err := whoami(client(nil, ""))
+
+		Expect(err).To(BeNil())
+		Expect(id).To(BeEmpty())
+	})
+
+	Describe("with allowed clients", func() {
+		BeforeEach(func() {
+			cfg.Server.TLS.AllowedClients = []string{"billing-service"}
+		})
+
+		It("should serve an allowed client", func() {
+			start("optional")
+
+			id, err := whoami(client(ca, "billing-service"))
+
+			Expect(err).To(BeNil())
+			Expect(id).To(Equal("billing-service"))
+		})
+
+		It("should refuse a client with a verified certificate that isn't allowed", func() {
+			start("optional")
+
+			code, err := status(client(ca, "reporting-service"), "/whoami")
+
+			Expect(err).To(BeNil())
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should refuse a client without a certificate", func() {
+			start("optional")
+
+			code, err := status(client(nil, ""), "/whoami")
+
+			Expect(err).To(BeNil())
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should refuse the audit log to a client that isn't allowed", func() {
+			start("optional")
+
+			code, err := status(client(ca, "reporting-service"), "/audit")
+
+			Expect(err).To(BeNil())
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should leave health checks open to clients without a certificate", func() {
+			start("optional")
+
+			code, err := status(client(nil, ""), "/healthz")
+
+			Expect(err).To(BeNil())
+			Expect(code).To(Equal(http.StatusOK))
+		})
+	})
+
+	It("should record the certificate's identity as the actor, whatever the header claims", func() {
+		start("optional")
+
+		Expect(createUser(client(ca, "billing-service"), "admin")).To(Equal("billing-service"))
+	})
+
+	It("should record clients without a certificate as unauthenticated, whatever the header claims", func() {
+		start("optional")
+
+		Expect(createUser(client(nil, ""), "admin")).To(Equal(constants.AuditActorUnauthenticated))
+	})
+
+	It("should record the actor in the header when clients aren't verified", func() {
+		start("")
+
+		Expect(createUser(client(nil, ""), "admin")).To(Equal("admin"))
+	})
+})
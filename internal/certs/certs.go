@@ -0,0 +1,194 @@
+// package certs provides the TLS config the API is served with, reloading
+// the certificate and CA files when they change so rotated certificates
+// are served without a restart.
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+)
+
+// versions are the TLS versions by their config names
+var versions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Reloader loads the TLS config from the files in the config, and
+// reloads it whenever they change once started.
+type Reloader struct {
+	Config   config.TLSConfig
+	Interval time.Duration
+
+	current atomic.Pointer[tls.Config]
+	// When each file was last modified as of the last load
+	modified map[string]time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewReloader creates a Reloader for the config, loading its files.
+//
+// Returns an error if the files can't be loaded.
+func NewReloader(c config.TLSConfig) (*Reloader, error) {
+	r := &Reloader{
+		Config:   c,
+		Interval: time.Duration(c.ReloadInterval) * time.Second,
+	}
+
+	if err := r.Load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Load loads the certificate, key and client CA files, and serves them to
+// every handshake from now on.
+//
+// Returns an error, continuing to serve the files last loaded, if they
+// can't be loaded.
+func (r *Reloader) Load() error {
+	modified := r.modifiedFiles()
+
+	cert, err := tls.LoadX509KeyPair(r.Config.CertFile, r.Config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	// Parsed to log, as it is only parsed by LoadX509KeyPair in newer Go
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	cert.Leaf = leaf
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   versions[r.Config.MinVersion],
+	}
+
+	if r.Config.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.Config.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS client CA bundle: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("failed to load TLS client CA bundle: no certificates found")
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		if r.Config.ClientAuth == "optional" {
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	r.current.Store(tlsConfig)
+	r.modified = modified
+
+	logging.Logger.Info("Loaded TLS certificate",
+		"subject", cert.Leaf.Subject.String(),
+		"expires_at", cert.Leaf.NotAfter)
+
+	return nil
+}
+
+// TLSConfig returns the config to serve with, which uses the files last
+// loaded for every handshake.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
+
+// Start checks the files for changes every interval in the background,
+// reloading them when they do, until Stop is called. Does nothing if the
+// interval is 0.
+func (r *Reloader) Start() {
+	r.stop = make(chan struct{})
+
+	if r.Interval <= 0 {
+		return
+	}
+
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.reloadIfChanged()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops checking the files and waits for a reload in progress to
+// finish.
+func (r *Reloader) Stop() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+// reloadIfChanged reloads the files if any were modified since they were
+// last loaded. Failures are logged, as the files may be part way through
+// being replaced, and retried on the next check.
+func (r *Reloader) reloadIfChanged() {
+	modified := r.modifiedFiles()
+	changed := false
+	for file, m := range modified {
+		if !m.Equal(r.modified[file]) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	if err := r.Load(); err != nil {
+		logging.Error("reloadIfChanged", "failed to reload TLS certificate, serving the last one loaded", err)
+	}
+}
+
+// modifiedFiles returns when each file was last modified, or the zero
+// time for files that can't be read
+func (r *Reloader) modifiedFiles() map[string]time.Time {
+	modified := map[string]time.Time{}
+
+	for _, file := range []string{r.Config.CertFile, r.Config.KeyFile, r.Config.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+
+		if info, err := os.Stat(file); err == nil {
+			modified[file] = info.ModTime()
+		} else {
+			modified[file] = time.Time{}
+		}
+	}
+
+	return modified
+}
@@ -0,0 +1,127 @@
+package certs_test
+
+import (
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/certs"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+)
+
+var _ = Describe("Reloader", func() {
+	var (
+		ca        *mocks.MockCA
+		tlsConfig config.TLSConfig
+	)
+
+	// writeCert issues a certificate for the common name and writes it and
+	// its key to the configured files, marking them modified at the time
+	writeCert := func(commonName string, modified time.Time) {
+		cert, key := ca.Issue(pkix.Name{CommonName: commonName})
+		Expect(os.WriteFile(tlsConfig.CertFile, cert, 0600)).To(Succeed())
+		Expect(os.WriteFile(tlsConfig.KeyFile, key, 0600)).To(Succeed())
+		Expect(os.Chtimes(tlsConfig.CertFile, modified, modified)).To(Succeed())
+		Expect(os.Chtimes(tlsConfig.KeyFile, modified, modified)).To(Succeed())
+	}
+
+	// served returns the config served to the next handshake
+	served := func(reloader *certs.Reloader) *tls.Config {
+		served, err := reloader.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+		Expect(err).To(BeNil())
+		return served
+	}
+
+	// servedName returns the common name of the certificate served
+	servedName := func(reloader *certs.Reloader) string {
+		return served(reloader).Certificates[0].Leaf.Subject.CommonName
+	}
+
+	BeforeEach(func() {
+		logging.Logger = mocks.NewMockLogger().Logger
+
+		ca = mocks.NewMockCA("Test CA")
+
+		dir := GinkgoT().TempDir()
+		tlsConfig = config.Default().Server.TLS
+		tlsConfig.CertFile = filepath.Join(dir, "tls.crt")
+		tlsConfig.KeyFile = filepath.Join(dir, "tls.key")
+
+		writeCert("first", time.Now().Add(-time.Minute))
+	})
+
+	It("should serve the certificate with the minimum version", func() {
+		tlsConfig.MinVersion = "1.3"
+
+		reloader, err := certs.NewReloader(tlsConfig)
+		Expect(err).To(BeNil())
+
+		Expect(servedName(reloader)).To(Equal("first"))
+		Expect(served(reloader).MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		Expect(served(reloader).ClientAuth).To(Equal(tls.NoClientCert))
+	})
+
+	It("should return an error if the certificate can't be loaded", func() {
+		tlsConfig.KeyFile = filepath.Join(GinkgoT().TempDir(), "missing.key")
+
+		_, err := certs.NewReloader(tlsConfig)
+
+		Expect(err).To(MatchError(ContainSubstring("failed to load TLS certificate")))
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+
+	DescribeTable("should verify client certificates against the CA bundle",
+		func(clientAuth string, expected tls.ClientAuthType) {
+			tlsConfig.ClientCAFile = filepath.Join(GinkgoT().TempDir(), "ca.crt")
+			tlsConfig.ClientAuth = clientAuth
+			Expect(os.WriteFile(tlsConfig.ClientCAFile, ca.PEM, 0600)).To(Succeed())
+
+			reloader, err := certs.NewReloader(tlsConfig)
+			Expect(err).To(BeNil())
+
+			Expect(served(reloader).ClientAuth).To(Equal(expected))
+			Expect(served(reloader).ClientCAs.Subjects()).To(HaveLen(1))
+		},
+		Entry("requiring them", "require", tls.RequireAndVerifyClientCert),
+		Entry("only when presented", "optional", tls.VerifyClientCertIfGiven),
+	)
+
+	It("should return an error if the CA bundle has no certificates", func() {
+		tlsConfig.ClientCAFile = filepath.Join(GinkgoT().TempDir(), "ca.crt")
+		Expect(os.WriteFile(tlsConfig.ClientCAFile, []byte("not a certificate"), 0600)).To(Succeed())
+
+		_, err := certs.NewReloader(tlsConfig)
+
+		Expect(err).To(MatchError("failed to load TLS client CA bundle: no certificates found"))
+	})
+
+	It("should serve the new certificate once the files change", func() {
+		reloader, err := certs.NewReloader(tlsConfig)
+		Expect(err).To(BeNil())
+		reloader.Interval = 10 * time.Millisecond
+
+		reloader.Start()
+		defer reloader.Stop()
+
+		writeCert("second", time.Now())
+
+		Eventually(func() string { return servedName(reloader) }).Should(Equal("second"))
+	})
+
+	It("should keep serving the last certificate if the files can't be loaded", func() {
+		reloader, err := certs.NewReloader(tlsConfig)
+		Expect(err).To(BeNil())
+
+		Expect(os.WriteFile(tlsConfig.KeyFile, []byte("not a key"), 0600)).To(Succeed())
+
+		Expect(reloader.Load()).To(MatchError(ContainSubstring("failed to load TLS certificate")))
+		Expect(servedName(reloader)).To(Equal("first"))
+	})
+})
@@ -1,76 +1,576 @@
 package config
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
-	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/secrets"
 )
 
+// FileEnv is the environment variable the path of the config file is
+// read from
+const FileEnv = "CONFIG_FILE"
+
 type DatabaseConfig struct {
-	Host     string
-	Username string
-	Password string
-	Name     string
-	Port     string
-	SSLMode  string
+	// Full connection URL or DSN. When set, it is used instead of the
+	// connection fields below.
+	URL      string `yaml:"url" toml:"url"`
+	Host     string `yaml:"host" toml:"host"`
+	Username string `yaml:"username" toml:"username"`
+	Password string `yaml:"password" toml:"password"`
+	Name     string `yaml:"name" toml:"name"`
+	Port     string `yaml:"port" toml:"port"`
+	SSLMode  string `yaml:"ssl_mode" toml:"ssl_mode"`
+	// Files URL and Password are read from instead, e.g. secrets mounted
+	// by Docker or Kubernetes
+	URLFile      string `yaml:"url_file" toml:"url_file"`
+	PasswordFile string `yaml:"password_file" toml:"password_file"`
+	// How often in seconds PasswordFile is reread, so new connections use
+	// rotated credentials without a restart. 0 only reads it on startup.
+	PasswordReloadInterval int `yaml:"password_reload_interval" toml:"password_reload_interval"`
 	// Maximum number of idle connections allowed
-	MaxIdleConnections int
+	MaxIdleConnections int `yaml:"max_idle_connections" toml:"max_idle_connections"`
 	// How long in minutes idle connections will stick around
 	// before they get terminated
-	ConnectionMaxIdleTime int
+	ConnectionMaxIdleTime int `yaml:"connection_max_idle_time" toml:"connection_max_idle_time"`
+	// Maximum number of open connections allowed. 0 allows any number.
+	MaxOpenConnections int `yaml:"max_open_connections" toml:"max_open_connections"`
+	// How long in minutes a connection is reused for before it is
+	// replaced. 0 reuses connections indefinitely.
+	ConnectionMaxLifetime int `yaml:"connection_max_lifetime" toml:"connection_max_lifetime"`
+	// How long in seconds Postgres lets a statement run before cancelling
+	// it. 0 leaves the server's setting.
+	StatementTimeout int `yaml:"statement_timeout" toml:"statement_timeout"`
+	// Name the connections are reported under in pg_stat_activity
+	ApplicationName string `yaml:"application_name" toml:"application_name"`
+	// Schemas unqualified names are looked up in. Empty leaves the
+	// server's setting.
+	SearchPath string `yaml:"search_path" toml:"search_path"`
+	// How long in seconds a single repo call can spend querying the DB
+	// before it is cancelled. 0 disables the timeout.
+	QueryTimeout int `yaml:"query_timeout" toml:"query_timeout"`
+	// How long in seconds to keep retrying to connect on startup before
+	// giving up. 0 keeps retrying until connected.
+	ConnectMaxWait int `yaml:"connect_max_wait" toml:"connect_max_wait"`
+	// How long in milliseconds to wait after the first failed attempt to
+	// connect, doubling after every attempt up to ConnectRetryMax
+	ConnectRetryInitial int `yaml:"connect_retry_initial" toml:"connect_retry_initial"`
+	// Longest wait in milliseconds between attempts to connect
+	ConnectRetryMax int `yaml:"connect_retry_max" toml:"connect_retry_max"`
+	// How often in seconds the connection is checked after startup so its
+	// loss is logged and reported by readiness. 0 disables the check.
+	MonitorInterval int `yaml:"monitor_interval" toml:"monitor_interval"`
 }
 
 type ServerConfig struct {
-	Port string
+	Port string `yaml:"port" toml:"port"`
+	// Format errors are rendered in when the client does not ask for
+	// application/problem+json. Either "default" or "problem".
+	ErrorFormat string `yaml:"error_format" toml:"error_format"`
+	// How long in seconds in-flight requests are given to finish once
+	// the server is asked to shut down
+	ShutdownTimeout int `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// How long in seconds the server keeps accepting requests after
+	// reporting itself as not ready on shutdown, so load balancers stop
+	// routing to it first
+	ShutdownDrainDelay int `yaml:"shutdown_drain_delay" toml:"shutdown_drain_delay"`
+	// How often in seconds the config file is checked for changes, which
+	// are then reloaded. 0 only reloads the config on SIGHUP.
+	ConfigWatchInterval int `yaml:"config_watch_interval" toml:"config_watch_interval"`
+	// Policy for cross-origin requests from browsers
+	CORS CORSConfig `yaml:"cors" toml:"cors"`
+	// Serving the API over TLS, for when it isn't terminated in front of
+	// the server
+	TLS TLSConfig `yaml:"tls" toml:"tls"`
+}
+
+type TLSConfig struct {
+	// PEM encoded certificate chain and private key files the API is
+	// served with. When empty, the API is served over plain HTTP.
+	CertFile string `yaml:"cert_file" toml:"cert_file"`
+	KeyFile  string `yaml:"key_file" toml:"key_file"`
+	// Oldest TLS version accepted. Either "1.2" or "1.3".
+	MinVersion string `yaml:"min_version" toml:"min_version"`
+	// PEM encoded CA bundle client certificates are verified against.
+	// When set, the subject of a client's certificate is its identity.
+	ClientCAFile string `yaml:"client_ca_file" toml:"client_ca_file"`
+	// Whether clients must present a certificate when ClientCAFile is
+	// set. Either "require" or "optional", which only verifies
+	// certificates that are presented.
+	ClientAuth string `yaml:"client_auth" toml:"client_auth"`
+	// Identities of the clients allowed to call the API when ClientCAFile
+	// is set. When empty, any client with a verified certificate is.
+	AllowedClients []string `yaml:"allowed_clients" toml:"allowed_clients"`
+	// How often in seconds the files are checked for changes, which are
+	// then reloaded. 0 only loads them on startup.
+	ReloadInterval int `yaml:"reload_interval" toml:"reload_interval"`
+}
+
+// Enabled reports whether the API is served over TLS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+type CORSConfig struct {
+	// Origins allowed to make cross-origin requests, e.g.
+	// "https://app.example.com". "https://*.example.com" allows any
+	// subdomain of example.com, and "*" allows any origin.
+	AllowOrigins []string `yaml:"allow_origins" toml:"allow_origins"`
+	// Methods allowed in cross-origin requests
+	AllowMethods []string `yaml:"allow_methods" toml:"allow_methods"`
+	// Headers allowed in cross-origin requests
+	AllowHeaders []string `yaml:"allow_headers" toml:"allow_headers"`
+	// Whether cross-origin requests can include cookies and auth headers
+	AllowCredentials bool `yaml:"allow_credentials" toml:"allow_credentials"`
+	// How long in seconds browsers can cache the response to a preflight
+	// request. 0 leaves it to the browser.
+	MaxAge int `yaml:"max_age" toml:"max_age"`
+}
+
+type LoggingConfig struct {
+	// Lowest level that will be logged. One of "debug", "info", "warn"
+	// or "error".
+	Level string `yaml:"level" toml:"level"`
+	// Format log lines are written in. Either "json" or "text".
+	Format string `yaml:"format" toml:"format"`
+	// Fields masked in log lines in addition to the defaults, e.g.
+	// "department"
+	RedactFields []string `yaml:"redact_fields" toml:"redact_fields"`
+	// Fields left unmasked in log lines while Level is "debug"
+	RedactAllow []string `yaml:"redact_allow" toml:"redact_allow"`
+}
+
+type MetricsConfig struct {
+	// Whether /metrics is served
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Port of a separate admin server /metrics is served on. When empty,
+	// /metrics is served on the same port as the API.
+	Port string `yaml:"port" toml:"port"`
+}
+
+type TracingConfig struct {
+	// Where spans are exported to. One of "none", "stdout", "file" or
+	// "otlp".
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	// Path of the file spans are written to by the "file" exporter
+	File string `yaml:"file" toml:"file"`
+	// Endpoint of the collector spans are sent to by the "otlp" exporter,
+	// e.g. "localhost:4318". When empty, the OTLP exporter's default and
+	// OTEL_EXPORTER_OTLP_* env vars are used.
+	OTLPEndpoint string `yaml:"otlp_endpoint" toml:"otlp_endpoint"`
+	// Name of this service on the exported spans
+	ServiceName string `yaml:"service_name" toml:"service_name"`
+}
+
+type HealthConfig struct {
+	// How long in seconds the readiness check can spend checking the DB
+	// before reporting it down
+	Timeout int `yaml:"timeout" toml:"timeout"`
+}
+
+type MailerConfig struct {
+	// SMTP host used to send emails. When empty, emails are
+	// written to the logs instead of being sent.
+	Host     string `yaml:"host" toml:"host"`
+	Port     string `yaml:"port" toml:"port"`
+	Username string `yaml:"username" toml:"username"`
+	Password string `yaml:"password" toml:"password"`
+	// File Password is read from instead on startup
+	PasswordFile string `yaml:"password_file" toml:"password_file"`
+	// Address that emails will be sent from
+	From string `yaml:"from" toml:"from"`
+}
+
+type PasswordResetConfig struct {
+	// URL of the front end page that completes the reset. The token
+	// will be appended to it as a query param.
+	URL string `yaml:"url" toml:"url"`
+	// How long in minutes a reset token is valid for
+	TokenTTL int `yaml:"token_ttl" toml:"token_ttl"`
+	// Maximum number of reset requests allowed per email address
+	// within the RateLimitWindow
+	RateLimit int `yaml:"rate_limit" toml:"rate_limit"`
+	// Window in minutes that RateLimit applies to
+	RateLimitWindow int `yaml:"rate_limit_window" toml:"rate_limit_window"`
+	// Minimum time in milliseconds a reset request takes to respond,
+	// so response times don't reveal whether an email exists
+	MinResponseTime int `yaml:"min_response_time" toml:"min_response_time"`
+}
+
+type SchedulerConfig struct {
+	// How often in seconds pending scheduled status changes are checked
+	// and applied
+	Interval int `yaml:"interval" toml:"interval"`
+}
+
+type UsersConfig struct {
+	// The statuses each user status is allowed to change to. Statuses
+	// missing from it can't be changed from.
+	StatusTransitions models.StatusTransitions `yaml:"status_transitions" toml:"status_transitions"`
 }
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Server        ServerConfig        `yaml:"server" toml:"server"`
+	Database      DatabaseConfig      `yaml:"database" toml:"database"`
+	Logging       LoggingConfig       `yaml:"logging" toml:"logging"`
+	Metrics       MetricsConfig       `yaml:"metrics" toml:"metrics"`
+	Tracing       TracingConfig       `yaml:"tracing" toml:"tracing"`
+	Health        HealthConfig        `yaml:"health" toml:"health"`
+	Mailer        MailerConfig        `yaml:"mailer" toml:"mailer"`
+	PasswordReset PasswordResetConfig `yaml:"password_reset" toml:"password_reset"`
+	Scheduler     SchedulerConfig     `yaml:"scheduler" toml:"scheduler"`
+	Users         UsersConfig         `yaml:"users" toml:"users"`
+	// Feature flags by name, which can be turned on and off while running
+	Features map[string]bool `yaml:"features" toml:"features"`
+
+	// Path of the config file the config was loaded from, which it is
+	// reloaded from
+	path string
 }
 
-func New() *Config {
+// Default returns the config used for every setting not set by the config
+// file or environment variables
+func Default() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("PORT", constants.ServerPortDefault),
+			Port:                constants.ServerPortDefault,
+			ErrorFormat:         constants.ServerErrorFormatDefault,
+			ShutdownTimeout:     constants.ServerShutdownTimeoutDefault,
+			ShutdownDrainDelay:  constants.ServerShutdownDrainDelayDefault,
+			ConfigWatchInterval: constants.ServerConfigWatchIntervalDefault,
+			CORS: CORSConfig{
+				AllowOrigins: []string{constants.CORSAllowOriginsDefault},
+				AllowMethods: strings.Split(constants.CORSAllowMethodsDefault, ","),
+				AllowHeaders: strings.Split(constants.CORSAllowHeadersDefault, ","),
+				MaxAge:       constants.CORSMaxAgeDefault,
+			},
+			TLS: TLSConfig{
+				MinVersion:     constants.TLSMinVersionDefault,
+				ClientAuth:     constants.TLSClientAuthDefault,
+				ReloadInterval: constants.TLSReloadIntervalDefault,
+			},
 		},
 		Database: DatabaseConfig{
-			Host:                  getEnv("POSTGRES_HOSTNAME", constants.DBHostDefault),
-			Username:              getEnv("POSTGRES_USER", constants.DBUsernameDefault),
-			Password:              getEnv("POSTGRES_PASSWORD", constants.DBPasswordDefault),
-			Name:                  getEnv("POSTGRES_DB", constants.DBNameDefault),
-			Port:                  getEnv("POSTGRES_PORT", constants.DBPortDefault),
-			SSLMode:               getEnv("POSTGRES_SSL", constants.DBSSLModeDefault),
-			MaxIdleConnections:    getEnvInt("POSTGRES_MAX_IDLE_CONNS", constants.DBMaxIdleConnectionsDefault),
-			ConnectionMaxIdleTime: getEnvInt("POSTGRES_CONN_MAX_IDLE_TIME", constants.DBConnectionMaxIdleTime),
+			Host:                   constants.DBHostDefault,
+			Username:               constants.DBUsernameDefault,
+			Password:               constants.DBPasswordDefault,
+			Name:                   constants.DBNameDefault,
+			Port:                   constants.DBPortDefault,
+			SSLMode:                constants.DBSSLModeDefault,
+			MaxIdleConnections:     constants.DBMaxIdleConnectionsDefault,
+			ConnectionMaxIdleTime:  constants.DBConnectionMaxIdleTime,
+			MaxOpenConnections:     constants.DBMaxOpenConnectionsDefault,
+			ConnectionMaxLifetime:  constants.DBConnectionMaxLifetimeDefault,
+			StatementTimeout:       constants.DBStatementTimeoutDefault,
+			ApplicationName:        constants.DBApplicationNameDefault,
+			QueryTimeout:           constants.DBQueryTimeoutDefault,
+			ConnectMaxWait:         constants.DBConnectMaxWaitDefault,
+			ConnectRetryInitial:    constants.DBConnectRetryInitialDefault,
+			ConnectRetryMax:        constants.DBConnectRetryMaxDefault,
+			MonitorInterval:        constants.DBMonitorIntervalDefault,
+			PasswordReloadInterval: constants.DBPasswordReloadIntervalDefault,
+		},
+		Logging: LoggingConfig{
+			Level:  constants.LogLevelDefault,
+			Format: constants.LogFormatDefault,
+		},
+		Metrics: MetricsConfig{
+			Enabled: constants.MetricsEnabledDefault,
+			Port:    constants.MetricsPortDefault,
+		},
+		Tracing: TracingConfig{
+			Exporter:    constants.TracingExporterDefault,
+			File:        constants.TracingFileDefault,
+			ServiceName: constants.TracingServiceNameDefault,
+		},
+		Health: HealthConfig{
+			Timeout: constants.HealthCheckTimeoutDefault,
+		},
+		Mailer: MailerConfig{
+			Host:     constants.MailerHostDefault,
+			Port:     constants.MailerPortDefault,
+			Username: constants.MailerUsernameDefault,
+			Password: constants.MailerPasswordDefault,
+			From:     constants.MailerFromDefault,
+		},
+		PasswordReset: PasswordResetConfig{
+			URL:             constants.PasswordResetURLDefault,
+			TokenTTL:        constants.PasswordResetTokenTTLDefault,
+			RateLimit:       constants.PasswordResetRateLimitDefault,
+			RateLimitWindow: constants.PasswordResetRateLimitWindowDefault,
+			MinResponseTime: constants.PasswordResetMinResponseTimeDefault,
+		},
+		Scheduler: SchedulerConfig{
+			Interval: constants.SchedulerIntervalDefault,
+		},
+		Users: UsersConfig{
+			StatusTransitions: models.DefaultStatusTransitions.Clone(),
 		},
+		Features: map[string]bool{},
+	}
+}
+
+// Load builds the config from the defaults, overlaid by the YAML or TOML
+// config file at the path if there is one, then by environment variables.
+// Secrets with a file set are then read from it.
+//
+// Returns an error, rather than falling back to the defaults, if the file
+// or an environment variable can't be parsed, or if any setting is invalid.
+func Load(path string) (*Config, error) {
+	c := Default()
+	c.path = path
+
+	if path != "" {
+		if err := c.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// All are checked so every mistake is reported at once
+	if err := errors.Join(c.loadEnv(), c.loadSecretFiles(), c.Validate()); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Path returns the path of the config file the config was loaded from,
+// or "" if it was only loaded from environment variables
+func (c *Config) Path() string {
+	return c.path
+}
+
+// loadEnv overlays the config with the environment variables that are set
+//
+// Returns an error listing every environment variable that can't be parsed.
+func (c *Config) loadEnv() error {
+	env := &envReader{}
+
+	env.String("PORT", &c.Server.Port)
+	env.String("ERROR_FORMAT", &c.Server.ErrorFormat)
+	env.Int("SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+	env.Int("SHUTDOWN_DRAIN_DELAY", &c.Server.ShutdownDrainDelay)
+	env.Int("CONFIG_WATCH_INTERVAL", &c.Server.ConfigWatchInterval)
+	// Comma separated origins, methods and headers
+	env.List("CORS_ALLOW_ORIGINS", &c.Server.CORS.AllowOrigins)
+	env.List("CORS_ALLOW_METHODS", &c.Server.CORS.AllowMethods)
+	env.List("CORS_ALLOW_HEADERS", &c.Server.CORS.AllowHeaders)
+	env.Bool("CORS_ALLOW_CREDENTIALS", &c.Server.CORS.AllowCredentials)
+	env.Int("CORS_MAX_AGE", &c.Server.CORS.MaxAge)
+	env.String("TLS_CERT_FILE", &c.Server.TLS.CertFile)
+	env.String("TLS_KEY_FILE", &c.Server.TLS.KeyFile)
+	env.String("TLS_MIN_VERSION", &c.Server.TLS.MinVersion)
+	env.String("TLS_CLIENT_CA_FILE", &c.Server.TLS.ClientCAFile)
+	env.String("TLS_CLIENT_AUTH", &c.Server.TLS.ClientAuth)
+	// Comma separated identities
+	env.List("TLS_ALLOWED_CLIENTS", &c.Server.TLS.AllowedClients)
+	env.Int("TLS_RELOAD_INTERVAL", &c.Server.TLS.ReloadInterval)
+	env.String("DATABASE_URL", &c.Database.URL)
+	env.String("POSTGRES_HOSTNAME", &c.Database.Host)
+	env.String("POSTGRES_USER", &c.Database.Username)
+	env.String("POSTGRES_PASSWORD", &c.Database.Password)
+	env.String("DATABASE_URL_FILE", &c.Database.URLFile)
+	env.String("POSTGRES_PASSWORD_FILE", &c.Database.PasswordFile)
+	env.Int("POSTGRES_PASSWORD_RELOAD_INTERVAL", &c.Database.PasswordReloadInterval)
+	env.String("POSTGRES_DB", &c.Database.Name)
+	env.String("POSTGRES_PORT", &c.Database.Port)
+	env.String("POSTGRES_SSL", &c.Database.SSLMode)
+	env.Int("POSTGRES_MAX_IDLE_CONNS", &c.Database.MaxIdleConnections)
+	env.Int("POSTGRES_CONN_MAX_IDLE_TIME", &c.Database.ConnectionMaxIdleTime)
+	env.Int("POSTGRES_MAX_OPEN_CONNS", &c.Database.MaxOpenConnections)
+	env.Int("POSTGRES_CONN_MAX_LIFETIME", &c.Database.ConnectionMaxLifetime)
+	env.Int("POSTGRES_STATEMENT_TIMEOUT", &c.Database.StatementTimeout)
+	env.String("POSTGRES_APPLICATION_NAME", &c.Database.ApplicationName)
+	env.String("POSTGRES_SEARCH_PATH", &c.Database.SearchPath)
+	env.Int("POSTGRES_QUERY_TIMEOUT", &c.Database.QueryTimeout)
+	env.Int("POSTGRES_CONNECT_MAX_WAIT", &c.Database.ConnectMaxWait)
+	env.Int("POSTGRES_CONNECT_RETRY_INITIAL", &c.Database.ConnectRetryInitial)
+	env.Int("POSTGRES_CONNECT_RETRY_MAX", &c.Database.ConnectRetryMax)
+	env.Int("POSTGRES_MONITOR_INTERVAL", &c.Database.MonitorInterval)
+	env.String("LOG_LEVEL", &c.Logging.Level)
+	env.String("LOG_FORMAT", &c.Logging.Format)
+	// Comma separated field names
+	env.List("LOG_REDACT_FIELDS", &c.Logging.RedactFields)
+	env.List("LOG_REDACT_ALLOW", &c.Logging.RedactAllow)
+	env.Bool("METRICS_ENABLED", &c.Metrics.Enabled)
+	env.String("METRICS_PORT", &c.Metrics.Port)
+	env.String("TRACING_EXPORTER", &c.Tracing.Exporter)
+	env.String("TRACING_FILE", &c.Tracing.File)
+	env.String("OTEL_EXPORTER_OTLP_ENDPOINT", &c.Tracing.OTLPEndpoint)
+	env.String("OTEL_SERVICE_NAME", &c.Tracing.ServiceName)
+	env.Int("HEALTH_CHECK_TIMEOUT", &c.Health.Timeout)
+	env.String("SMTP_HOST", &c.Mailer.Host)
+	env.String("SMTP_PORT", &c.Mailer.Port)
+	env.String("SMTP_USERNAME", &c.Mailer.Username)
+	env.String("SMTP_PASSWORD", &c.Mailer.Password)
+	env.String("SMTP_PASSWORD_FILE", &c.Mailer.PasswordFile)
+	env.String("SMTP_FROM", &c.Mailer.From)
+	env.String("PASSWORD_RESET_URL", &c.PasswordReset.URL)
+	env.Int("PASSWORD_RESET_TOKEN_TTL", &c.PasswordReset.TokenTTL)
+	env.Int("PASSWORD_RESET_RATE_LIMIT", &c.PasswordReset.RateLimit)
+	env.Int("PASSWORD_RESET_RATE_LIMIT_WINDOW", &c.PasswordReset.RateLimitWindow)
+	env.Int("PASSWORD_RESET_MIN_RESPONSE_TIME", &c.PasswordReset.MinResponseTime)
+	env.Int("SCHEDULER_INTERVAL", &c.Scheduler.Interval)
+	// Comma separated statuses with the ones they can change to, e.g. "I=A|T,A=I|T"
+	env.ListMap("USER_STATUS_TRANSITIONS", (*map[string][]string)(&c.Users.StatusTransitions))
+	// Comma separated flags, e.g. "bulk_import,audit_export=false"
+	env.Flags("FEATURE_FLAGS", &c.Features)
+
+	return errors.Join(env.errs...)
+}
+
+// loadSecretFiles sets the secrets that have a file set to its contents,
+// following the _FILE convention of Docker images, so they don't have to be
+// set in plain environment variables
+//
+// Returns an error listing every file that can't be read.
+func (c *Config) loadSecretFiles() error {
+	var errs []error
+
+	for _, secret := range []struct {
+		key  string
+		file string
+		val  *string
+	}{
+		{"DATABASE_URL_FILE", c.Database.URLFile, &c.Database.URL},
+		{"POSTGRES_PASSWORD_FILE", c.Database.PasswordFile, &c.Database.Password},
+		{"SMTP_PASSWORD_FILE", c.Mailer.PasswordFile, &c.Mailer.Password},
+	} {
+		if secret.file == "" {
+			continue
+		}
+
+		value, err := secrets.File{Path: secret.file}.Secret(context.Background())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", secret.key, err))
+			continue
+		}
+		*secret.val = value
+	}
+
+	return errors.Join(errs...)
+}
+
+// envReader reads environment variables over the config's values,
+// collecting an error for every one that can't be parsed
+type envReader struct {
+	errs []error
+}
+
+// String sets val to the environment variable for the key, if it is set
+func (r *envReader) String(key string, val *string) {
+	if v, exists := os.LookupEnv(key); exists {
+		*val = v
+	}
+}
+
+// Int sets val to the environment variable for the key converted to an
+// integer, if it is set
+func (r *envReader) Int(key string, val *int) {
+	if v, exists := os.LookupEnv(key); exists {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			r.errs = append(r.errs, fmt.Errorf("%s must be an integer, got %q", key, v))
+			return
+		}
+		*val = i
 	}
 }
 
-// GetEnv attempts to retrieve the environment variable for the key param
-// If one does not exist, returns the defaultVal
-func getEnv(key string, defaultVal string) string {
-	if val, exists := os.LookupEnv(key); exists {
-		return val
+// Bool sets val to the environment variable for the key converted to a
+// bool, if it is set
+func (r *envReader) Bool(key string, val *bool) {
+	if v, exists := os.LookupEnv(key); exists {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			r.errs = append(r.errs, fmt.Errorf("%s must be true or false, got %q", key, v))
+			return
+		}
+		*val = b
+	}
+}
+
+// List sets val to the environment variable for the key split on commas,
+// trimming the space around each value and dropping empty values, if it
+// is set
+func (r *envReader) List(key string, val *[]string) {
+	v, exists := os.LookupEnv(key)
+	if !exists {
+		return
 	}
 
-	return defaultVal
+	list := []string{}
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	*val = list
 }
 
-// GetEnvInt attempts to retrieve the environment variable for the key param and tries
-// to convert it to an integer
-// If one does not exist, or it fails to convert to an integer, it returns the defaultVal
-func getEnvInt(key string, defaultVal int) int {
-	if val, exists := os.LookupEnv(key); exists {
-		i, err := strconv.Atoi(val)
-		if err == nil {
-			return i
+// ListMap sets val to the environment variable for the key split on commas
+// into name=values pairs, with the values split on "|", if it is set. A name
+// without values is set to an empty list.
+func (r *envReader) ListMap(key string, val *map[string][]string) {
+	v, exists := os.LookupEnv(key)
+	if !exists {
+		return
+	}
+
+	listMap := map[string][]string{}
+	for _, item := range strings.Split(v, ",") {
+		name, values, _ := strings.Cut(strings.TrimSpace(item), "=")
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+
+		list := []string{}
+		for _, value := range strings.Split(values, "|") {
+			if value = strings.TrimSpace(value); value != "" {
+				list = append(list, value)
+			}
 		}
-		// If there were an error, we log and let it fallthrough to return the default value
-		logging.Error("GetEnvInt", "failed to convert environment variable to int", err)
+		listMap[name] = list
 	}
 
-	return defaultVal
+	*val = listMap
+}
+
+// Flags sets the flags in the environment variable for the key, split on
+// commas, over val, if it is set. A flag is turned on by its name alone,
+// or set with name=true or name=false.
+func (r *envReader) Flags(key string, val *map[string]bool) {
+	v, exists := os.LookupEnv(key)
+	if !exists {
+		return
+	}
+
+	if *val == nil {
+		*val = map[string]bool{}
+	}
+
+	for _, item := range strings.Split(v, ",") {
+		name, value, hasValue := strings.Cut(strings.TrimSpace(item), "=")
+		if name == "" {
+			continue
+		}
+
+		on := true
+		if hasValue {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				r.errs = append(r.errs, fmt.Errorf("%s flag %s must be true or false, got %q", key, name, value))
+				continue
+			}
+			on = b
+		}
+		(*val)[name] = on
+	}
 }
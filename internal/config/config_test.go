@@ -2,28 +2,31 @@ package config_test
 
 import (
 	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
 )
 
 var _ = Describe("Config", func() {
 
-	Describe("New", func() {
+	AfterEach(func() {
+		os.Clearenv()
+	})
 
-		AfterEach(func() {
-			os.Clearenv()
-		})
+	Describe("Load", func() {
 
 		It("should create a new config object with environment variables", func() {
 			os.Setenv("POSTGRES_HOSTNAME", "postgres")
 			os.Setenv("PORT", "80")
 			os.Setenv("POSTGRES_MAX_IDLE_CONNS", "5")
 
-			config := config.New()
+			config, err := config.Load("")
+			Expect(err).To(BeNil())
 
 			Expect(config.Server.Port).To(Equal("80"))
 			Expect(config.Database.Host).To(Equal("postgres"))
@@ -31,11 +34,407 @@ var _ = Describe("Config", func() {
 		})
 
 		It("should use all defaults when environment variables are not set", func() {
-			config := config.New()
+			config, err := config.Load("")
+			Expect(err).To(BeNil())
 
 			Expect(config.Server.Port).To(Equal(constants.ServerPortDefault))
 			Expect(config.Database.Name).To(Equal(constants.DBNameDefault))
 			Expect(config.Database.SSLMode).To(Equal(constants.DBSSLModeDefault))
 		})
+
+		It("should return an error rather than defaulting when an environment variable can't be parsed", func() {
+			os.Setenv("POSTGRES_MAX_IDLE_CONNS", "ten")
+			os.Setenv("METRICS_ENABLED", "sometimes")
+
+			_, err := config.Load("")
+
+			Expect(err).To(MatchError(ContainSubstring(`POSTGRES_MAX_IDLE_CONNS must be an integer, got "ten"`)))
+			Expect(err).To(MatchError(ContainSubstring(`METRICS_ENABLED must be true or false, got "sometimes"`)))
+		})
+
+		It("should return an error when a setting is invalid", func() {
+			os.Setenv("LOG_LEVEL", "verbose")
+
+			_, err := config.Load("")
+
+			Expect(err).To(MatchError(ContainSubstring(`LOG_LEVEL must be one of debug, info, warn, error, got "verbose"`)))
+		})
+	})
+
+	Describe("Error format", func() {
+		It("should read the error format", func() {
+			os.Setenv("ERROR_FORMAT", "problem")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Server.ErrorFormat).To(Equal("problem"))
+		})
+
+		It("should default the error format", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Server.ErrorFormat).To(Equal(constants.ServerErrorFormatDefault))
+		})
+	})
+
+	Describe("Logging", func() {
+		It("should read the logging settings", func() {
+			os.Setenv("LOG_LEVEL", "debug")
+			os.Setenv("LOG_FORMAT", "text")
+			os.Setenv("LOG_REDACT_FIELDS", "department, ,user_status")
+			os.Setenv("LOG_REDACT_ALLOW", "email")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Logging.Level).To(Equal("debug"))
+			Expect(cfg.Logging.Format).To(Equal("text"))
+			Expect(cfg.Logging.RedactFields).To(Equal([]string{"department", "user_status"}))
+			Expect(cfg.Logging.RedactAllow).To(Equal([]string{"email"}))
+		})
+
+		It("should default the logging settings", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Logging.Level).To(Equal(constants.LogLevelDefault))
+			Expect(cfg.Logging.Format).To(Equal(constants.LogFormatDefault))
+			Expect(cfg.Logging.RedactFields).To(BeEmpty())
+		})
+	})
+
+	Describe("Metrics", func() {
+		It("should read the metrics settings", func() {
+			os.Setenv("METRICS_ENABLED", "false")
+			os.Setenv("METRICS_PORT", "9090")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Metrics.Enabled).To(BeFalse())
+			Expect(cfg.Metrics.Port).To(Equal("9090"))
+		})
+
+		It("should default the metrics settings", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Metrics.Enabled).To(Equal(constants.MetricsEnabledDefault))
+			Expect(cfg.Metrics.Port).To(Equal(constants.MetricsPortDefault))
+		})
+	})
+
+	Describe("Tracing", func() {
+		It("should read the tracing settings", func() {
+			os.Setenv("TRACING_EXPORTER", "file")
+			os.Setenv("TRACING_FILE", "/tmp/traces.json")
+			os.Setenv("OTEL_SERVICE_NAME", "integra-worker")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Tracing.Exporter).To(Equal("file"))
+			Expect(cfg.Tracing.File).To(Equal("/tmp/traces.json"))
+			Expect(cfg.Tracing.ServiceName).To(Equal("integra-worker"))
+		})
+
+		It("should default the tracing settings", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Tracing.Exporter).To(Equal(constants.TracingExporterDefault))
+			Expect(cfg.Tracing.ServiceName).To(Equal(constants.TracingServiceNameDefault))
+		})
+	})
+
+	Describe("Health checks", func() {
+		It("should read the health check timeout", func() {
+			os.Setenv("HEALTH_CHECK_TIMEOUT", "5")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Health.Timeout).To(Equal(5))
+		})
+
+		It("should default the health check timeout", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Health.Timeout).To(Equal(constants.HealthCheckTimeoutDefault))
+		})
+	})
+
+	Describe("Shutdown", func() {
+		It("should read the shutdown settings", func() {
+			os.Setenv("SHUTDOWN_TIMEOUT", "30")
+			os.Setenv("SHUTDOWN_DRAIN_DELAY", "10")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Server.ShutdownTimeout).To(Equal(30))
+			Expect(cfg.Server.ShutdownDrainDelay).To(Equal(10))
+		})
+
+		It("should default the shutdown settings", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Server.ShutdownTimeout).To(Equal(constants.ServerShutdownTimeoutDefault))
+			Expect(cfg.Server.ShutdownDrainDelay).To(Equal(constants.ServerShutdownDrainDelayDefault))
+		})
+	})
+
+	Describe("DB connection retries", func() {
+		It("should read the connection retry settings", func() {
+			os.Setenv("POSTGRES_CONNECT_MAX_WAIT", "0")
+			os.Setenv("POSTGRES_CONNECT_RETRY_INITIAL", "100")
+			os.Setenv("POSTGRES_CONNECT_RETRY_MAX", "2000")
+			os.Setenv("POSTGRES_MONITOR_INTERVAL", "30")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Database.ConnectMaxWait).To(Equal(0))
+			Expect(cfg.Database.ConnectRetryInitial).To(Equal(100))
+			Expect(cfg.Database.ConnectRetryMax).To(Equal(2000))
+			Expect(cfg.Database.MonitorInterval).To(Equal(30))
+		})
+
+		It("should default the connection retry settings", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Database.ConnectMaxWait).To(Equal(constants.DBConnectMaxWaitDefault))
+			Expect(cfg.Database.ConnectRetryInitial).To(Equal(constants.DBConnectRetryInitialDefault))
+			Expect(cfg.Database.ConnectRetryMax).To(Equal(constants.DBConnectRetryMaxDefault))
+			Expect(cfg.Database.MonitorInterval).To(Equal(constants.DBMonitorIntervalDefault))
+		})
+	})
+
+	Describe("DB connection pool", func() {
+		It("should read the connection pool settings", func() {
+			os.Setenv("DATABASE_URL", "postgres://app@db:5432/app")
+			os.Setenv("POSTGRES_MAX_OPEN_CONNS", "50")
+			os.Setenv("POSTGRES_CONN_MAX_LIFETIME", "60")
+			os.Setenv("POSTGRES_CONN_MAX_IDLE_TIME", "2")
+			os.Setenv("POSTGRES_STATEMENT_TIMEOUT", "5")
+			os.Setenv("POSTGRES_QUERY_TIMEOUT", "30")
+			os.Setenv("POSTGRES_APPLICATION_NAME", "integra-worker")
+			os.Setenv("POSTGRES_SEARCH_PATH", "integra_partners,public")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Database.URL).To(Equal("postgres://app@db:5432/app"))
+			Expect(cfg.Database.MaxOpenConnections).To(Equal(50))
+			Expect(cfg.Database.ConnectionMaxLifetime).To(Equal(60))
+			Expect(cfg.Database.ConnectionMaxIdleTime).To(Equal(2))
+			Expect(cfg.Database.StatementTimeout).To(Equal(5))
+			Expect(cfg.Database.QueryTimeout).To(Equal(30))
+			Expect(cfg.Database.ApplicationName).To(Equal("integra-worker"))
+			Expect(cfg.Database.SearchPath).To(Equal("integra_partners,public"))
+		})
+
+		It("should default the connection pool settings", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Database.URL).To(BeEmpty())
+			Expect(cfg.Database.MaxOpenConnections).To(Equal(constants.DBMaxOpenConnectionsDefault))
+			Expect(cfg.Database.ConnectionMaxLifetime).To(Equal(constants.DBConnectionMaxLifetimeDefault))
+			Expect(cfg.Database.StatementTimeout).To(Equal(constants.DBStatementTimeoutDefault))
+			Expect(cfg.Database.QueryTimeout).To(Equal(constants.DBQueryTimeoutDefault))
+			Expect(cfg.Database.ApplicationName).To(Equal(constants.DBApplicationNameDefault))
+			Expect(cfg.Database.Validate()).To(Succeed())
+		})
+	})
+
+	Describe("Secret files", func() {
+		It("should read secrets from the files set with the _FILE environment variables", func() {
+			dir := GinkgoT().TempDir()
+			writeFile := func(name, contents string) string {
+				path := filepath.Join(dir, name)
+				Expect(os.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+				return path
+			}
+
+			os.Setenv("POSTGRES_PASSWORD", "plain")
+			os.Setenv("POSTGRES_PASSWORD_FILE", writeFile("db_password", "from-file\n"))
+			os.Setenv("SMTP_PASSWORD_FILE", writeFile("smtp_password", "smtp-secret"))
+			os.Setenv("POSTGRES_PASSWORD_RELOAD_INTERVAL", "300")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Database.Password).To(Equal("from-file"))
+			Expect(cfg.Mailer.Password).To(Equal("smtp-secret"))
+			Expect(cfg.Database.PasswordReloadInterval).To(Equal(300))
+		})
+
+		It("should read the DB URL from the file set with DATABASE_URL_FILE", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "db_url")
+			Expect(os.WriteFile(path, []byte("postgres://app:pw@db:5432/app\n"), 0600)).To(Succeed())
+			os.Setenv("DATABASE_URL_FILE", path)
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Database.URL).To(Equal("postgres://app:pw@db:5432/app"))
+		})
+
+		It("should default the password reload interval", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Database.PasswordReloadInterval).To(Equal(constants.DBPasswordReloadIntervalDefault))
+		})
+
+		It("should return an error naming the variable when a secret file can't be read", func() {
+			os.Setenv("POSTGRES_PASSWORD_FILE", filepath.Join(GinkgoT().TempDir(), "missing"))
+
+			_, err := config.Load("")
+
+			Expect(err).To(MatchError(ContainSubstring("POSTGRES_PASSWORD_FILE: failed to read secret file")))
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+	})
+
+	Describe("Config watching", func() {
+		It("should read the config watch interval", func() {
+			os.Setenv("CONFIG_WATCH_INTERVAL", "0")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Server.ConfigWatchInterval).To(Equal(0))
+		})
+
+		It("should default the config watch interval", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Server.ConfigWatchInterval).To(Equal(constants.ServerConfigWatchIntervalDefault))
+		})
+	})
+
+	Describe("Feature flags", func() {
+		It("should read the feature flags", func() {
+			os.Setenv("FEATURE_FLAGS", "bulk_import, audit_export=false,,beta=true")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Features).To(Equal(map[string]bool{"bulk_import": true, "audit_export": false, "beta": true}))
+		})
+
+		It("should default to no feature flags", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Features).To(BeEmpty())
+		})
+
+		It("should return an error for a feature flag that isn't true or false", func() {
+			os.Setenv("FEATURE_FLAGS", "bulk_import=sometimes")
+
+			_, err := config.Load("")
+
+			Expect(err).To(MatchError(ContainSubstring(`FEATURE_FLAGS flag bulk_import must be true or false, got "sometimes"`)))
+		})
+	})
+
+	Describe("CORS", func() {
+		It("should read the CORS policy", func() {
+			os.Setenv("CORS_ALLOW_ORIGINS", "https://app.example.com, https://admin.example.com")
+			os.Setenv("CORS_ALLOW_METHODS", "GET,POST")
+			os.Setenv("CORS_ALLOW_HEADERS", "Content-Type")
+			os.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+			os.Setenv("CORS_MAX_AGE", "60")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Server.CORS.AllowOrigins).To(Equal([]string{"https://app.example.com", "https://admin.example.com"}))
+			Expect(cfg.Server.CORS.AllowMethods).To(Equal([]string{"GET", "POST"}))
+			Expect(cfg.Server.CORS.AllowHeaders).To(Equal([]string{"Content-Type"}))
+			Expect(cfg.Server.CORS.AllowCredentials).To(BeTrue())
+			Expect(cfg.Server.CORS.MaxAge).To(Equal(60))
+		})
+
+		It("should default the CORS policy", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Server.CORS.AllowOrigins).To(Equal([]string{constants.CORSAllowOriginsDefault}))
+			Expect(cfg.Server.CORS.AllowCredentials).To(BeFalse())
+			Expect(cfg.Server.CORS.MaxAge).To(Equal(constants.CORSMaxAgeDefault))
+		})
+	})
+
+	Describe("TLS", func() {
+		It("should read the TLS settings", func() {
+			os.Setenv("TLS_CERT_FILE", "/etc/tls/tls.crt")
+			os.Setenv("TLS_KEY_FILE", "/etc/tls/tls.key")
+			os.Setenv("TLS_MIN_VERSION", "1.3")
+			os.Setenv("TLS_CLIENT_CA_FILE", "/etc/tls/ca.crt")
+			os.Setenv("TLS_CLIENT_AUTH", "optional")
+			os.Setenv("TLS_RELOAD_INTERVAL", "0")
+			os.Setenv("TLS_ALLOWED_CLIENTS", "billing-service, reporting-service")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Server.TLS).To(Equal(config.TLSConfig{
+				CertFile:       "/etc/tls/tls.crt",
+				KeyFile:        "/etc/tls/tls.key",
+				MinVersion:     "1.3",
+				ClientCAFile:   "/etc/tls/ca.crt",
+				ClientAuth:     "optional",
+				AllowedClients: []string{"billing-service", "reporting-service"},
+				ReloadInterval: 0,
+			}))
+			Expect(cfg.Server.TLS.Enabled()).To(BeTrue())
+		})
+
+		It("should serve plain HTTP by default", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Server.TLS.Enabled()).To(BeFalse())
+			Expect(cfg.Server.TLS.MinVersion).To(Equal(constants.TLSMinVersionDefault))
+			Expect(cfg.Server.TLS.ReloadInterval).To(Equal(constants.TLSReloadIntervalDefault))
+		})
+	})
+
+	Describe("User status transitions", func() {
+		It("should read the user status transitions", func() {
+			os.Setenv("USER_STATUS_TRANSITIONS", "I=A, A=I|T,T=")
+
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Users.StatusTransitions).To(Equal(models.StatusTransitions{
+				"I": {"A"},
+				"A": {"I", "T"},
+				"T": {},
+			}))
+		})
+
+		It("should default to the default status transitions", func() {
+			cfg, err := config.Load("")
+			Expect(err).To(BeNil())
+
+			Expect(cfg.Users.StatusTransitions).To(Equal(models.DefaultStatusTransitions))
+		})
+
+		It("should not change the default status transitions when the config's are changed", func() {
+			config.Default().Users.StatusTransitions["T"] = []string{"A"}
+
+			Expect(models.DefaultStatusTransitions).NotTo(HaveKey("T"))
+		})
 	})
 })
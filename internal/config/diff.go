@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Change is a setting that differs between two configs
+type Change struct {
+	// Path of the setting in the config file, e.g. "logging.level"
+	Setting string
+	Old     string
+	New     string
+	// Whether the change is applied by reloading the config rather than
+	// needing a restart
+	Reloadable bool
+}
+
+// Diff returns every setting that differs from the old config, by its
+// path in the config file, with secrets masked so the changes can be
+// logged
+func (c Config) Diff(old Config) []Change {
+	var changes []Change
+	diffValues("",
+		values{reflect.ValueOf(old), reflect.ValueOf(old.Masked())},
+		values{reflect.ValueOf(c), reflect.ValueOf(c.Masked())},
+		&changes)
+
+	return changes
+}
+
+// values is a setting of a config along with the same setting of its
+// masked copy, which is logged in its place
+type values struct {
+	value  reflect.Value
+	masked reflect.Value
+}
+
+// field returns the values of the struct field at the index
+func (v values) field(i int) values {
+	return values{v.value.Field(i), v.masked.Field(i)}
+}
+
+// key returns the values of the map entry with the key, which are not
+// valid if the map has no entry for it
+func (v values) key(key reflect.Value) values {
+	return values{v.value.MapIndex(key), v.masked.MapIndex(key)}
+}
+
+// diffValues appends a Change for every setting that differs between old
+// and new, walking into sections by their YAML names and into maps by key.
+// Settings are compared unmasked, so a changed secret is still reported.
+func diffValues(path string, old values, new values, changes *[]Change) {
+	switch old.value.Kind() {
+	case reflect.Struct:
+		for i := 0; i < old.value.NumField(); i++ {
+			field := old.value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			diffValues(joinPath(path, name), old.field(i), new.field(i), changes)
+		}
+	case reflect.Map:
+		var keys []string
+		for _, key := range append(old.value.MapKeys(), new.value.MapKeys()...) {
+			if !slices.Contains(keys, key.String()) {
+				keys = append(keys, key.String())
+			}
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			diffValues(joinPath(path, key), old.key(reflect.ValueOf(key)), new.key(reflect.ValueOf(key)), changes)
+		}
+	default:
+		if old.value.IsValid() && new.value.IsValid() && reflect.DeepEqual(old.value.Interface(), new.value.Interface()) {
+			return
+		}
+
+		*changes = append(*changes, Change{
+			Setting:    path,
+			Old:        formatValue(old.masked),
+			New:        formatValue(new.masked),
+			Reloadable: IsReloadable(path),
+		})
+	}
+}
+
+// formatValue formats the setting for logging, lists as comma separated
+// values. Settings missing from a map are formatted as "".
+func formatValue(value reflect.Value) string {
+	if !value.IsValid() {
+		return ""
+	}
+
+	if value.Kind() == reflect.Slice {
+		items := make([]string, value.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(value.Index(i).Interface())
+		}
+		return strings.Join(items, ",")
+	}
+
+	return fmt.Sprint(value.Interface())
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
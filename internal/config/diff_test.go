@@ -0,0 +1,60 @@
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+)
+
+var _ = Describe("Diff", func() {
+	var old, cfg *config.Config
+
+	BeforeEach(func() {
+		old = config.Default()
+		cfg = config.Default()
+	})
+
+	It("should return nothing if no setting changed", func() {
+		Expect(cfg.Diff(*old)).To(BeEmpty())
+	})
+
+	It("should return every changed setting by its path in the config file", func() {
+		cfg.Logging.Level = "debug"
+		cfg.Server.CORS.AllowOrigins = []string{"https://app.example.com", "https://admin.example.com"}
+		cfg.Server.Port = "9000"
+
+		Expect(cfg.Diff(*old)).To(ConsistOf(
+			config.Change{Setting: "logging.level", Old: "info", New: "debug", Reloadable: true},
+			config.Change{
+				Setting:    "server.cors.allow_origins",
+				Old:        "http://localhost:3000",
+				New:        "https://app.example.com,https://admin.example.com",
+				Reloadable: true,
+			},
+			config.Change{Setting: "server.port", Old: "8080", New: "9000", Reloadable: false},
+		))
+	})
+
+	It("should return feature flags that were added, removed or changed", func() {
+		old.Features = map[string]bool{"removed": true, "flipped": false, "same": true}
+		cfg.Features = map[string]bool{"added": true, "flipped": true, "same": true}
+
+		Expect(cfg.Diff(*old)).To(Equal([]config.Change{
+			{Setting: "features.added", Old: "", New: "true", Reloadable: true},
+			{Setting: "features.flipped", Old: "false", New: "true", Reloadable: true},
+			{Setting: "features.removed", Old: "true", New: "", Reloadable: true},
+		}))
+	})
+
+	It("should mask secrets that changed", func() {
+		cfg.Database.Password = "rotated"
+
+		changes := cfg.Diff(*old)
+
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Setting).To(Equal("database.password"))
+		Expect(changes[0].New).To(Equal(config.Masked))
+		Expect(changes[0].Reloadable).To(BeFalse())
+	})
+})
@@ -0,0 +1,116 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+)
+
+var _ = Describe("Config file", func() {
+
+	// writeFile writes the contents to a file with the name in a temp dir
+	// and returns its path
+	writeFile := func(name string, contents string) string {
+		path := filepath.Join(GinkgoT().TempDir(), name)
+		Expect(os.WriteFile(path, []byte(contents), 0o600)).To(Succeed())
+		return path
+	}
+
+	AfterEach(func() {
+		os.Clearenv()
+	})
+
+	DescribeTable("should layer the file over the defaults and environment variables over the file",
+		func(name string, contents string) {
+			os.Setenv("POSTGRES_HOSTNAME", "env-db")
+			os.Setenv("LOG_REDACT_FIELDS", "department")
+
+			cfg, err := config.Load(writeFile(name, contents))
+
+			Expect(err).To(BeNil())
+			// Set by the file and the environment
+			Expect(cfg.Database.Host).To(Equal("env-db"))
+			Expect(cfg.Logging.RedactFields).To(Equal([]string{"department"}))
+			// Set by the file
+			Expect(cfg.Database.MaxOpenConnections).To(Equal(40))
+			Expect(cfg.Server.Port).To(Equal("9000"))
+			Expect(cfg.Metrics.Enabled).To(BeFalse())
+			// Set by neither
+			Expect(cfg.Database.Name).To(Equal(constants.DBNameDefault))
+			Expect(cfg.Scheduler.Interval).To(Equal(constants.SchedulerIntervalDefault))
+		},
+		Entry("YAML", "config.yaml", `
+server:
+  port: "9000"
+database:
+  host: file-db
+  max_open_connections: 40
+logging:
+  redact_fields: [user_status]
+metrics:
+  enabled: false
+`),
+		Entry("TOML", "config.toml", `
+[server]
+port = "9000"
+
+[database]
+host = "file-db"
+max_open_connections = 40
+
+[logging]
+redact_fields = ["user_status"]
+
+[metrics]
+enabled = false
+`),
+	)
+
+	It("should keep the defaults for an empty file", func() {
+		path := writeFile("config.yml", "")
+		cfg, err := config.Load(path)
+
+		Expect(err).To(BeNil())
+		Expect(cfg.Path()).To(Equal(path))
+		Expect(cfg.Diff(*config.Default())).To(BeEmpty())
+	})
+
+	DescribeTable("should return an error for settings the config doesn't have",
+		func(name string, contents string, setting string) {
+			_, err := config.Load(writeFile(name, contents))
+
+			Expect(err).To(MatchError(ContainSubstring(setting)))
+		},
+		Entry("YAML", "config.yaml", "database:\n  hostname: db\n", "hostname"),
+		Entry("TOML", "config.toml", "[database]\nhostname = \"db\"\n", "database.hostname"),
+	)
+
+	It("should return an error for values of the wrong type", func() {
+		_, err := config.Load(writeFile("config.yaml", "database:\n  max_open_connections: lots\n"))
+
+		Expect(err).To(MatchError(ContainSubstring("lots")))
+	})
+
+	It("should validate the settings in the file", func() {
+		_, err := config.Load(writeFile("config.toml", "[scheduler]\ninterval = 0\n"))
+
+		Expect(err).To(MatchError(ContainSubstring("SCHEDULER_INTERVAL must be more than 0, got 0")))
+	})
+
+	It("should return an error for other file formats", func() {
+		_, err := config.Load(writeFile("config.json", "{}"))
+
+		Expect(err).To(MatchError(ContainSubstring(`must be .yaml, .yml or .toml, got ".json"`)))
+	})
+
+	It("should return an error when the file doesn't exist", func() {
+		_, err := config.Load(filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+})
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"io"
+	"maps"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Masked replaces the value of secrets when printing the config
+const Masked = "********"
+
+// dsnPasswordPattern matches the password of a keyword/value DSN, quoted
+// or not
+var dsnPasswordPattern = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// Masked returns a copy of the config with its secrets masked, so it can
+// be printed or logged
+func (c Config) Masked() Config {
+	c.Database.Password = mask(c.Database.Password)
+	c.Database.URL = maskURL(c.Database.URL)
+	c.Mailer.Password = mask(c.Mailer.Password)
+
+	// Lists are copied so the masked config doesn't share them
+	c.Logging.RedactFields = append([]string(nil), c.Logging.RedactFields...)
+	c.Logging.RedactAllow = append([]string(nil), c.Logging.RedactAllow...)
+	c.Server.CORS.AllowOrigins = append([]string(nil), c.Server.CORS.AllowOrigins...)
+	c.Server.CORS.AllowMethods = append([]string(nil), c.Server.CORS.AllowMethods...)
+	c.Server.CORS.AllowHeaders = append([]string(nil), c.Server.CORS.AllowHeaders...)
+	c.Server.TLS.AllowedClients = append([]string(nil), c.Server.TLS.AllowedClients...)
+	c.Users.StatusTransitions = c.Users.StatusTransitions.Clone()
+	c.Features = maps.Clone(c.Features)
+
+	return c
+}
+
+// Print writes the config to w as YAML, in the format of the config file,
+// with its secrets masked
+func (c Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(c.Masked()); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// mask masks the secret, leaving it empty if it is not set so it is clear
+// when one is missing
+func mask(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return Masked
+}
+
+// maskURL masks the password in the connection URL or DSN
+func maskURL(connString string) string {
+	if u, err := url.Parse(connString); err == nil && u.Scheme != "" {
+		if _, set := u.User.Password(); set {
+			u.User = url.UserPassword(u.User.Username(), Masked)
+		}
+
+		if query := u.Query(); query.Has("password") {
+			query.Set("password", Masked)
+			u.RawQuery = query.Encode()
+		}
+
+		// Left unescaped so it reads the same as the other secrets
+		return strings.ReplaceAll(u.String(), url.QueryEscape(Masked), Masked)
+	}
+
+	return dsnPasswordPattern.ReplaceAllString(connString, "${1}"+Masked)
+}
@@ -0,0 +1,67 @@
+package config_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+)
+
+var _ = Describe("Print", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		cfg = config.Default()
+		cfg.Database.Password = "db-secret"
+		cfg.Mailer.Password = "smtp-secret"
+	})
+
+	It("should print the config in the file format with the secrets masked", func() {
+		var out bytes.Buffer
+
+		Expect(cfg.Print(&out)).To(Succeed())
+
+		Expect(out.String()).To(ContainSubstring("database:\n"))
+		Expect(out.String()).To(ContainSubstring("max_open_connections: 25\n"))
+		Expect(out.String()).To(ContainSubstring(`password: '********'`))
+		Expect(out.String()).NotTo(ContainSubstring("db-secret"))
+		Expect(out.String()).NotTo(ContainSubstring("smtp-secret"))
+	})
+
+	It("should leave secrets that aren't set empty", func() {
+		cfg.Mailer.Password = ""
+
+		Expect(cfg.Masked().Mailer.Password).To(BeEmpty())
+	})
+
+	It("should not change the config it masks", func() {
+		cfg.Masked()
+
+		Expect(cfg.Database.Password).To(Equal("db-secret"))
+	})
+
+	DescribeTable("should mask the password in the DB URL",
+		func(url string, expected string) {
+			cfg.Database.URL = url
+
+			Expect(cfg.Masked().Database.URL).To(Equal(expected))
+		},
+		Entry("in the user info",
+			"postgres://app:s3cret@db:5432/app?sslmode=require",
+			"postgres://app:********@db:5432/app?sslmode=require"),
+		Entry("as a query param",
+			"postgres://app@db/app?password=s3cret",
+			"postgres://app@db/app?password=********"),
+		Entry("without a password",
+			"postgres://app@db/app",
+			"postgres://app@db/app"),
+		Entry("in a keyword/value DSN",
+			"host=db user=app password=s3cret dbname=app",
+			"host=db user=app password=******** dbname=app"),
+		Entry("quoted in a keyword/value DSN",
+			"host=db password='s3 cret' dbname=app",
+			"host=db password=******** dbname=app"),
+	)
+})
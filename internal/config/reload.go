@@ -0,0 +1,46 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ReloadableSettings are the settings applied again when the config is
+// reloaded while the server is running. A change to any other setting
+// only takes effect once the server is restarted.
+var ReloadableSettings = []string{
+	"server.cors",
+	"logging.level",
+	"logging.redact_fields",
+	"logging.redact_allow",
+	"password_reset.rate_limit",
+	"password_reset.rate_limit_window",
+	"features",
+}
+
+// IsReloadable reports whether the setting at the path is applied by
+// reloading the config
+func IsReloadable(setting string) bool {
+	return slices.ContainsFunc(ReloadableSettings, func(reloadable string) bool {
+		return setting == reloadable || strings.HasPrefix(setting, reloadable+".")
+	})
+}
+
+// WithReloaded returns a copy of the config with the reloadable settings
+// of the reloaded config, keeping the rest as they were, as the server
+// only applies those while running
+func (c Config) WithReloaded(reloaded Config) Config {
+	c.Server.CORS = reloaded.Server.CORS
+	c.Logging.Level = reloaded.Logging.Level
+	c.Logging.RedactFields = slices.Clone(reloaded.Logging.RedactFields)
+	c.Logging.RedactAllow = slices.Clone(reloaded.Logging.RedactAllow)
+	c.Server.CORS.AllowOrigins = slices.Clone(reloaded.Server.CORS.AllowOrigins)
+	c.Server.CORS.AllowMethods = slices.Clone(reloaded.Server.CORS.AllowMethods)
+	c.Server.CORS.AllowHeaders = slices.Clone(reloaded.Server.CORS.AllowHeaders)
+	c.PasswordReset.RateLimit = reloaded.PasswordReset.RateLimit
+	c.PasswordReset.RateLimitWindow = reloaded.PasswordReset.RateLimitWindow
+	c.Features = maps.Clone(reloaded.Features)
+
+	return c
+}
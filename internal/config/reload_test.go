@@ -0,0 +1,62 @@
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+)
+
+var _ = Describe("Reload", func() {
+
+	Describe("WithReloaded", func() {
+		It("should only take the reloadable settings of the reloaded config", func() {
+			current := config.Default()
+
+			reloaded := config.Default()
+			reloaded.Server.Port = "9000"
+			reloaded.Database.Password = "rotated"
+			reloaded.Logging.Level = "debug"
+			reloaded.Logging.RedactFields = []string{"department"}
+			reloaded.Server.CORS.AllowOrigins = []string{"https://app.example.com"}
+			reloaded.PasswordReset.RateLimit = 10
+			reloaded.PasswordReset.RateLimitWindow = 5
+			reloaded.Features = map[string]bool{"bulk_import": true}
+
+			applied := current.WithReloaded(*reloaded)
+
+			// Every reloadable setting is taken, so only the others are left
+			for _, change := range applied.Diff(*reloaded) {
+				Expect(change.Reloadable).To(BeFalse(), change.Setting)
+			}
+			for _, change := range applied.Diff(*current) {
+				Expect(change.Reloadable).To(BeTrue(), change.Setting)
+			}
+			Expect(applied.Server.Port).To(Equal(current.Server.Port))
+			Expect(applied.Logging.Level).To(Equal("debug"))
+		})
+
+		It("should not share lists or flags with the reloaded config", func() {
+			reloaded := config.Default()
+			reloaded.Features = map[string]bool{"bulk_import": true}
+
+			applied := config.Default().WithReloaded(*reloaded)
+			reloaded.Features["bulk_import"] = false
+			reloaded.Server.CORS.AllowOrigins[0] = "https://evil.example.com"
+
+			Expect(applied.Features["bulk_import"]).To(BeTrue())
+			Expect(applied.Server.CORS.AllowOrigins).To(Equal([]string{"http://localhost:3000"}))
+		})
+	})
+
+	DescribeTable("IsReloadable",
+		func(setting string, expected bool) {
+			Expect(config.IsReloadable(setting)).To(Equal(expected))
+		},
+		Entry("a reloadable setting", "logging.level", true),
+		Entry("a setting in a reloadable section", "server.cors.allow_origins", true),
+		Entry("a feature flag", "features.bulk_import", true),
+		Entry("a static setting", "logging.format", false),
+		Entry("a static setting sharing a prefix", "password_reset.rate_limit_windows", false),
+	)
+})
@@ -0,0 +1,337 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/validation"
+)
+
+// Values accepted by the settings that take one of a set
+var (
+	// SSLModes are the sslmode values Postgres accepts
+	SSLModes         = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	ErrorFormats     = []string{"default", "problem"}
+	LogFormats       = []string{"json", "text"}
+	TracingExporters = []string{"none", "stdout", "file", "otlp"}
+	// CORSMethods are the methods that can be allowed in cross-origin
+	// requests
+	CORSMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	TLSVersions = []string{"1.2", "1.3"}
+	ClientAuths = []string{"require", "optional"}
+)
+
+// headerNamePattern matches the characters HTTP allows in header names
+var headerNamePattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// Validate checks every setting in the config, so mistakes are caught on
+// startup rather than when the setting is used.
+//
+// Returns an error listing every invalid setting by its environment
+// variable.
+func (c Config) Validate() error {
+	return errors.Join(
+		c.Server.Validate(),
+		c.Database.Validate(),
+		c.Logging.Validate(),
+		c.Metrics.Validate(c.Server),
+		c.Tracing.Validate(),
+		c.Health.Validate(),
+		c.Mailer.Validate(),
+		c.PasswordReset.Validate(),
+		c.Scheduler.Validate(),
+		c.Users.Validate())
+}
+
+func (c ServerConfig) Validate() error {
+	return errors.Join(
+		checkPort("PORT", c.Port),
+		checkOneOf("ERROR_FORMAT", c.ErrorFormat, ErrorFormats),
+		checkNotNegative("SHUTDOWN_TIMEOUT", c.ShutdownTimeout),
+		checkNotNegative("SHUTDOWN_DRAIN_DELAY", c.ShutdownDrainDelay),
+		checkNotNegative("CONFIG_WATCH_INTERVAL", c.ConfigWatchInterval),
+		c.CORS.Validate(),
+		c.TLS.Validate())
+}
+
+// Validate checks the TLS settings. The files are checked when they are
+// loaded on startup.
+func (c TLSConfig) Validate() error {
+	var errs []error
+
+	switch {
+	case c.CertFile != "" && c.KeyFile == "":
+		errs = append(errs, errors.New("TLS_KEY_FILE is required with TLS_CERT_FILE"))
+	case c.CertFile == "" && c.KeyFile != "":
+		errs = append(errs, errors.New("TLS_CERT_FILE is required with TLS_KEY_FILE"))
+	}
+
+	if c.ClientCAFile != "" && c.CertFile == "" {
+		errs = append(errs, errors.New("TLS_CERT_FILE is required with TLS_CLIENT_CA_FILE, as clients can only be verified over TLS"))
+	}
+
+	if len(c.AllowedClients) > 0 && c.ClientCAFile == "" {
+		errs = append(errs, errors.New("TLS_CLIENT_CA_FILE is required with TLS_ALLOWED_CLIENTS, as clients are only identified by certificate"))
+	}
+	for _, client := range c.AllowedClients {
+		if strings.TrimSpace(client) == "" {
+			errs = append(errs, errors.New("TLS_ALLOWED_CLIENTS can't contain empty identities"))
+			break
+		}
+	}
+
+	return errors.Join(append(errs,
+		checkOneOf("TLS_MIN_VERSION", c.MinVersion, TLSVersions),
+		checkOneOf("TLS_CLIENT_AUTH", c.ClientAuth, ClientAuths),
+		checkNotNegative("TLS_RELOAD_INTERVAL", c.ReloadInterval))...)
+}
+
+// Validate checks the CORS policy, so a mistyped origin is caught on
+// startup rather than as the front end's requests being blocked.
+func (c CORSConfig) Validate() error {
+	var errs []error
+
+	if len(c.AllowOrigins) == 0 {
+		errs = append(errs, errors.New("CORS_ALLOW_ORIGINS is required"))
+	}
+
+	for _, origin := range c.AllowOrigins {
+		if origin == "*" {
+			// Browsers reject credentials for any origin
+			if c.AllowCredentials {
+				errs = append(errs, errors.New("CORS_ALLOW_CREDENTIALS can't be true when CORS_ALLOW_ORIGINS allows any origin"))
+			}
+			continue
+		}
+
+		errs = append(errs, checkOrigin("CORS_ALLOW_ORIGINS", origin))
+	}
+
+	for _, method := range c.AllowMethods {
+		errs = append(errs, checkOneOf("CORS_ALLOW_METHODS", method, CORSMethods))
+	}
+
+	for _, header := range c.AllowHeaders {
+		if !headerNamePattern.MatchString(header) {
+			errs = append(errs, fmt.Errorf("CORS_ALLOW_HEADERS must be header names, got %q", header))
+		}
+	}
+
+	errs = append(errs, checkNotNegative("CORS_MAX_AGE", c.MaxAge))
+
+	return errors.Join(errs...)
+}
+
+// Validate checks the DB config can be connected with, so mistakes are
+// caught on startup rather than as failures to connect.
+func (c DatabaseConfig) Validate() error {
+	var errs []error
+
+	if c.URL != "" && c.PasswordFile != "" {
+		// The URL's password would be overwritten by the file's
+		errs = append(errs, errors.New("POSTGRES_PASSWORD_FILE can't be set with DATABASE_URL"))
+	}
+
+	if c.URL != "" {
+		// Keyword/value DSNs are left for the driver to parse
+		if strings.Contains(c.URL, "://") {
+			u, err := url.Parse(c.URL)
+			if err != nil {
+				// The parse error would include the password
+				errs = append(errs, errors.New("DATABASE_URL is not a valid URL"))
+			} else if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+				errs = append(errs, fmt.Errorf("DATABASE_URL scheme must be postgres or postgresql, got %q", u.Scheme))
+			}
+		}
+	} else {
+		errs = append(errs,
+			checkRequired("POSTGRES_HOSTNAME", c.Host),
+			checkRequired("POSTGRES_USER", c.Username),
+			checkRequired("POSTGRES_DB", c.Name),
+			checkPort("POSTGRES_PORT", c.Port),
+			checkOneOf("POSTGRES_SSL", c.SSLMode, SSLModes))
+	}
+
+	errs = append(errs,
+		checkNotNegative("POSTGRES_MAX_IDLE_CONNS", c.MaxIdleConnections),
+		checkNotNegative("POSTGRES_CONN_MAX_IDLE_TIME", c.ConnectionMaxIdleTime),
+		checkNotNegative("POSTGRES_MAX_OPEN_CONNS", c.MaxOpenConnections),
+		checkNotNegative("POSTGRES_CONN_MAX_LIFETIME", c.ConnectionMaxLifetime),
+		checkNotNegative("POSTGRES_QUERY_TIMEOUT", c.QueryTimeout),
+		checkNotNegative("POSTGRES_STATEMENT_TIMEOUT", c.StatementTimeout),
+		checkNotNegative("POSTGRES_CONNECT_MAX_WAIT", c.ConnectMaxWait),
+		checkPositive("POSTGRES_CONNECT_RETRY_INITIAL", c.ConnectRetryInitial),
+		checkPositive("POSTGRES_CONNECT_RETRY_MAX", c.ConnectRetryMax),
+		checkNotNegative("POSTGRES_MONITOR_INTERVAL", c.MonitorInterval),
+		checkNotNegative("POSTGRES_PASSWORD_RELOAD_INTERVAL", c.PasswordReloadInterval))
+
+	if c.MaxOpenConnections > 0 && c.MaxIdleConnections > c.MaxOpenConnections {
+		errs = append(errs, fmt.Errorf(
+			"POSTGRES_MAX_IDLE_CONNS (%d) can't be more than POSTGRES_MAX_OPEN_CONNS (%d)",
+			c.MaxIdleConnections, c.MaxOpenConnections))
+	}
+
+	if c.ConnectRetryInitial > c.ConnectRetryMax {
+		errs = append(errs, fmt.Errorf(
+			"POSTGRES_CONNECT_RETRY_INITIAL (%d) can't be more than POSTGRES_CONNECT_RETRY_MAX (%d)",
+			c.ConnectRetryInitial, c.ConnectRetryMax))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c LoggingConfig) Validate() error {
+	var level slog.Level
+	var levelErr error
+	if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+		levelErr = fmt.Errorf("LOG_LEVEL must be one of debug, info, warn, error, got %q", c.Level)
+	}
+
+	return errors.Join(
+		levelErr,
+		checkOneOf("LOG_FORMAT", strings.ToLower(c.Format), LogFormats))
+}
+
+// Validate checks the metrics config, along with the server config the
+// admin port must not clash with
+func (c MetricsConfig) Validate(server ServerConfig) error {
+	if c.Port == "" {
+		return nil
+	}
+
+	if c.Port == server.Port {
+		return fmt.Errorf("METRICS_PORT can't be the same as PORT (%s)", server.Port)
+	}
+
+	return checkPort("METRICS_PORT", c.Port)
+}
+
+func (c TracingConfig) Validate() error {
+	var fileErr error
+	if c.Exporter == "file" {
+		fileErr = checkRequired("TRACING_FILE", c.File)
+	}
+
+	return errors.Join(
+		checkOneOf("TRACING_EXPORTER", c.Exporter, TracingExporters),
+		fileErr,
+		checkRequired("OTEL_SERVICE_NAME", c.ServiceName))
+}
+
+func (c HealthConfig) Validate() error {
+	return checkPositive("HEALTH_CHECK_TIMEOUT", c.Timeout)
+}
+
+// Validate checks the mailer config. The SMTP settings are only checked
+// when a host is set, as emails are logged otherwise.
+func (c MailerConfig) Validate() error {
+	errs := []error{checkRequired("SMTP_FROM", c.From)}
+
+	if c.Host != "" {
+		errs = append(errs, checkPort("SMTP_PORT", c.Port))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c PasswordResetConfig) Validate() error {
+	var urlErr error
+	if u, err := url.Parse(c.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		urlErr = fmt.Errorf("PASSWORD_RESET_URL must be an http or https URL, got %q", c.URL)
+	}
+
+	return errors.Join(
+		urlErr,
+		checkPositive("PASSWORD_RESET_TOKEN_TTL", c.TokenTTL),
+		checkPositive("PASSWORD_RESET_RATE_LIMIT", c.RateLimit),
+		checkPositive("PASSWORD_RESET_RATE_LIMIT_WINDOW", c.RateLimitWindow),
+		checkNotNegative("PASSWORD_RESET_MIN_RESPONSE_TIME", c.MinResponseTime))
+}
+
+func (c SchedulerConfig) Validate() error {
+	return checkPositive("SCHEDULER_INTERVAL", c.Interval)
+}
+
+// Validate checks the status transitions only name statuses users can have
+func (c UsersConfig) Validate() error {
+	var errs []error
+
+	for from, to := range c.StatusTransitions {
+		errs = append(errs, checkOneOf("USER_STATUS_TRANSITIONS", from, validation.UserStatuses))
+
+		for _, status := range to {
+			errs = append(errs, checkOneOf("USER_STATUS_TRANSITIONS", status, validation.UserStatuses))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkRequired returns an error if the setting is empty
+func checkRequired(key string, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required", key)
+	}
+
+	return nil
+}
+
+// checkPort returns an error if the setting is not a port number
+func checkPort(key string, value string) error {
+	if port, err := strconv.Atoi(value); err != nil || port < 0 || port > 65535 {
+		return fmt.Errorf("%s must be a port number, got %q", key, value)
+	}
+
+	return nil
+}
+
+// checkOneOf returns an error if the setting is not one of the options
+func checkOneOf(key string, value string, options []string) error {
+	if !slices.Contains(options, value) {
+		return fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(options, ", "), value)
+	}
+
+	return nil
+}
+
+// checkOrigin returns an error if the setting is not an origin, which is
+// an http or https URL without a path. The host can start with a "*."
+// wildcard for any subdomain.
+func checkOrigin(key string, value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("%s must be origins like https://app.example.com, got %q", key, value)
+	}
+
+	host := strings.TrimPrefix(u.Hostname(), "*.")
+	if host == "" || strings.Contains(host, "*") {
+		return fmt.Errorf("%s can only have a wildcard for the subdomain like https://*.example.com, got %q", key, value)
+	}
+
+	return nil
+}
+
+// checkNotNegative returns an error if the setting is negative
+func checkNotNegative(key string, value int) error {
+	if value < 0 {
+		return fmt.Errorf("%s can't be negative, got %d", key, value)
+	}
+
+	return nil
+}
+
+// checkPositive returns an error if the setting is not above 0
+func checkPositive(key string, value int) error {
+	if value <= 0 {
+		return fmt.Errorf("%s must be more than 0, got %d", key, value)
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+)
+
+var _ = Describe("Validate", func() {
+	var dbConfig config.DatabaseConfig
+
+	BeforeEach(func() {
+		dbConfig = config.Default().Database
+	})
+
+	It("should accept a full connection URL in place of the connection fields", func() {
+		dbConfig.URL = "postgresql://app:secret@db:5432/app?sslmode=require"
+		dbConfig.Host = ""
+		dbConfig.Port = "not a port"
+
+		Expect(dbConfig.Validate()).To(Succeed())
+	})
+
+	It("should accept a keyword/value DSN", func() {
+		dbConfig.URL = "host=db user=app dbname=app"
+
+		Expect(dbConfig.Validate()).To(Succeed())
+	})
+
+	DescribeTable("should reject invalid settings naming the environment variable",
+		func(update func(*config.DatabaseConfig), message string) {
+			update(&dbConfig)
+
+			Expect(dbConfig.Validate()).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("URL with a password file",
+			func(c *config.DatabaseConfig) {
+				c.URL = "postgres://app:secret@db:5432/app"
+				c.PasswordFile = "/run/secrets/db_password"
+			},
+			"POSTGRES_PASSWORD_FILE can't be set with DATABASE_URL"),
+		Entry("URL with another scheme",
+			func(c *config.DatabaseConfig) { c.URL = "mysql://app@db/app" },
+			`DATABASE_URL scheme must be postgres or postgresql, got "mysql"`),
+		Entry("missing host",
+			func(c *config.DatabaseConfig) { c.Host = "" },
+			"POSTGRES_HOSTNAME is required"),
+		Entry("invalid port",
+			func(c *config.DatabaseConfig) { c.Port = "70000" },
+			`POSTGRES_PORT must be a port number, got "70000"`),
+		Entry("unknown SSL mode",
+			func(c *config.DatabaseConfig) { c.SSLMode = "on" },
+			`POSTGRES_SSL must be one of disable, allow, prefer, require, verify-ca, verify-full, got "on"`),
+		Entry("negative statement timeout",
+			func(c *config.DatabaseConfig) { c.StatementTimeout = -1 },
+			"POSTGRES_STATEMENT_TIMEOUT can't be negative, got -1"),
+		Entry("more idle than open connections",
+			func(c *config.DatabaseConfig) { c.MaxIdleConnections, c.MaxOpenConnections = 20, 10 },
+			"POSTGRES_MAX_IDLE_CONNS (20) can't be more than POSTGRES_MAX_OPEN_CONNS (10)"),
+		Entry("no initial retry wait",
+			func(c *config.DatabaseConfig) { c.ConnectRetryInitial = 0 },
+			"POSTGRES_CONNECT_RETRY_INITIAL must be more than 0, got 0"),
+		Entry("no max retry wait",
+			func(c *config.DatabaseConfig) { c.ConnectRetryMax = 0 },
+			"POSTGRES_CONNECT_RETRY_MAX must be more than 0, got 0"),
+		Entry("initial retry wait above the max",
+			func(c *config.DatabaseConfig) { c.ConnectRetryInitial, c.ConnectRetryMax = 2000, 1000 },
+			"POSTGRES_CONNECT_RETRY_INITIAL (2000) can't be more than POSTGRES_CONNECT_RETRY_MAX (1000)"),
+	)
+
+	It("should report every invalid setting at once", func() {
+		dbConfig.Host = ""
+		dbConfig.Name = ""
+		dbConfig.MaxOpenConnections = -5
+
+		err := dbConfig.Validate()
+
+		Expect(err).To(MatchError(ContainSubstring("POSTGRES_HOSTNAME")))
+		Expect(err).To(MatchError(ContainSubstring("POSTGRES_DB")))
+		Expect(err).To(MatchError(ContainSubstring("POSTGRES_MAX_OPEN_CONNS")))
+	})
+
+	It("should accept the defaults", func() {
+		Expect(config.Default().Validate()).To(Succeed())
+	})
+
+	DescribeTable("should reject invalid settings outside the DB config",
+		func(update func(*config.Config), message string) {
+			cfg := config.Default()
+			update(cfg)
+
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("unknown error format",
+			func(c *config.Config) { c.Server.ErrorFormat = "xml" },
+			`ERROR_FORMAT must be one of default, problem, got "xml"`),
+		Entry("unknown log format",
+			func(c *config.Config) { c.Logging.Format = "logfmt" },
+			`LOG_FORMAT must be one of json, text, got "logfmt"`),
+		Entry("metrics on the API port",
+			func(c *config.Config) { c.Metrics.Port = c.Server.Port },
+			"METRICS_PORT can't be the same as PORT"),
+		Entry("file exporter without a file",
+			func(c *config.Config) { c.Tracing.Exporter, c.Tracing.File = "file", "" },
+			"TRACING_FILE is required"),
+		Entry("no health check timeout",
+			func(c *config.Config) { c.Health.Timeout = 0 },
+			"HEALTH_CHECK_TIMEOUT must be more than 0, got 0"),
+		Entry("SMTP host without a valid port",
+			func(c *config.Config) { c.Mailer.Host, c.Mailer.Port = "smtp.example.com", "smtp" },
+			`SMTP_PORT must be a port number, got "smtp"`),
+		Entry("relative password reset URL",
+			func(c *config.Config) { c.PasswordReset.URL = "/password-reset" },
+			`PASSWORD_RESET_URL must be an http or https URL, got "/password-reset"`),
+		Entry("no CORS origins",
+			func(c *config.Config) { c.Server.CORS.AllowOrigins = nil },
+			"CORS_ALLOW_ORIGINS is required"),
+		Entry("CORS origin with a path",
+			func(c *config.Config) { c.Server.CORS.AllowOrigins = []string{"https://app.example.com/"} },
+			`CORS_ALLOW_ORIGINS must be origins like https://app.example.com, got "https://app.example.com/"`),
+		Entry("CORS origin without a scheme",
+			func(c *config.Config) { c.Server.CORS.AllowOrigins = []string{"app.example.com"} },
+			`CORS_ALLOW_ORIGINS must be origins like https://app.example.com, got "app.example.com"`),
+		Entry("CORS origin with a wildcard that isn't the subdomain",
+			func(c *config.Config) { c.Server.CORS.AllowOrigins = []string{"https://app.*.com"} },
+			`CORS_ALLOW_ORIGINS can only have a wildcard for the subdomain like https://*.example.com, got "https://app.*.com"`),
+		Entry("CORS credentials for any origin",
+			func(c *config.Config) {
+				c.Server.CORS.AllowOrigins, c.Server.CORS.AllowCredentials = []string{"*"}, true
+			},
+			"CORS_ALLOW_CREDENTIALS can't be true when CORS_ALLOW_ORIGINS allows any origin"),
+		Entry("unknown CORS method",
+			func(c *config.Config) { c.Server.CORS.AllowMethods = []string{"get"} },
+			`CORS_ALLOW_METHODS must be one of GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS, got "get"`),
+		Entry("invalid CORS header",
+			func(c *config.Config) { c.Server.CORS.AllowHeaders = []string{"X Actor"} },
+			`CORS_ALLOW_HEADERS must be header names, got "X Actor"`),
+		Entry("negative CORS max age",
+			func(c *config.Config) { c.Server.CORS.MaxAge = -1 },
+			"CORS_MAX_AGE can't be negative, got -1"),
+		Entry("negative shutdown drain delay",
+			func(c *config.Config) { c.Server.ShutdownDrainDelay = -1 },
+			"SHUTDOWN_DRAIN_DELAY can't be negative, got -1"),
+		Entry("TLS certificate without a key",
+			func(c *config.Config) { c.Server.TLS.CertFile = "tls.crt" },
+			"TLS_KEY_FILE is required with TLS_CERT_FILE"),
+		Entry("TLS key without a certificate",
+			func(c *config.Config) { c.Server.TLS.KeyFile = "tls.key" },
+			"TLS_CERT_FILE is required with TLS_KEY_FILE"),
+		Entry("client CA bundle without TLS",
+			func(c *config.Config) { c.Server.TLS.ClientCAFile = "ca.crt" },
+			"TLS_CERT_FILE is required with TLS_CLIENT_CA_FILE"),
+		Entry("allowed clients without a client CA bundle",
+			func(c *config.Config) {
+				c.Server.TLS.CertFile, c.Server.TLS.KeyFile = "tls.crt", "tls.key"
+				c.Server.TLS.AllowedClients = []string{"billing-service"}
+			},
+			"TLS_CLIENT_CA_FILE is required with TLS_ALLOWED_CLIENTS"),
+		Entry("empty allowed client",
+			func(c *config.Config) {
+				c.Server.TLS.CertFile, c.Server.TLS.KeyFile = "tls.crt", "tls.key"
+				c.Server.TLS.ClientCAFile = "ca.crt"
+				c.Server.TLS.AllowedClients = []string{"billing-service", " "}
+			},
+			"TLS_ALLOWED_CLIENTS can't contain empty identities"),
+		Entry("unsupported TLS version",
+			func(c *config.Config) { c.Server.TLS.MinVersion = "1.1" },
+			`TLS_MIN_VERSION must be one of 1.2, 1.3, got "1.1"`),
+		Entry("unknown client auth",
+			func(c *config.Config) { c.Server.TLS.ClientAuth = "request" },
+			`TLS_CLIENT_AUTH must be one of require, optional, got "request"`),
+		Entry("unknown status to change from",
+			func(c *config.Config) { c.Users.StatusTransitions = map[string][]string{"X": {"A"}} },
+			`USER_STATUS_TRANSITIONS must be one of I, A, T, got "X"`),
+		Entry("unknown status to change to",
+			func(c *config.Config) { c.Users.StatusTransitions = map[string][]string{"A": {"I", "deleted"}} },
+			`USER_STATUS_TRANSITIONS must be one of I, A, T, got "deleted"`),
+	)
+
+	It("should accept wildcard subdomain and any CORS origins", func() {
+		cors := config.Default().Server.CORS
+		cors.AllowOrigins = []string{"https://*.example.com", "http://localhost:3000", "*"}
+
+		Expect(cors.Validate()).To(Succeed())
+	})
+
+	It("should not include the password of a URL that fails to parse", func() {
+		dbConfig.URL = "postgres://app:s3cret@db:port/app"
+
+		err := dbConfig.Validate()
+
+		Expect(err).To(MatchError("DATABASE_URL is not a valid URL"))
+	})
+})
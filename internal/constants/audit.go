@@ -0,0 +1,13 @@
+package constants
+
+const (
+	// Header used by clients to identify who is making a change
+	AuditActorHeader  = "X-Actor"
+	AuditActorDefault = "anonymous"
+	// Actor of changes made by clients without a verified certificate
+	// when clients are identified by certificate
+	AuditActorUnauthenticated = "unauthenticated"
+
+	AuditEntriesLimitDefault = 100
+	AuditEntriesLimitMax     = 1000
+)
@@ -1,7 +1,40 @@
 package constants
 
 const (
-	ServerPortDefault = "8080"
+	ServerPortDefault        = "8080"
+	ServerErrorFormatDefault = "default"
+	// Seconds in-flight requests are given to finish on shutdown
+	ServerShutdownTimeoutDefault = 15
+	// Seconds requests are still accepted after reporting not ready on
+	// shutdown
+	ServerShutdownDrainDelayDefault = 5
+	// Seconds between checks of the config file for changes
+	ServerConfigWatchIntervalDefault = 5
+
+	// The front end's dev server
+	CORSAllowOriginsDefault = "http://localhost:3000"
+	// Comma separated
+	CORSAllowMethodsDefault = "GET,HEAD,PUT,PATCH,POST,DELETE"
+	CORSAllowHeadersDefault = "Accept,Accept-Language,Content-Type,X-Request-ID,X-Actor"
+	// Seconds browsers can cache preflight responses for
+	CORSMaxAgeDefault = 600
+
+	TLSMinVersionDefault = "1.2"
+	TLSClientAuthDefault = "require"
+	// Seconds between checks of the certificate files for changes
+	TLSReloadIntervalDefault = 60
+
+	LogLevelDefault  = "info"
+	LogFormatDefault = "json"
+
+	MetricsEnabledDefault = true
+	MetricsPortDefault    = ""
+
+	HealthCheckTimeoutDefault = 2
+
+	TracingExporterDefault    = "none"
+	TracingFileDefault        = "traces.json"
+	TracingServiceNameDefault = "integra-partners-backend"
 
 	DBHostDefault               = "localhost"
 	DBUsernameDefault           = "postgres"
@@ -11,4 +44,33 @@ const (
 	DBSSLModeDefault            = "disable"
 	DBMaxIdleConnectionsDefault = 10
 	DBConnectionMaxIdleTime     = 5
+	DBQueryTimeoutDefault       = 10
+	DBMaxOpenConnectionsDefault = 25
+	// Minutes a connection is reused for
+	DBConnectionMaxLifetimeDefault = 30
+	DBStatementTimeoutDefault      = 0
+	DBApplicationNameDefault       = "integra-partners-backend"
+	// Seconds to keep retrying to connect on startup
+	DBConnectMaxWaitDefault = 60
+	// Milliseconds between the first attempts to connect and at most
+	DBConnectRetryInitialDefault = 500
+	DBConnectRetryMaxDefault     = 10000
+	// Seconds between checks of the connection after startup
+	DBMonitorIntervalDefault = 10
+	// Seconds between rereads of the DB password file
+	DBPasswordReloadIntervalDefault = 60
+
+	MailerHostDefault     = ""
+	MailerPortDefault     = "587"
+	MailerUsernameDefault = ""
+	MailerPasswordDefault = ""
+	MailerFromDefault     = "no-reply@integrapartners.com"
+
+	PasswordResetURLDefault             = "http://localhost:3000/password-reset"
+	PasswordResetTokenTTLDefault        = 30
+	PasswordResetRateLimitDefault       = 3
+	PasswordResetRateLimitWindowDefault = 60
+	PasswordResetMinResponseTimeDefault = 500
+
+	SchedulerIntervalDefault = 60
 )
@@ -1,5 +1,21 @@
 package constants
 
 const (
-	UsersTableName = "integra_partners.users"
+	UsersTableName                  = "integra_partners.users"
+	UserCredentialsTableName        = "integra_partners.user_credentials"
+	PasswordResetTokensTableName    = "integra_partners.password_reset_tokens"
+	AuditLogTableName               = "integra_partners.audit_log"
+	UsersHistoryTableName           = "integra_partners.users_history"
+	ScheduledStatusChangesTableName = "integra_partners.scheduled_status_changes"
+
+	// Table sqitch records the changes deployed to the DB in
+	SqitchChangesTableName = "sqitch.changes"
+)
+
+const (
+	// SqitchProject is the project name in sqitch/sqitch.plan
+	SqitchProject = "integra-partners-assessment-db"
+	// SchemaVersion is the last change in sqitch/sqitch.plan, which must
+	// be deployed for the app to be ready. Update it when adding a change.
+	SchemaVersion = "IPA-8/add_status_change_audit_actions"
 )
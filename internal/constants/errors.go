@@ -5,15 +5,63 @@ const (
 	// Contains all returned error messages for the clients
 	ErrDBRepoFailedToInitializeMessage = "failed to initialize DB"
 
-	ErrUsersRepoGetAllUsersDBQueryFailMessage = "failed to get users from records"
-	ErrUsersRepoCreateUserDBQueryFailMessage  = "failed to create user in records"
-	ErrUsersRepoUserDuplicateUserNameMessage  = "user with username already exists"
-	ErrUsersRepoUserDuplicateEmailMessage     = "user with email already exists"
-	ErrUsersRepoInvalidUserStatusMessage      = "input for user_status is invalid"
-	ErrUsersRepoUpdateUserDBQueryFailMessage  = "failed to update user in records"
-	ErrUsersRepoUpdateInvalidUserIdMessage    = "user Id is required to update the user"
-	ErrUsersRepoDeleteUserDBQueryFailMessage  = "failed to delete user from records"
+	ErrUsersRepoGetAllUsersDBQueryFailMessage    = "failed to get users from records"
+	ErrUsersRepoCreateUserDBQueryFailMessage     = "failed to create user in records"
+	ErrUsersRepoUserDuplicateUserNameMessage     = "user with username already exists"
+	ErrUsersRepoUserDuplicateEmailMessage        = "user with email already exists"
+	ErrUsersRepoInvalidUserStatusMessage         = "input for user_status is invalid"
+	ErrUsersRepoUpdateUserDBQueryFailMessage     = "failed to update user in records"
+	ErrUsersRepoUpdateInvalidUserIdMessage       = "user Id is required to update the user"
+	ErrUsersRepoDeleteUserDBQueryFailMessage     = "failed to delete user from records"
+	ErrUsersRepoGetUserDBQueryFailMessage        = "failed to get user from records"
+	ErrUsersRepoGetUserHistoryDBQueryFailMessage = "failed to get user history from records"
 
 	ErrUsersControllerUserFailedToBindBodyFailMessage = "user input body is invalid"
 	ErrUsersControllerInvalidUserIdParamMessage       = "user id passed as URL param is invalid"
+	ErrUsersControllerInvalidAsOfParamMessage         = "as_of query param must be an RFC 3339 timestamp"
+
+	ErrPasswordResetRepoCreateTokenDBQueryFailMessage   = "failed to create password reset token in records"
+	ErrPasswordResetRepoResetPasswordDBQueryFailMessage = "failed to reset password in records"
+
+	ErrPasswordResetControllerFailedToBindBodyMessage = "password reset input body is invalid"
+	ErrPasswordResetControllerInvalidEmailMessage     = "email is required to reset a password"
+	ErrPasswordResetControllerInvalidPasswordMessage  = "password must be between 8 and 72 bytes"
+	ErrPasswordResetControllerInvalidTokenMessage     = "password reset token is invalid or has expired"
+	ErrPasswordResetControllerRateLimitedMessage      = "too many password reset requests, try again later"
+	ErrPasswordResetControllerFailedToHashMessage     = "failed to process password reset"
+
+	ErrAuditRepoCreateEntryDBQueryFailMessage = "failed to create audit entry in records"
+	ErrAuditRepoGetEntriesDBQueryFailMessage  = "failed to get audit entries from records"
+
+	ErrAuditControllerInvalidFilterMessage = "audit filter query params are invalid"
+
+	ErrStatusChangesRepoCreateDBQueryFailMessage = "failed to create scheduled status change in records"
+	ErrStatusChangesRepoGetDBQueryFailMessage    = "failed to get scheduled status changes from records"
+	ErrStatusChangesRepoCancelDBQueryFailMessage = "failed to cancel scheduled status change in records"
+	ErrStatusChangesRepoApplyDBQueryFailMessage  = "failed to apply scheduled status change in records"
+	ErrStatusChangesRepoUserNotFoundMessage      = "user for scheduled status change does not exist"
+
+	ErrStatusChangesControllerFailedToBindBodyMessage   = "scheduled status change input body is invalid"
+	ErrStatusChangesControllerInvalidChangeIdMessage    = "scheduled status change id passed as URL param is invalid"
+	ErrStatusChangesControllerInvalidEffectiveAtMessage = "effective_at is required to schedule a status change"
+
+	ErrUsersRepoIllegalStatusTransitionMessage = "user status cannot change from its current status to the requested status"
+
+	ErrUsersControllerUserValidationFailedMessage = "user input body has invalid fields"
+
+	ErrDBRepoPingFailedMessage             = "failed to reach DB"
+	ErrDBRepoSchemaVersionQueryFailMessage = "failed to get deployed DB schema version"
+
+	ErrDBRepoInvalidConfigMessage = "DB config is invalid"
+
+	// Field messages are formatted with the parameters of the check
+	ErrFieldRequiredMessage     = "is required"
+	ErrFieldTooLongMessage      = "must be at most %v characters"
+	ErrFieldInvalidEmailMessage = "must be a valid email address"
+	ErrFieldInvalidValueMessage = "must be one of %v"
+	ErrFieldInvalidTypeMessage  = "must be of type %v"
+
+	ErrIdentityClientNotAllowedMessage = "client is not allowed to call the API"
+
+	ErrAuditControllerInvalidActorMessage = "actor header is invalid"
 )
@@ -0,0 +1,9 @@
+package constants
+
+const (
+	// Max lengths match the VARCHAR sizes of the users table columns
+	UserNameMaxLength   = 50
+	UserFieldsMaxLength = 255
+	// Matches the VARCHAR size of the actor columns of the audit tables
+	AuditActorMaxLength = 255
+)
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/identity"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/requestid"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type AuditController struct {
+	Controller
+	Repo database.Repo
+}
+
+// createDefault will update itself with necessary components
+func (ac AuditController) createDefault(deps Dependencies) Controller {
+	return &AuditController{
+		Repo: deps.Repo,
+	}
+}
+
+// registerRoutes will register all controller routes to the Echo instance
+func (ac AuditController) registerRoutes(e *echo.Echo) Controller {
+	e.GET("/audit", ac.GetAuditEntries)
+
+	return ac
+}
+
+// @Summary Returns audit entries for user changes
+// @Description Show the audit entries for every user create, update and delete, newest first
+// @Tags 	Audit
+// @Produce json
+// @Param 	user_id query int false "Only return entries for this user"
+// @Param 	actor query string false "Only return entries made by this actor"
+// @Param 	from query string false "Only return entries made at or after this RFC 3339 time"
+// @Param 	to query string false "Only return entries made before this RFC 3339 time"
+// @Param 	limit query int false "Maximum number of entries to return, defaults to 100"
+// @Success 200 {object} response.Response{data=[]models.AuditEntry,error_code=nil,error_message=nil}
+// @Failure 400 {object} response.Response{data=nil,error_code=int,error_message=string}
+// @Failure 500 {object} response.Response{data=nil,error_code=int,error_message=string}
+// @Router	/audit		 [get]
+func (ac AuditController) GetAuditEntries(ctx echo.Context) error {
+	filter, err := parseAuditFilter(ctx)
+	if err != nil {
+		return errors.New(errors.AuditControllerInvalidFilter, err)
+	}
+
+	entries, err := ac.Repo.GetAuditEntries(ctx.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response.Success(entries))
+}
+
+// parseAuditFilter creates an AuditFilter from the request query params.
+//
+// Returns an error if any of the params fail to parse.
+func parseAuditFilter(ctx echo.Context) (models.AuditFilter, error) {
+	filter := models.AuditFilter{
+		Actor: ctx.QueryParam("actor"),
+		Limit: constants.AuditEntriesLimitDefault,
+	}
+
+	if userId := ctx.QueryParam("user_id"); userId != "" {
+		id, err := strconv.Atoi(userId)
+		if err != nil {
+			return filter, err
+		}
+		filter.UserId = id
+	}
+
+	if from := ctx.QueryParam("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return filter, err
+		}
+		filter.From = t
+	}
+
+	if to := ctx.QueryParam("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return filter, err
+		}
+		filter.To = t
+	}
+
+	if limit := ctx.QueryParam("limit"); limit != "" {
+		l, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			return filter, err
+		}
+		filter.Limit = min(l, constants.AuditEntriesLimitMax)
+	}
+
+	return filter, nil
+}
+
+// ignoreActorHeader reports whether the actor header is ignored, which is
+// when clients are identified by certificate so the header can't be used
+// to claim another client's identity
+func ignoreActorHeader(config *config.Config) bool {
+	return config != nil && config.Server.TLS.ClientCAFile != ""
+}
+
+// auditInfo returns who is making the request and the request's id so
+// changes can be recorded in the audit log.
+//
+// The actor is the identity of the client's verified certificate when
+// served over mutual TLS, as the header can be set by anyone. When the
+// header is ignored, clients without one are recorded as unauthenticated.
+// The request id is the one assigned by the requestid middleware.
+//
+// Returns an error with the field that failed validation if the header is
+// too long to be recorded.
+func auditInfo(ctx echo.Context, ignoreHeader bool) (models.AuditInfo, error) {
+	actor := identity.FromContext(ctx.Request().Context())
+	if actor == "" && ignoreHeader {
+		actor = constants.AuditActorUnauthenticated
+	}
+	if actor == "" {
+		actor = ctx.Request().Header.Get(constants.AuditActorHeader)
+		if fieldErrs := validation.ValidateActor(actor); len(fieldErrs) > 0 {
+			return models.AuditInfo{}, errors.New(errors.AuditControllerInvalidActor, nil).WithFields(fieldErrs)
+		}
+	}
+	if actor == "" {
+		actor = constants.AuditActorDefault
+	}
+
+	return models.AuditInfo{
+		Actor:     actor,
+		RequestId: requestid.FromContext(ctx.Request().Context()),
+	}, nil
+}
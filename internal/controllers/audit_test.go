@@ -0,0 +1,163 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/controllers"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AuditController", Ordered, func() {
+
+	var (
+		mockCtrl *gomock.Controller
+		mockRepo *mocks.MockIRepo
+		e        *echo.Echo
+
+		rec *httptest.ResponseRecorder
+	)
+
+	BeforeAll(func() {
+		mockLogger := mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+	})
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockIRepo(mockCtrl)
+		e = echo.New()
+		e.HTTPErrorHandler = response.NewHTTPErrorHandler(e, response.FormatDefault)
+
+		rec = httptest.NewRecorder()
+	})
+
+	Describe("GetAuditEntries", func() {
+
+		It("should return audit entries with the default limit", func() {
+			expected := []models.AuditEntry{
+				{
+					AuditId:   1,
+					Actor:     "admin",
+					Action:    models.AuditActionCreate,
+					UserId:    1,
+					Changes:   json.RawMessage(`{"user_id":{"before":null,"after":1}}`),
+					RequestId: "request-1",
+					CreatedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+				},
+			}
+			ctx := e.NewContext(createTestRequest(http.MethodGet, "/audit", nil), rec)
+
+			mockRepo.EXPECT().
+				GetAuditEntries(gomock.Any(), models.AuditFilter{Limit: constants.AuditEntriesLimitDefault}).
+				Return(expected, nil)
+			auditController := &controllers.AuditController{
+				Repo: mockRepo,
+			}
+			serve(ctx, auditController.GetAuditEntries)
+
+			b, _ := json.Marshal(response.Success(expected))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should pass the query params as filters", func() {
+			ctx := e.NewContext(createTestRequest(
+				http.MethodGet,
+				"/audit?user_id=1&actor=admin&from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z&limit=5000",
+				nil), rec)
+
+			mockRepo.EXPECT().
+				GetAuditEntries(gomock.Any(), models.AuditFilter{
+					UserId: 1,
+					Actor:  "admin",
+					From:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+					To:     time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+					Limit:  constants.AuditEntriesLimitMax,
+				}).
+				Return([]models.AuditEntry{}, nil)
+			auditController := &controllers.AuditController{
+				Repo: mockRepo,
+			}
+			serve(ctx, auditController.GetAuditEntries)
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should fail when a filter is invalid", func() {
+			expectedCode := ipErrors.AuditControllerInvalidFilter
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			ctx := e.NewContext(createTestRequest(http.MethodGet, "/audit?from=yesterday", nil), rec)
+
+			auditController := &controllers.AuditController{
+				Repo: mockRepo,
+			}
+			serve(ctx, auditController.GetAuditEntries)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should return a problem document when the client accepts one", func() {
+			expectedCode := ipErrors.AuditControllerInvalidFilter
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			req := createTestRequest(http.MethodGet, "/audit?limit=-1", nil)
+			req.Header.Set(echo.HeaderAccept, response.MIMEApplicationProblemJSON)
+			req.Header.Set(echo.HeaderXRequestID, "request-1")
+			ctx := e.NewContext(req, rec)
+
+			auditController := &controllers.AuditController{
+				Repo: mockRepo,
+			}
+			serve(ctx, auditController.GetAuditEntries)
+
+			b, _ := json.Marshal(response.NewProblem(
+				http.StatusBadRequest,
+				response.Failure(expectedCode, expectedMsg),
+				"request-1",
+				ipErrors.DefaultLocale))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(rec.Header().Get(echo.HeaderContentType)).To(Equal(response.MIMEApplicationProblemJSON))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should return error when DB throws error", func() {
+			expectedCode := ipErrors.AuditRepoGetEntriesDBQueryFail
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			ctx := e.NewContext(createTestRequest(http.MethodGet, "/audit", nil), rec)
+
+			mockRepo.EXPECT().
+				GetAuditEntries(gomock.Any(), gomock.Any()).
+				Return([]models.AuditEntry{}, ipErrors.New(expectedCode, errors.New("DB had an error!")))
+			auditController := &controllers.AuditController{
+				Repo: mockRepo,
+			}
+			serve(ctx, auditController.GetAuditEntries)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+	})
+})
@@ -1,22 +1,37 @@
 package controllers
 
 import (
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/health"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mailer"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/ratelimit"
 	"github.com/labstack/echo/v4"
 )
 
+// Dependencies contains the shared resources controllers are created with.
+type Dependencies struct {
+	Repo   database.Repo
+	Mailer mailer.Mailer
+	Config *config.Config
+	Health *health.Checker
+	// Limits password reset requests, shared with the app so its limit
+	// can be changed when the config is reloaded
+	ResetLimiter *ratelimit.Limiter
+}
+
 type Controller interface {
-	createDefault(repo database.Repo) Controller
+	createDefault(deps Dependencies) Controller
 	registerRoutes(e *echo.Echo) Controller
 }
 
 // New creates a new instance of the controller and initializes it
-// with a Repo instance.
+// with the shared Dependencies.
 // It will also call the Controllers RegisterRoutes function to allow
 // it to register any routes it may control.
-func Initialize[T Controller](repo database.Repo, e *echo.Echo) {
+func Initialize[T Controller](deps Dependencies, e *echo.Echo) {
 	var controller T
 	controller.
-		createDefault(repo).
+		createDefault(deps).
 		registerRoutes(e)
-}
\ No newline at end of file
+}
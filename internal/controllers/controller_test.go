@@ -6,25 +6,66 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/controllers"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
 )
 
 var _ = Describe("Controller", func() {
 
 	Describe("Initialize", Ordered, func() {
 		var repo database.ServiceRepo
+		var deps controllers.Dependencies
 		var e *echo.Echo
 
 		BeforeAll(func() {
 			repo = database.CreateDefault()
+			deps = controllers.Dependencies{
+				Repo:   &repo,
+				Mailer: mocks.NewMockMailer(),
+				Config: config.Default(),
+			}
+		})
+
+		BeforeEach(func() {
 			e = echo.New()
 		})
 
 		It("should create new user controller", func() {
-			controllers.Initialize[controllers.UserController](&repo, e)
+			controllers.Initialize[controllers.UserController](deps, e)
+
+			Expect(len(e.Routes())).To(Equal(6))
+		})
+
+		It("should create new password reset controller", func() {
+			controllers.Initialize[controllers.PasswordResetController](deps, e)
+
+			Expect(len(e.Routes())).To(Equal(2))
+		})
+
+		It("should create new audit controller", func() {
+			controllers.Initialize[controllers.AuditController](deps, e)
+
+			Expect(len(e.Routes())).To(Equal(1))
+		})
+
+		It("should create new status change controller", func() {
+			controllers.Initialize[controllers.StatusChangeController](deps, e)
+
+			Expect(len(e.Routes())).To(Equal(3))
+		})
+
+		It("should create new error controller", func() {
+			controllers.Initialize[controllers.ErrorController](deps, e)
+
+			Expect(len(e.Routes())).To(Equal(1))
+		})
+
+		It("should create new health controller", func() {
+			controllers.Initialize[controllers.HealthController](deps, e)
 
-			Expect(len(e.Routes())).To(Equal(4))
+			Expect(len(e.Routes())).To(Equal(2))
 		})
 	})
 })
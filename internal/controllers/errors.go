@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/labstack/echo/v4"
+)
+
+type ErrorController struct {
+	Controller
+}
+
+// createDefault will update itself with necessary components
+func (ec ErrorController) createDefault(deps Dependencies) Controller {
+	return &ErrorController{}
+}
+
+// registerRoutes will register all controller routes to the Echo instance
+func (ec ErrorController) registerRoutes(e *echo.Echo) Controller {
+	e.GET("/errors", ec.GetErrorCatalog)
+
+	return ec
+}
+
+// @Summary Returns every error code
+// @Description Show the code, symbolic name, message and HTTP status of every error the API can respond with
+// @Tags 	Errors
+// @Produce json
+// @Success 200 {object} response.Response{data=[]errors.CatalogEntry,error_code=nil,error_message=nil}
+// @Router	/errors		 [get]
+func (ec ErrorController) GetErrorCatalog(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, response.Success(errors.Catalog()))
+}
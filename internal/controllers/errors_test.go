@@ -0,0 +1,45 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/controllers"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ErrorController", func() {
+
+	var (
+		e   *echo.Echo
+		rec *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		e = echo.New()
+		e.HTTPErrorHandler = response.NewHTTPErrorHandler(e, response.FormatDefault)
+
+		rec = httptest.NewRecorder()
+	})
+
+	Describe("GetErrorCatalog", func() {
+
+		It("should return every error code", func() {
+			ctx := e.NewContext(createTestRequest(http.MethodGet, "/errors", nil), rec)
+
+			errorController := &controllers.ErrorController{}
+			serve(ctx, errorController.GetErrorCatalog)
+
+			b, _ := json.Marshal(response.Success(ipErrors.Catalog()))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+	})
+})
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/health"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/labstack/echo/v4"
+)
+
+type HealthController struct {
+	Controller
+	Health *health.Checker
+}
+
+// createDefault will update itself with necessary components
+func (hc HealthController) createDefault(deps Dependencies) Controller {
+	return &HealthController{
+		Health: deps.Health,
+	}
+}
+
+// registerRoutes will register all controller routes to the Echo instance
+func (hc HealthController) registerRoutes(e *echo.Echo) Controller {
+	e.GET("/healthz", hc.GetLiveness)
+	e.GET("/readyz", hc.GetReadiness)
+
+	return hc
+}
+
+// @Summary Returns whether the process is alive
+// @Description Always up while the process can serve requests, regardless of the DB
+// @Tags 	Health
+// @Produce json
+// @Success 200 {object} response.Response{data=health.Report,error_code=nil,error_message=nil}
+// @Router	/healthz		 [get]
+func (hc HealthController) GetLiveness(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, response.Success(hc.Health.Live()))
+}
+
+// @Summary Returns whether the app is ready to serve traffic
+// @Description Show the status of each component the app depends on. Down if the app is shutting down, the DB can't be reached, or the DB schema is behind.
+// @Tags 	Health
+// @Produce json
+// @Success 200 {object} response.Response{data=health.Report,error_code=nil,error_message=nil}
+// @Failure 503 {object} response.Response{data=health.Report,error_code=nil,error_message=nil}
+// @Router	/readyz		 [get]
+func (hc HealthController) GetReadiness(ctx echo.Context) error {
+	report := hc.Health.Ready(ctx.Request().Context())
+
+	status := http.StatusOK
+	if report.Status != health.StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	return ctx.JSON(status, response.Success(report))
+}
@@ -0,0 +1,98 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/controllers"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/health"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+)
+
+var _ = Describe("HealthController", func() {
+
+	var (
+		mockCtrl         *gomock.Controller
+		mockRepo         *mocks.MockIRepo
+		e                *echo.Echo
+		rec              *httptest.ResponseRecorder
+		healthController *controllers.HealthController
+	)
+
+	// readReport decodes the report from the response body
+	readReport := func() health.Report {
+		var body struct {
+			Data health.Report `json:"data"`
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+		return body.Data
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockIRepo(mockCtrl)
+
+		e = echo.New()
+		e.HTTPErrorHandler = response.NewHTTPErrorHandler(e, response.FormatDefault)
+
+		rec = httptest.NewRecorder()
+		healthController = &controllers.HealthController{
+			Health: health.NewChecker(mockRepo, time.Second),
+		}
+	})
+
+	Describe("GetLiveness", func() {
+		It("should return up without checking the DB", func() {
+			ctx := e.NewContext(createTestRequest(http.MethodGet, "/healthz", nil), rec)
+
+			serve(ctx, healthController.GetLiveness)
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(readReport().Status).To(Equal(health.StatusUp))
+		})
+	})
+
+	Describe("GetReadiness", func() {
+		It("should return 200 with the component statuses when ready", func() {
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+			mockRepo.EXPECT().SchemaChangeDeployed(gomock.Any(), gomock.Any()).Return(true, nil)
+			ctx := e.NewContext(createTestRequest(http.MethodGet, "/readyz", nil), rec)
+
+			serve(ctx, healthController.GetReadiness)
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(readReport().Components).To(HaveLen(3))
+		})
+
+		It("should return 503 when the DB is down", func() {
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(ipErrors.New(ipErrors.DBRepoPingFailed, nil))
+			ctx := e.NewContext(createTestRequest(http.MethodGet, "/readyz", nil), rec)
+
+			serve(ctx, healthController.GetReadiness)
+
+			Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(readReport().Components[health.ComponentDatabase].Status).To(Equal(health.StatusDown))
+		})
+
+		It("should return 503 once shutting down", func() {
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+			mockRepo.EXPECT().SchemaChangeDeployed(gomock.Any(), gomock.Any()).Return(true, nil)
+			healthController.Health.SetShuttingDown()
+			ctx := e.NewContext(createTestRequest(http.MethodGet, "/readyz", nil), rec)
+
+			serve(ctx, healthController.GetReadiness)
+
+			Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(readReport().Components[health.ComponentServer].Status).To(Equal(health.StatusDown))
+		})
+	})
+})
@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mailer"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/ratelimit"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/labstack/echo/v4"
+)
+
+// Password lengths are in bytes rather than characters, as bcrypt ignores
+// any bytes past 72
+const (
+	passwordMinLength = 8
+	passwordMaxLength = 72
+)
+
+type PasswordResetController struct {
+	Controller
+	Repo database.Repo
+	// Expected to send in the background, such as a mailer.Queue, so the
+	// time taken to send can not be measured by the client
+	Mailer  mailer.Mailer
+	Limiter *ratelimit.Limiter
+	Config  config.PasswordResetConfig
+}
+
+// createDefault will update itself with necessary components
+func (pc PasswordResetController) createDefault(deps Dependencies) Controller {
+	resetConfig := deps.Config.PasswordReset
+
+	limiter := deps.ResetLimiter
+	if limiter == nil {
+		limiter = ratelimit.NewLimiter(
+			resetConfig.RateLimit,
+			time.Duration(resetConfig.RateLimitWindow)*time.Minute)
+	}
+
+	return &PasswordResetController{
+		Repo:    deps.Repo,
+		Mailer:  deps.Mailer,
+		Limiter: limiter,
+		Config:  resetConfig,
+	}
+}
+
+// registerRoutes will register all controller routes to the Echo instance
+func (pc PasswordResetController) registerRoutes(e *echo.Echo) Controller {
+	e.POST("/password-reset", pc.RequestPasswordReset)
+	e.POST("/password-reset/complete", pc.CompletePasswordReset)
+
+	return pc
+}
+
+// @Summary Requests a password reset email
+// @Description Emails a single-use password reset token to the user with the email.
+// @Description The response is the same whether or not the email exists.
+// @Tags 	Password Reset
+// @Produce json
+// @Param	request body models.PasswordResetRequest true "Email of the user to reset"
+// @Success 202 {object} response.Response{data=nil,error_code=nil,error_message=nil}
+// @Failure 400 {object} response.Response{data=nil,error_code=int,error_message=string}
+// @Failure 429 {object} response.Response{data=nil,error_code=int,error_message=string}
+// @Router	/password-reset		 [post]
+func (pc PasswordResetController) RequestPasswordReset(ctx echo.Context) error {
+	// Pad every response to the same minimum duration so that the work done
+	// for an existing email can not be timed by the client
+	defer waitUntil(time.Now().Add(time.Duration(pc.Config.MinResponseTime) * time.Millisecond))
+
+	request := models.PasswordResetRequest{}
+	if err := ctx.Bind(&request); err != nil {
+		return errors.New(errors.PasswordResetControllerFailedToBindBody, err)
+	}
+
+	email := strings.ToLower(strings.TrimSpace(request.Email))
+	if email == "" {
+		return errors.New(errors.PasswordResetControllerInvalidEmail, nil)
+	}
+
+	if !pc.Limiter.Allow(email) {
+		return errors.New(errors.PasswordResetControllerRateLimited, nil)
+	}
+
+	token, tokenHash, err := generateResetToken()
+	if err != nil {
+		return errors.New(errors.PasswordResetControllerFailedToHash, err)
+	}
+
+	expiresAt := time.Now().Add(time.Duration(pc.Config.TokenTTL) * time.Minute)
+	exists, err := pc.Repo.CreatePasswordResetToken(ctx.Request().Context(), email, tokenHash, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		err := pc.Mailer.Send(mailer.Message{
+			To:      email,
+			Subject: "Reset your password",
+			Body: fmt.Sprintf(
+				"A password reset was requested for your account.\n\n"+
+					"Use the link below to choose a new password. It expires in %d minutes.\n\n%s?token=%s\n\n"+
+					"If you did not request a reset, you can ignore this email.",
+				pc.Config.TokenTTL,
+				pc.Config.URL,
+				token),
+		})
+
+		// Failing to queue is only logged, reporting it would reveal
+		// that the email exists
+		if err != nil {
+			logging.ErrorContext(ctx.Request().Context(), "RequestPasswordReset", "failed to queue password reset email", err)
+		}
+	}
+
+	return ctx.JSON(http.StatusAccepted, response.Success(nil))
+}
+
+// @Summary Completes a password reset
+// @Description Sets a new password for the user the reset token was issued to.
+// @Description Tokens can only be used once and expire after a configured time.
+// @Description The password must be between 8 and 72 bytes long when UTF-8 encoded.
+// @Tags 	Password Reset
+// @Produce json
+// @Param	completion body models.PasswordResetCompletion true "Reset token and the new password"
+// @Success 200 {object} response.Response{data=nil,error_code=nil,error_message=nil}
+// @Failure 400 {object} response.Response{data=nil,error_code=int,error_message=string}
+// @Failure 500 {object} response.Response{data=nil,error_code=int,error_message=string}
+// @Router	/password-reset/complete		 [post]
+func (pc PasswordResetController) CompletePasswordReset(ctx echo.Context) error {
+	completion := models.PasswordResetCompletion{}
+	if err := ctx.Bind(&completion); err != nil {
+		return errors.New(errors.PasswordResetControllerFailedToBindBody, err)
+	}
+
+	if completion.Token == "" {
+		return errors.New(errors.PasswordResetControllerInvalidToken, nil)
+	}
+
+	if len(completion.Password) < passwordMinLength || len(completion.Password) > passwordMaxLength {
+		return errors.New(errors.PasswordResetControllerInvalidPassword, nil)
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(completion.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New(errors.PasswordResetControllerFailedToHash, err)
+	}
+
+	reset, err := pc.Repo.ResetPassword(ctx.Request().Context(), hashResetToken(completion.Token), string(passwordHash))
+	if err != nil {
+		return err
+	}
+
+	if !reset {
+		return errors.New(errors.PasswordResetControllerInvalidToken, nil)
+	}
+
+	return ctx.JSON(http.StatusOK, response.Success(nil))
+}
+
+// generateResetToken creates a random URL safe token along with the hash
+// of it that is stored in the DB.
+func generateResetToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashResetToken(token), nil
+}
+
+// hashResetToken returns the hex encoded SHA-256 hash of the token.
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// waitUntil sleeps until the deadline has passed.
+func waitUntil(deadline time.Time) {
+	time.Sleep(time.Until(deadline))
+}
@@ -0,0 +1,344 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/controllers"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mailer"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/ratelimit"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PasswordResetController", Ordered, func() {
+
+	var (
+		mockCtrl   *gomock.Controller
+		mockRepo   *mocks.MockIRepo
+		mockMailer mocks.MockMailer
+		e          *echo.Echo
+
+		controller *controllers.PasswordResetController
+
+		req *http.Request
+		rec *httptest.ResponseRecorder
+		ctx echo.Context
+	)
+
+	BeforeAll(func() {
+		mockLogger := mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+	})
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockIRepo(mockCtrl)
+		mockMailer = mocks.NewMockMailer()
+		e = echo.New()
+		e.HTTPErrorHandler = response.NewHTTPErrorHandler(e, response.FormatDefault)
+
+		rec = httptest.NewRecorder()
+
+		resetConfig := config.Default().PasswordReset
+		resetConfig.MinResponseTime = 0
+
+		controller = &controllers.PasswordResetController{
+			Repo:    mockRepo,
+			Mailer:  mockMailer,
+			Limiter: ratelimit.NewLimiter(1, time.Minute),
+			Config:  resetConfig,
+		}
+	})
+
+	Describe("RequestPasswordReset", func() {
+		var input models.PasswordResetRequest
+
+		BeforeEach(func() {
+			input = models.PasswordResetRequest{Email: "Test@User.com"}
+		})
+
+		It("should email a reset token when the email exists", func() {
+			req = createTestRequest(http.MethodPost, "/password-reset", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			var storedHash string
+			mockRepo.EXPECT().
+				CreatePasswordResetToken(gomock.Any(), "test@user.com", gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, email string, tokenHash string, expiresAt time.Time) (bool, error) {
+					storedHash = tokenHash
+					return true, nil
+				})
+			serve(ctx, controller.RequestPasswordReset)
+
+			b, _ := json.Marshal(response.Success(nil))
+
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+
+			sent := mockMailer.SentMessages()
+			Expect(len(sent)).To(Equal(1))
+			Expect(sent[0].To).To(Equal("test@user.com"))
+			// Only the hash is stored, the raw token is only in the email
+			Expect(sent[0].Body).To(ContainSubstring("?token="))
+			Expect(sent[0].Body).NotTo(ContainSubstring(storedHash))
+		})
+
+		It("should respond the same without emailing when the email does not exist", func() {
+			req = createTestRequest(http.MethodPost, "/password-reset", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			mockRepo.EXPECT().
+				CreatePasswordResetToken(gomock.Any(), "test@user.com", gomock.Any(), gomock.Any()).
+				Return(false, nil)
+			serve(ctx, controller.RequestPasswordReset)
+
+			b, _ := json.Marshal(response.Success(nil))
+
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+			Expect(len(mockMailer.SentMessages())).To(Equal(0))
+		})
+
+		It("should respond the same when the email fails to send", func() {
+			req = createTestRequest(http.MethodPost, "/password-reset", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			mockMailer.Err = errors.New("SMTP is down!")
+			controller.Mailer = mockMailer
+
+			mockRepo.EXPECT().
+				CreatePasswordResetToken(gomock.Any(), "test@user.com", gomock.Any(), gomock.Any()).
+				Return(true, nil)
+			serve(ctx, controller.RequestPasswordReset)
+
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+		})
+
+		It("should wait for the minimum response time", func() {
+			req = createTestRequest(http.MethodPost, "/password-reset", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			controller.Config.MinResponseTime = 50
+
+			mockRepo.EXPECT().
+				CreatePasswordResetToken(gomock.Any(), "test@user.com", gomock.Any(), gomock.Any()).
+				Return(false, nil)
+
+			start := time.Now()
+			serve(ctx, controller.RequestPasswordReset)
+
+			Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+		})
+
+		It("should respond as quickly whether or not the email exists when sending is slow", func() {
+			slowMailer := mocks.NewMockMailer()
+			slowMailer.Delay = 200 * time.Millisecond
+			queue := mailer.NewQueue(slowMailer, 1)
+			queue.Start()
+			controller.Mailer = queue
+
+			mockRepo.EXPECT().
+				CreatePasswordResetToken(gomock.Any(), "test@user.com", gomock.Any(), gomock.Any()).
+				Return(true, nil)
+			mockRepo.EXPECT().
+				CreatePasswordResetToken(gomock.Any(), "unknown@user.com", gomock.Any(), gomock.Any()).
+				Return(false, nil)
+
+			// timeRequest returns how long a reset for the email takes
+			timeRequest := func(email string) time.Duration {
+				req = createTestRequest(http.MethodPost, "/password-reset", models.PasswordResetRequest{Email: email})
+				req.Header.Add("Content-Type", "application/json")
+				rec = httptest.NewRecorder()
+
+				start := time.Now()
+				serve(e.NewContext(req, rec), controller.RequestPasswordReset)
+				elapsed := time.Since(start)
+
+				Expect(rec.Code).To(Equal(http.StatusAccepted))
+				return elapsed
+			}
+
+			existing := timeRequest("test@user.com")
+			unknown := timeRequest("unknown@user.com")
+
+			// Neither waits for the email to be sent
+			Expect(existing).To(BeNumerically("<", slowMailer.Delay))
+			Expect(unknown).To(BeNumerically("<", slowMailer.Delay))
+
+			queue.Stop()
+			Expect(len(slowMailer.SentMessages())).To(Equal(1))
+		})
+
+		It("should rate limit requests per email address", func() {
+			expectedCode := ipErrors.PasswordResetControllerRateLimited
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			mockRepo.EXPECT().
+				CreatePasswordResetToken(gomock.Any(), "test@user.com", gomock.Any(), gomock.Any()).
+				Return(true, nil).
+				Times(1)
+
+			req = createTestRequest(http.MethodPost, "/password-reset", input)
+			req.Header.Add("Content-Type", "application/json")
+			controller.RequestPasswordReset(e.NewContext(req, httptest.NewRecorder()))
+
+			req = createTestRequest(http.MethodPost, "/password-reset", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+			serve(ctx, controller.RequestPasswordReset)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+			Expect(len(mockMailer.SentMessages())).To(Equal(1))
+		})
+
+		It("should fail if the email is not passed", func() {
+			expectedCode := ipErrors.PasswordResetControllerInvalidEmail
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			req = createTestRequest(http.MethodPost, "/password-reset", models.PasswordResetRequest{})
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+			serve(ctx, controller.RequestPasswordReset)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should fail when DB returns an error", func() {
+			expectedCode := ipErrors.PasswordResetRepoCreateTokenDBQueryFail
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			req = createTestRequest(http.MethodPost, "/password-reset", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			mockRepo.EXPECT().
+				CreatePasswordResetToken(gomock.Any(), "test@user.com", gomock.Any(), gomock.Any()).
+				Return(false, ipErrors.New(expectedCode, errors.New("DB error occurred!")))
+			serve(ctx, controller.RequestPasswordReset)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+	})
+
+	Describe("CompletePasswordReset", func() {
+
+		It("should reset the password with a valid token", func() {
+			input := models.PasswordResetCompletion{Token: "token", Password: "newPassword!"}
+			req = createTestRequest(http.MethodPost, "/password-reset/complete", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			mockRepo.EXPECT().
+				ResetPassword(gomock.Any(), gomock.Not("token"), gomock.Not("newPassword!")).
+				Return(true, nil)
+			serve(ctx, controller.CompletePasswordReset)
+
+			b, _ := json.Marshal(response.Success(nil))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should fail when the token is invalid, used or expired", func() {
+			expectedCode := ipErrors.PasswordResetControllerInvalidToken
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			input := models.PasswordResetCompletion{Token: "token", Password: "newPassword!"}
+			req = createTestRequest(http.MethodPost, "/password-reset/complete", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			mockRepo.EXPECT().
+				ResetPassword(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(false, nil)
+			serve(ctx, controller.CompletePasswordReset)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should fail when the password is too short", func() {
+			expectedCode := ipErrors.PasswordResetControllerInvalidPassword
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			input := models.PasswordResetCompletion{Token: "token", Password: "short"}
+			req = createTestRequest(http.MethodPost, "/password-reset/complete", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			serve(ctx, controller.CompletePasswordReset)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should count the password length in bytes", func() {
+			expectedCode := ipErrors.PasswordResetControllerInvalidPassword
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			// 37 characters, but 74 bytes
+			input := models.PasswordResetCompletion{Token: "token", Password: strings.Repeat("é", 37)}
+			req = createTestRequest(http.MethodPost, "/password-reset/complete", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			serve(ctx, controller.CompletePasswordReset)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should fail when DB returns an error", func() {
+			expectedCode := ipErrors.PasswordResetRepoResetPasswordDBQueryFail
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			input := models.PasswordResetCompletion{Token: "token", Password: "newPassword!"}
+			req = createTestRequest(http.MethodPost, "/password-reset/complete", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			mockRepo.EXPECT().
+				ResetPassword(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(false, ipErrors.New(expectedCode, errors.New("DB error occurred!")))
+			serve(ctx, controller.CompletePasswordReset)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+	})
+})
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type StatusChangeController struct {
+	Controller
+	Repo database.Repo
+	// Whether the actor header is ignored when recording changes in the
+	// audit log, as clients are identified by certificate
+	IgnoreActorHeader bool
+}
+
+// createDefault will update itself with necessary components
+func (sc StatusChangeController) createDefault(deps Dependencies) Controller {
+	return &StatusChangeController{
+		Repo:              deps.Repo,
+		IgnoreActorHeader: ignoreActorHeader(deps.Config),
+	}
+}
+
+// registerRoutes will register all controller routes to the Echo instance
+func (sc StatusChangeController) registerRoutes(e *echo.Echo) Controller {
+	e.POST("/users/:userId/scheduled-status-changes", sc.ScheduleStatusChange)
+	e.GET("/scheduled-status-changes", sc.GetPendingStatusChanges)
+	e.DELETE("/scheduled-status-changes/:changeId", sc.CancelStatusChange)
+
+	return sc
+}
+
+// @Summary Schedules a user status change
+// @Description Schedules the user_status of the user to change once effective_at has passed
+// @Tags 	Scheduled Status Changes
+// @Produce json
+// @Param 	userId path string true "User Id for the user whose status will change"
+// @Param	change body models.ScheduledStatusChange true "Status and the time it takes effect"
+// @Success 200 {object} 			response.Response{data=models.ScheduledStatusChange,error_code=nil,error_message=nil}
+// @Failure 400 {object} 			response.Response{data=nil,error_code=int,error_message=string}
+// @Failure 404 {object} 			response.Response{data=nil,error_code=int,error_message=string}
+// @Failure 409 {object} 			response.Response{data=nil,error_code=int,error_message=string}
+// @Failure 422 {object} 			response.Response{data=nil,error_code=int,error_message=string,errors=[]errors.FieldError}
+// @Failure 500 {object} 			response.Response{data=nil,error_code=int,error_message=string}
+// @Router	/users/{userId}/scheduled-status-changes	[post]
+func (sc StatusChangeController) ScheduleStatusChange(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		return errors.New(errors.UsersControllerInvalidUserIdParam, err)
+	}
+
+	change := models.ScheduledStatusChange{}
+	if err := ctx.Bind(&change); err != nil {
+		return errors.New(errors.StatusChangesControllerFailedToBindBody, err)
+	}
+
+	// The user is always taken from the URL
+	change.UserId = id
+
+	if change.EffectiveAt.IsZero() {
+		return errors.New(errors.StatusChangesControllerInvalidEffectiveAt, nil)
+	}
+
+	if fieldErrs := validation.ValidateStatusChange(change); len(fieldErrs) > 0 {
+		return validationFailure(nil, fieldErrs)
+	}
+
+	info, err := auditInfo(ctx, sc.IgnoreActorHeader)
+	if err != nil {
+		return err
+	}
+
+	newChange, err := sc.Repo.CreateScheduledStatusChange(ctx.Request().Context(), change, info)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response.Success(newChange))
+}
+
+// @Summary Returns pending scheduled status changes
+// @Description Show the scheduled status changes that have not been applied or cancelled, soonest first
+// @Tags 	Scheduled Status Changes
+// @Produce json
+// @Param 	user_id query int false "Only return changes for this user"
+// @Success 200 {object} response.Response{data=[]models.ScheduledStatusChange,error_code=nil,error_message=nil}
+// @Failure 400 {object} response.Response{data=nil,error_code=int,error_message=string}
+// @Failure 500 {object} response.Response{data=nil,error_code=int,error_message=string}
+// @Router	/scheduled-status-changes		 [get]
+func (sc StatusChangeController) GetPendingStatusChanges(ctx echo.Context) error {
+	userId := 0
+	if param := ctx.QueryParam("user_id"); param != "" {
+		id, err := strconv.Atoi(param)
+		if err != nil {
+			return errors.New(errors.UsersControllerInvalidUserIdParam, err)
+		}
+		userId = id
+	}
+
+	changes, err := sc.Repo.GetPendingStatusChanges(ctx.Request().Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response.Success(changes))
+}
+
+// @Summary Cancels a pending scheduled status change
+// @Description Cancels the scheduled status change with the associated ID if it has not been applied yet
+// @Tags 	Scheduled Status Changes
+// @Produce json
+// @Param 	changeId path string true "Id of the scheduled status change to cancel"
+// @Success 200 {object} 			response.Response{data=int,error_code=nil,error_message=nil}
+// @Failure 400 {object} 			response.Response{data=nil,error_code=int,error_message=string}
+// @Failure 404 {object} 			response.Response{data=nil,error_code=nil,error_message=nil}
+// @Failure 422 {object} 			response.Response{data=nil,error_code=int,error_message=string,errors=[]errors.FieldError}
+// @Failure 500 {object} 			response.Response{data=nil,error_code=int,error_message=string}
+// @Router	/scheduled-status-changes/{changeId}	[delete]
+func (sc StatusChangeController) CancelStatusChange(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("changeId"))
+	if err != nil {
+		return errors.New(errors.StatusChangesControllerInvalidChangeId, err)
+	}
+
+	info, err := auditInfo(ctx, sc.IgnoreActorHeader)
+	if err != nil {
+		return err
+	}
+
+	cancelled, err := sc.Repo.CancelScheduledStatusChange(ctx.Request().Context(), id, info)
+	if err != nil {
+		return err
+	}
+
+	// Either the change does not exist or it is no longer pending
+	if !cancelled {
+		return ctx.JSON(http.StatusNotFound, response.Success(nil))
+	}
+
+	return ctx.JSON(http.StatusOK, response.Success(id))
+}
@@ -0,0 +1,317 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/controllers"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StatusChangeController", Ordered, func() {
+
+	var (
+		mockCtrl *gomock.Controller
+		mockRepo *mocks.MockIRepo
+		e        *echo.Echo
+
+		rec *httptest.ResponseRecorder
+	)
+
+	effectiveAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	testChange := models.ScheduledStatusChange{
+		ChangeId:    1,
+		UserId:      1,
+		UserStatus:  "I",
+		EffectiveAt: effectiveAt,
+		Actor:       "admin",
+		CreatedAt:   time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	BeforeAll(func() {
+		mockLogger := mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+	})
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockIRepo(mockCtrl)
+		e = echo.New()
+		e.HTTPErrorHandler = response.NewHTTPErrorHandler(e, response.FormatDefault)
+
+		rec = httptest.NewRecorder()
+	})
+
+	Describe("ScheduleStatusChange", func() {
+		newScheduleContext := func(userId string, body interface{}) echo.Context {
+			req := createTestRequest(http.MethodPost, "/users/"+userId+"/scheduled-status-changes", body)
+			req.Header.Add("Content-Type", "application/json")
+			req.Header.Add(constants.AuditActorHeader, "admin")
+			ctx := e.NewContext(req, rec)
+			ctx.SetParamNames("userId")
+			ctx.SetParamValues(userId)
+
+			return ctx
+		}
+
+		It("should schedule the status change for the user in the path", func() {
+			ctx := newScheduleContext("1", models.ScheduledStatusChange{UserId: 5, UserStatus: "I", EffectiveAt: effectiveAt})
+
+			mockRepo.EXPECT().
+				CreateScheduledStatusChange(gomock.Any(), 
+					models.ScheduledStatusChange{UserId: 1, UserStatus: "I", EffectiveAt: effectiveAt},
+					models.AuditInfo{Actor: "admin"}).
+				Return(&testChange, nil)
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.ScheduleStatusChange)
+
+			b, _ := json.Marshal(response.Success(testChange))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should fail when the user id is invalid", func() {
+			expectedCode := ipErrors.UsersControllerInvalidUserIdParam
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			ctx := newScheduleContext("abc", models.ScheduledStatusChange{UserStatus: "I", EffectiveAt: effectiveAt})
+
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.ScheduleStatusChange)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should fail when the body cannot be bound", func() {
+			expectedCode := ipErrors.StatusChangesControllerFailedToBindBody
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			ctx := newScheduleContext("1", `{"invalid":"type"}`)
+
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.ScheduleStatusChange)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should fail when effective_at is missing", func() {
+			expectedCode := ipErrors.StatusChangesControllerInvalidEffectiveAt
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			ctx := newScheduleContext("1", models.ScheduledStatusChange{UserStatus: "I"})
+
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.ScheduleStatusChange)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		DescribeTable("should reject a missing or unknown user_status",
+			func(status string, expectedFieldCode ipErrors.ErrorCode) {
+				ctx := newScheduleContext("1", models.ScheduledStatusChange{UserStatus: status, EffectiveAt: effectiveAt})
+
+				statusChangeController := &controllers.StatusChangeController{
+					Repo: mockRepo,
+				}
+				serve(ctx, statusChangeController.ScheduleStatusChange)
+
+				var body response.Response
+				Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+				Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+				Expect(body.ErrorCode).To(Equal(ipErrors.UsersControllerUserValidationFailed))
+				Expect(body.Errors).To(HaveLen(1))
+				Expect(body.Errors[0].Field).To(Equal("user_status"))
+				Expect(body.Errors[0].Code).To(Equal(expectedFieldCode))
+			},
+			Entry("missing", "", ipErrors.FieldRequired),
+			Entry("unknown", "X", ipErrors.FieldInvalidValue),
+		)
+
+		It("should return not found when the user does not exist", func() {
+			expectedCode := ipErrors.StatusChangesRepoUserNotFound
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			ctx := newScheduleContext("1", models.ScheduledStatusChange{UserStatus: "I", EffectiveAt: effectiveAt})
+
+			mockRepo.EXPECT().
+				CreateScheduledStatusChange(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(nil, ipErrors.New(expectedCode, errors.New("Failed!")))
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.ScheduleStatusChange)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+	})
+
+	Describe("GetPendingStatusChanges", func() {
+
+		It("should return the pending changes", func() {
+			expected := []models.ScheduledStatusChange{testChange}
+			ctx := e.NewContext(createTestRequest(http.MethodGet, "/scheduled-status-changes?user_id=1", nil), rec)
+
+			mockRepo.EXPECT().GetPendingStatusChanges(gomock.Any(), 1).Return(expected, nil)
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.GetPendingStatusChanges)
+
+			b, _ := json.Marshal(response.Success(expected))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should fail when the user id is invalid", func() {
+			expectedCode := ipErrors.UsersControllerInvalidUserIdParam
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			ctx := e.NewContext(createTestRequest(http.MethodGet, "/scheduled-status-changes?user_id=abc", nil), rec)
+
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.GetPendingStatusChanges)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should return error when DB throws error", func() {
+			expectedCode := ipErrors.StatusChangesRepoGetDBQueryFail
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			ctx := e.NewContext(createTestRequest(http.MethodGet, "/scheduled-status-changes", nil), rec)
+
+			mockRepo.EXPECT().
+				GetPendingStatusChanges(gomock.Any(), 0).
+				Return([]models.ScheduledStatusChange{}, ipErrors.New(expectedCode, errors.New("DB had an error!")))
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.GetPendingStatusChanges)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+	})
+
+	Describe("CancelStatusChange", func() {
+		newCancelContext := func(changeId string) echo.Context {
+			req := createTestRequest(http.MethodDelete, "/scheduled-status-changes/"+changeId, nil)
+			req.Header.Add(constants.AuditActorHeader, "admin")
+			ctx := e.NewContext(req, rec)
+			ctx.SetParamNames("changeId")
+			ctx.SetParamValues(changeId)
+
+			return ctx
+		}
+
+		It("should cancel the pending change", func() {
+			ctx := newCancelContext("1")
+
+			mockRepo.EXPECT().CancelScheduledStatusChange(gomock.Any(), 1, models.AuditInfo{Actor: "admin"}).Return(true, nil)
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.CancelStatusChange)
+
+			b, _ := json.Marshal(response.Success(1))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should return not found when the change is not pending", func() {
+			ctx := newCancelContext("1")
+
+			mockRepo.EXPECT().CancelScheduledStatusChange(gomock.Any(), 1, models.AuditInfo{Actor: "admin"}).Return(false, nil)
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.CancelStatusChange)
+
+			b, _ := json.Marshal(response.Success(nil))
+
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should fail when the change id is invalid", func() {
+			expectedCode := ipErrors.StatusChangesControllerInvalidChangeId
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			ctx := newCancelContext("abc")
+
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.CancelStatusChange)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should return error when DB throws error", func() {
+			expectedCode := ipErrors.StatusChangesRepoCancelDBQueryFail
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			ctx := newCancelContext("1")
+
+			mockRepo.EXPECT().
+				CancelScheduledStatusChange(gomock.Any(), 1, models.AuditInfo{Actor: "admin"}).
+				Return(false, ipErrors.New(expectedCode, errors.New("DB had an error!")))
+			statusChangeController := &controllers.StatusChangeController{
+				Repo: mockRepo,
+			}
+			serve(ctx, statusChangeController.CancelStatusChange)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+	})
+})
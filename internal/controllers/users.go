@@ -1,33 +1,39 @@
 package controllers
 
 import (
-	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
-	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/validation"
 	"github.com/labstack/echo/v4"
 )
 
 type UserController struct {
 	Controller
 	Repo database.Repo
+	// Whether the actor header is ignored when recording changes in the
+	// audit log, as clients are identified by certificate
+	IgnoreActorHeader bool
 }
 
 // createDefault will update itself with necessary components 
-func (uc UserController) createDefault(repo database.Repo) Controller {
+func (uc UserController) createDefault(deps Dependencies) Controller {
 	return &UserController{
-		Repo: repo,
+		Repo:              deps.Repo,
+		IgnoreActorHeader: ignoreActorHeader(deps.Config),
 	}
 }
 
 // registerRoutes will register all controller routes to the Echo instance
 func (uc UserController) registerRoutes(e *echo.Echo) Controller {
 	e.GET("/users", uc.GetAllUsers)
+	e.GET("/users/:userId", uc.GetUser)
+	e.GET("/users/:userId/history", uc.GetUserHistory)
 	e.POST("/users", uc.CreateUser)
 	e.PUT("/users", uc.UpdateUser)
 	e.DELETE("/users/:userId", uc.DeleteUser)
@@ -37,50 +43,135 @@ func (uc UserController) registerRoutes(e *echo.Echo) Controller {
 
 // @Summary Returns all users
 // @Description Show all available users from data store
+// @Description When as_of is passed, shows the users as they were at that time
 // @Tags 	Users
 // @Produce json
+// @Param 	as_of query string false "RFC 3339 time to reconstruct the users at"
 // @Success 200 {object} response.Response{data=models.User,error_code=nil,error_message=nil}
+// @Failure 400 {object} response.Response{data=nil,error_code=int,error_message=string}
 // @Failure 500 {object} response.Response{data=nil,error_code=int,error_message=string}
 // @Router	/users		 [get]
 func (uc UserController) GetAllUsers(ctx echo.Context) error {
-	users, errCode, err := uc.Repo.GetAllUsers()
+	asOf, err := parseAsOfParam(ctx)
 	if err != nil {
-		logging.ErrorWithCode(errCode, "failed to fetch user data", err)
+		return errors.New(errors.UsersControllerInvalidAsOfParam, err)
+	}
+
+	var users []models.User
+	if asOf.IsZero() {
+		users, err = uc.Repo.GetAllUsers(ctx.Request().Context())
+	} else {
+		users, err = uc.Repo.GetAllUsersAsOf(ctx.Request().Context(), asOf)
+	}
 
-		return ctx.JSON(http.StatusInternalServerError,
-			response.Failure(errCode, errors.GetErrorMessage(errCode)))
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response.Success(users))
 }
 
+// @Summary Returns a user by the userId
+// @Description Show the user from the data store with the associated ID
+// @Description When as_of is passed, shows the user as they were at that time
+// @Tags 	Users
+// @Produce json
+// @Param 	userId path string true "User Id for the user to be returned"
+// @Param 	as_of query string false "RFC 3339 time to reconstruct the user at"
+// @Success 200 {object} 			response.Response{data=models.User,error_code=nil,error_message=nil}
+// @Failure 400 {object} 			response.Response{data=nil,error_code=int,error_message=string}
+// @Failure 404 {object} 			response.Response{data=nil,error_code=nil,error_message=nil}
+// @Failure 500 {object} 			response.Response{data=nil,error_code=int,error_message=string}
+// @Router	/users/{userId}			[get]
+func (uc UserController) GetUser(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		return errors.New(errors.UsersControllerInvalidUserIdParam, err)
+	}
+
+	asOf, err := parseAsOfParam(ctx)
+	if err != nil {
+		return errors.New(errors.UsersControllerInvalidAsOfParam, err)
+	}
+
+	var user *models.User
+	if asOf.IsZero() {
+		user, err = uc.Repo.GetUser(ctx.Request().Context(), id)
+	} else {
+		user, err = uc.Repo.GetUserAsOf(ctx.Request().Context(), id, asOf)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return ctx.JSON(http.StatusNotFound, response.Success(nil))
+	}
+
+	return ctx.JSON(http.StatusOK, response.Success(user))
+}
+
+// @Summary Returns the history of a user by the userId
+// @Description Show every version of the user with the associated ID, oldest first
+// @Tags 	Users
+// @Produce json
+// @Param 	userId path string true "User Id for the user whose history will be returned"
+// @Success 200 {object} 			response.Response{data=[]models.UserVersion,error_code=nil,error_message=nil}
+// @Failure 400 {object} 			response.Response{data=nil,error_code=int,error_message=string}
+// @Failure 404 {object} 			response.Response{data=nil,error_code=nil,error_message=nil}
+// @Failure 500 {object} 			response.Response{data=nil,error_code=int,error_message=string}
+// @Router	/users/{userId}/history	[get]
+func (uc UserController) GetUserHistory(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		return errors.New(errors.UsersControllerInvalidUserIdParam, err)
+	}
+
+	versions, err := uc.Repo.GetUserHistory(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	// A user that has never existed has no history
+	if len(versions) == 0 {
+		return ctx.JSON(http.StatusNotFound, response.Success(nil))
+	}
+
+	return ctx.JSON(http.StatusOK, response.Success(versions))
+}
+
 // @Summary Creates a new user
 // @Description Creates a new user in the data store. Returns new user when successful
 // @Tags 	Users
 // @Produce json
 // @Param	user body models.User true "User data to be ingested"
 // @Success 200 {object} response.Response{data=[]models.User,error_code=nil,error_message=nil}
+// @Failure 422 {object} response.Response{data=nil,error_code=int,error_message=string,errors=[]errors.FieldError}
 // @Failure 500 {object} response.Response{data=nil,error_code=int,error_message=string}
 // @Router	/users		 [post]
 func (uc UserController) CreateUser(ctx echo.Context) error {
 	user := models.User{}
 	if err := ctx.Bind(&user); err != nil {
-		code := errors.UsersControllerUserFailedToBindBody
-		errMessage := errors.GetErrorMessage(code)
-		logging.ErrorWithCode(code, errMessage, err)
+		if fieldErr, ok := validation.FromBindError(err); ok {
+			return validationFailure(err, []errors.FieldError{fieldErr})
+		}
 
-		return ctx.JSON(http.StatusBadRequest,
-			response.Failure(code, errMessage))
+		return errors.New(errors.UsersControllerUserFailedToBindBody, err)
 	}
 
-	newUser, errCode, err := uc.Repo.CreateUser(user)
-	if err != nil {
-		errMessage := errors.GetErrorMessage(errCode)
-		logging.ErrorWithCode(errCode, errMessage, err)
+	if fieldErrs := validation.ValidateUser(user); len(fieldErrs) > 0 {
+		return validationFailure(nil, fieldErrs)
+	}
 
-		statusCode := getHttpStatusCodeForErr(errCode)
+	info, err := auditInfo(ctx, uc.IgnoreActorHeader)
+	if err != nil {
+		return err
+	}
 
-		return ctx.JSON(statusCode, response.Failure(errCode, errMessage))
+	newUser, err := uc.Repo.CreateUser(ctx.Request().Context(), user, info)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response.Success(newUser))
@@ -90,36 +181,40 @@ func (uc UserController) CreateUser(ctx echo.Context) error {
 // @Description Updates a new user in the data store. Returns updated user when successful
 // @Tags 	Users
 // @Produce json
-// @Param	user body models.User true "User data to be ingested"
+// @Param	user body models.UserUpdate true "User data to be ingested and the reason for the change"
 // @Success 200 {object} response.Response{data=[]models.User,error_code=nil,error_message=nil}
+// @Failure 422 {object} response.Response{data=nil,error_code=int,error_message=string,errors=[]errors.FieldError}
+// @Failure 409 {object} response.Response{data=nil,error_code=int,error_message=string}
 // @Failure 500 {object} response.Response{data=nil,error_code=int,error_message=string}
 // @Router	/users		 [put]
 func (uc UserController) UpdateUser(ctx echo.Context) error {
-	user := models.User{}
-	if err := ctx.Bind(&user); err != nil {
-		code := errors.UsersControllerUserFailedToBindBody
-		errMessage := errors.GetErrorMessage(code)
-		logging.ErrorWithCode(code, errMessage, err)
+	update := models.UserUpdate{}
+	if err := ctx.Bind(&update); err != nil {
+		if fieldErr, ok := validation.FromBindError(err); ok {
+			return validationFailure(err, []errors.FieldError{fieldErr})
+		}
 
-		return ctx.JSON(http.StatusBadRequest,
-			response.Failure(code, fmt.Sprintf("%s. %s", errMessage, err.Error())))
+		return errors.New(errors.UsersControllerUserFailedToBindBody, err).WithDetail(err.Error())
 	}
 
 	// Ensure that the user_id is passed
-	if user.UserId == 0 {
-		code := errors.UsersRepoUpdateInvalidUserId
+	if update.UserId == 0 {
+		return errors.New(errors.UsersRepoUpdateInvalidUserId, nil)
+	}
 
-		return ctx.JSON(http.StatusBadRequest, response.Failure(code, errors.GetErrorMessage(code)))
+	if fieldErrs := validation.ValidateUserUpdate(update.User); len(fieldErrs) > 0 {
+		return validationFailure(nil, fieldErrs)
 	}
 
-	newUser, errCode, err := uc.Repo.UpdateUser(user)
+	info, err := auditInfo(ctx, uc.IgnoreActorHeader)
 	if err != nil {
-		errMessage := errors.GetErrorMessage(errCode)
-		logging.ErrorWithCode(errCode, errMessage, err)
-
-		statusCode := getHttpStatusCodeForErr(errCode)
+		return err
+	}
+	info.Reason = update.Reason
 
-		return ctx.JSON(statusCode, response.Failure(errCode, errMessage))
+	newUser, err := uc.Repo.UpdateUser(ctx.Request().Context(), update.User, info)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response.Success(newUser))
@@ -131,6 +226,8 @@ func (uc UserController) UpdateUser(ctx echo.Context) error {
 // @Produce json
 // @Param 	userId path string true "User Id for the user to be removed"
 // @Success 200 {object} 			response.Response{data=[]models.User,error_code=nil,error_message=nil}
+// @Failure 400 {object} 			response.Response{data=nil,error_code=int,error_message=string}
+// @Failure 422 {object} 			response.Response{data=nil,error_code=int,error_message=string,errors=[]errors.FieldError}
 // @Failure 404 {object} 			response.Response{data=nil,error_code=nil,error_message=nil}
 // @Failure 500 {object} 			response.Response{data=nil,error_code=int,error_message=string}
 // @Router	/users/{userId}			[delete]
@@ -138,19 +235,17 @@ func (uc UserController) DeleteUser(ctx echo.Context) error {
 	id, err := strconv.Atoi(ctx.Param("userId"))
 
 	if err != nil {
-		code := errors.UsersControllerInvalidUserIdParam
-		message := errors.GetErrorMessage(code)
-		logging.ErrorWithCode(code, message, err)
-
-		return ctx.JSON(http.StatusBadRequest, response.Failure(code, errors.GetErrorMessage(code)))
+		return errors.New(errors.UsersControllerInvalidUserIdParam, err)
 	}
 
-	deleted, errCode, err := uc.Repo.DeleteUser(id)
+	info, err := auditInfo(ctx, uc.IgnoreActorHeader)
 	if err != nil {
-		message := errors.GetErrorMessage(errCode)
-		logging.ErrorWithCode(errCode, message, err)
+		return err
+	}
 
-		return ctx.JSON(http.StatusInternalServerError, response.Failure(errCode, errors.GetErrorMessage(errCode)))
+	deleted, err := uc.Repo.DeleteUser(ctx.Request().Context(), id, info)
+	if err != nil {
+		return err
 	}
 
 	// If the DB returns empty, then we relay to the client that the user
@@ -162,18 +257,20 @@ func (uc UserController) DeleteUser(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response.Success(id))
 }
 
-// getHttpStatusCodeForErr returns the http status code for the specified
-// errors.ErrorCode.
+// parseAsOfParam parses the as_of query param as an RFC 3339 time.
 //
-// Will return http.StatusInternalServerError (500) status code if errCode
-// does not have a specific status code for it.
-func getHttpStatusCodeForErr(errCode errors.ErrorCode) int {
-	switch errCode {
-	case errors.UsersRepoUserDuplicateEmail:
-		fallthrough
-	case errors.UsersRepoUserDuplicateUsername:
-		return http.StatusConflict
-	}
-
-	return http.StatusInternalServerError
-}
\ No newline at end of file
+// Returns the zero time if the param was not passed.
+func parseAsOfParam(ctx echo.Context) (time.Time, error) {
+	asOf := ctx.QueryParam("as_of")
+	if asOf == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, asOf)
+}
+
+// validationFailure returns the error for user input with fields that
+// failed validation.
+func validationFailure(cause error, fieldErrs []errors.FieldError) error {
+	return errors.New(errors.UsersControllerUserValidationFailed, cause).WithFields(fieldErrs)
+}
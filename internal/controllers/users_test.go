@@ -8,14 +8,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/controllers"
 	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/identity"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/requestid"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
 	"github.com/labstack/echo/v4"
 	. "github.com/onsi/ginkgo/v2"
@@ -33,6 +36,14 @@ func createTestRequest(method string, url string, body interface{}) *http.Reques
 	return httptest.NewRequest(method, url, nil)
 }
 
+// serve runs the handler and renders any error it returns with the
+// Echo instance's error handler, the same as a routed request.
+func serve(ctx echo.Context, handler echo.HandlerFunc) {
+	if err := handler(ctx); err != nil {
+		ctx.Echo().HTTPErrorHandler(err, ctx)
+	}
+}
+
 var _ = Describe("UserController", Ordered, func() {
 
 	var (
@@ -43,6 +54,8 @@ var _ = Describe("UserController", Ordered, func() {
 		req *http.Request
 		rec *httptest.ResponseRecorder
 		ctx echo.Context
+
+		anonymousAudit = models.AuditInfo{Actor: constants.AuditActorDefault}
 	)
 
 	BeforeAll(func() {
@@ -54,6 +67,7 @@ var _ = Describe("UserController", Ordered, func() {
 		mockCtrl = gomock.NewController(GinkgoT())
 		mockRepo = mocks.NewMockIRepo(mockCtrl)
 		e = echo.New()
+		e.HTTPErrorHandler = response.NewHTTPErrorHandler(e, response.FormatDefault)
 
 		rec = httptest.NewRecorder()
 	})
@@ -68,11 +82,11 @@ var _ = Describe("UserController", Ordered, func() {
 		It("should return all users in data store", func() {
 			expected := constants.TestUsers
 
-			mockRepo.EXPECT().GetAllUsers().Return(expected, ipErrors.ErrorCode(0), nil)
+			mockRepo.EXPECT().GetAllUsers(gomock.Any()).Return(expected, nil)
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.GetAllUsers(ctx)
+			serve(ctx, userController.GetAllUsers)
 
 			b, _ := json.Marshal(response.Success(expected))
 			
@@ -83,13 +97,13 @@ var _ = Describe("UserController", Ordered, func() {
 		It("should return error when DB throws error", func() {
 			expectedErr := errors.New("DB had an error!")
 			expectedCode := ipErrors.UsersRepoGetAllUsersDBQueryFail
-			expectedMsg := ipErrors.GetErrorMessage(expectedCode)
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
 
-			mockRepo.EXPECT().GetAllUsers().Return([]models.User{}, expectedCode, expectedErr)
+			mockRepo.EXPECT().GetAllUsers(gomock.Any()).Return([]models.User{}, ipErrors.New(expectedCode, expectedErr))
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.GetAllUsers(ctx)
+			serve(ctx, userController.GetAllUsers)
 
 			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
 			
@@ -98,6 +112,163 @@ var _ = Describe("UserController", Ordered, func() {
 		})
 	})
 
+	Describe("GetAllUsers as of a time", func() {
+
+		It("should return users as they were at the time", func() {
+			expected := constants.TestUsers
+			asOf := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+			req = createTestRequest(http.MethodGet, "/users?as_of=2026-03-01T00:00:00Z", nil)
+			ctx = e.NewContext(req, rec)
+
+			mockRepo.EXPECT().GetAllUsersAsOf(gomock.Any(), asOf).Return(expected, nil)
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.GetAllUsers)
+
+			b, _ := json.Marshal(response.Success(expected))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should fail if as_of is not a valid time", func() {
+			expectedCode := ipErrors.UsersControllerInvalidAsOfParam
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			req = createTestRequest(http.MethodGet, "/users?as_of=yesterday", nil)
+			ctx = e.NewContext(req, rec)
+
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.GetAllUsers)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+	})
+
+	Describe("GetUser", func() {
+
+		BeforeEach(func() {
+			req = createTestRequest(http.MethodGet, "/users/:userId", nil)
+			ctx = e.NewContext(req, rec)
+			ctx.SetParamNames("userId")
+			ctx.SetParamValues("1")
+		})
+
+		It("should return the user", func() {
+			expected := constants.TestUsers[0]
+
+			mockRepo.EXPECT().GetUser(gomock.Any(), 1).Return(&expected, nil)
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.GetUser)
+
+			b, _ := json.Marshal(response.Success(expected))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should return the user as they were at the time", func() {
+			expected := constants.TestUsers[0]
+			asOf := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+			req = createTestRequest(http.MethodGet, "/users/:userId?as_of=2026-03-01T00:00:00Z", nil)
+			ctx = e.NewContext(req, rec)
+			ctx.SetParamNames("userId")
+			ctx.SetParamValues("1")
+
+			mockRepo.EXPECT().GetUserAsOf(gomock.Any(), 1, asOf).Return(&expected, nil)
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.GetUser)
+
+			b, _ := json.Marshal(response.Success(expected))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should return NotFound if user with Id does not exist", func() {
+			mockRepo.EXPECT().GetUser(gomock.Any(), 1).Return(nil, nil)
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.GetUser)
+
+			b, _ := json.Marshal(response.Success(nil))
+
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should return error when DB returns an error", func() {
+			expectedCode := ipErrors.UsersRepoGetUserDBQueryFail
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			mockRepo.EXPECT().GetUser(gomock.Any(), 1).Return(nil, ipErrors.New(expectedCode, errors.New("DB error occurred!")))
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.GetUser)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+	})
+
+	Describe("GetUserHistory", func() {
+
+		BeforeEach(func() {
+			req = createTestRequest(http.MethodGet, "/users/:userId/history", nil)
+			ctx = e.NewContext(req, rec)
+			ctx.SetParamNames("userId")
+			ctx.SetParamValues("1")
+		})
+
+		It("should return every version of the user", func() {
+			validFrom := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+			expected := []models.UserVersion{
+				{
+					User:      constants.TestUsers[0],
+					Operation: models.AuditActionCreate,
+					ValidFrom: validFrom,
+				},
+			}
+
+			mockRepo.EXPECT().GetUserHistory(gomock.Any(), 1).Return(expected, nil)
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.GetUserHistory)
+
+			b, _ := json.Marshal(response.Success(expected))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should return NotFound if user with Id never existed", func() {
+			mockRepo.EXPECT().GetUserHistory(gomock.Any(), 1).Return([]models.UserVersion{}, nil)
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.GetUserHistory)
+
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
 	Describe("CreateUser", func() {
 
 		It("should create new user successfully", func() {
@@ -106,11 +277,11 @@ var _ = Describe("UserController", Ordered, func() {
 			req.Header.Add("Content-Type", "application/json")
 			ctx = e.NewContext(req, rec)
 			
-			mockRepo.EXPECT().CreateUser(expected).Return(&expected, ipErrors.ErrorCode(0), nil)
+			mockRepo.EXPECT().CreateUser(gomock.Any(), expected, anonymousAudit).Return(&expected, nil)
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.CreateUser(ctx)
+			serve(ctx, userController.CreateUser)
 
 			b, _ := json.Marshal(response.Success(expected))
 			
@@ -118,9 +289,121 @@ var _ = Describe("UserController", Ordered, func() {
 			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
 		})
 
+		It("should record the actor and request id for the audit log", func() {
+			expected := constants.TestUsers[0]
+			req = createTestRequest(http.MethodPost, "/users", expected)
+			req.Header.Add("Content-Type", "application/json")
+			req.Header.Add(constants.AuditActorHeader, "admin")
+			req.Header.Add(echo.HeaderXRequestID, "request-1")
+			ctx = e.NewContext(req, rec)
+
+			audit := models.AuditInfo{Actor: "admin", RequestId: "request-1"}
+			mockRepo.EXPECT().CreateUser(gomock.Any(), expected, audit).Return(&expected, nil)
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, requestid.Middleware()(userController.CreateUser))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should reject an actor header too long to be recorded, counting characters", func() {
+			expected := constants.TestUsers[0]
+			req = createTestRequest(http.MethodPost, "/users", expected)
+			req.Header.Add("Content-Type", "application/json")
+			req.Header.Add(constants.AuditActorHeader, strings.Repeat("é", constants.AuditActorMaxLength+1))
+			ctx = e.NewContext(req, rec)
+
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.CreateUser)
+
+			var body response.Response
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+			Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(body.ErrorCode).To(Equal(ipErrors.AuditControllerInvalidActor))
+			Expect(body.Errors).To(HaveLen(1))
+			Expect(body.Errors[0].Field).To(Equal(constants.AuditActorHeader))
+			Expect(body.Errors[0].Code).To(Equal(ipErrors.FieldTooLong))
+		})
+
+		It("should accept an actor header at the max length in characters", func() {
+			expected := constants.TestUsers[0]
+			actor := strings.Repeat("é", constants.AuditActorMaxLength)
+			req = createTestRequest(http.MethodPost, "/users", expected)
+			req.Header.Add("Content-Type", "application/json")
+			req.Header.Add(constants.AuditActorHeader, actor)
+			ctx = e.NewContext(req, rec)
+
+			mockRepo.EXPECT().CreateUser(gomock.Any(), expected, models.AuditInfo{Actor: actor}).Return(&expected, nil)
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.CreateUser)
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should record the client certificate's identity as the actor over the header", func() {
+			expected := constants.TestUsers[0]
+			req = createTestRequest(http.MethodPost, "/users", expected)
+			req.Header.Add("Content-Type", "application/json")
+			req.Header.Add(constants.AuditActorHeader, "admin")
+			req = req.WithContext(identity.NewContext(req.Context(), "billing-service"))
+			ctx = e.NewContext(req, rec)
+
+			audit := models.AuditInfo{Actor: "billing-service"}
+			mockRepo.EXPECT().CreateUser(gomock.Any(), expected, audit).Return(&expected, nil)
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.CreateUser)
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should not let the header override the client certificate's identity when it is ignored", func() {
+			expected := constants.TestUsers[0]
+			req = createTestRequest(http.MethodPost, "/users", expected)
+			req.Header.Add("Content-Type", "application/json")
+			req.Header.Add(constants.AuditActorHeader, "admin")
+			req = req.WithContext(identity.NewContext(req.Context(), "billing-service"))
+			ctx = e.NewContext(req, rec)
+
+			audit := models.AuditInfo{Actor: "billing-service"}
+			mockRepo.EXPECT().CreateUser(gomock.Any(), expected, audit).Return(&expected, nil)
+			userController := &controllers.UserController{
+				Repo:              mockRepo,
+				IgnoreActorHeader: true,
+			}
+			serve(ctx, userController.CreateUser)
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should record clients without a certificate as unauthenticated when the header is ignored", func() {
+			expected := constants.TestUsers[0]
+			req = createTestRequest(http.MethodPost, "/users", expected)
+			req.Header.Add("Content-Type", "application/json")
+			req.Header.Add(constants.AuditActorHeader, "admin")
+			ctx = e.NewContext(req, rec)
+
+			audit := models.AuditInfo{Actor: constants.AuditActorUnauthenticated}
+			mockRepo.EXPECT().CreateUser(gomock.Any(), expected, audit).Return(&expected, nil)
+			userController := &controllers.UserController{
+				Repo:              mockRepo,
+				IgnoreActorHeader: true,
+			}
+			serve(ctx, userController.CreateUser)
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
 		It("should fail to bind request body", func() {
 			expectedCode := ipErrors.UsersControllerUserFailedToBindBody
-			expectedMsg := ipErrors.GetErrorMessage(expectedCode)
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
 
 			req = createTestRequest(http.MethodPost, "/users", `{"invalid":"type"}`)
 			req.Header.Add("Content-Type", "application/json")
@@ -129,7 +412,7 @@ var _ = Describe("UserController", Ordered, func() {
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.CreateUser(ctx)
+			serve(ctx, userController.CreateUser)
 
 			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
 			
@@ -137,19 +420,66 @@ var _ = Describe("UserController", Ordered, func() {
 			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
 		})
 
+		It("should return the field that has the wrong type", func() {
+			expectedCode := ipErrors.UsersControllerUserValidationFailed
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			req = createTestRequest(http.MethodPost, "/users", map[string]interface{}{"user_name": 5})
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.CreateUser)
+
+			b, _ := json.Marshal(response.ValidationFailure(expectedCode, expectedMsg, []ipErrors.FieldError{
+				ipErrors.NewFieldError("user_name", ipErrors.FieldInvalidType, "string"),
+			}))
+
+			Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should return every invalid field before reaching the DB", func() {
+			expectedCode := ipErrors.UsersControllerUserValidationFailed
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			input := constants.TestUsers[0]
+			input.Firstname = ""
+			input.Email = "not-an-email"
+
+			req = createTestRequest(http.MethodPost, "/users", input)
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.CreateUser)
+
+			b, _ := json.Marshal(response.ValidationFailure(expectedCode, expectedMsg, []ipErrors.FieldError{
+				ipErrors.NewFieldError("first_name", ipErrors.FieldRequired),
+				ipErrors.NewFieldError("email", ipErrors.FieldInvalidEmail),
+			}))
+
+			Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
 		It("should fail if user with username already exists in DB", func() {
 			expectedCode := ipErrors.UsersRepoUserDuplicateUsername
-			expectedMsg := ipErrors.GetErrorMessage(expectedCode)
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
 
 			req = createTestRequest(http.MethodPost, "/users", constants.TestUsers[0])
 			req.Header.Add("Content-Type", "application/json")
 			ctx = e.NewContext(req, rec)
 			
-			mockRepo.EXPECT().CreateUser(constants.TestUsers[0]).Return(nil, expectedCode, errors.New("Failed!"))
+			mockRepo.EXPECT().CreateUser(gomock.Any(), constants.TestUsers[0], anonymousAudit).Return(nil, ipErrors.New(expectedCode, errors.New("Failed!")))
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.CreateUser(ctx)
+			serve(ctx, userController.CreateUser)
 
 			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
 			
@@ -159,17 +489,17 @@ var _ = Describe("UserController", Ordered, func() {
 
 		It("should fail with DB error", func() {
 			expectedCode := ipErrors.UsersRepoCreateUserDBQueryFail
-			expectedMsg := ipErrors.GetErrorMessage(expectedCode)
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
 
 			req = createTestRequest(http.MethodPost, "/users", constants.TestUsers[0])
 			req.Header.Add("Content-Type", "application/json")
 			ctx = e.NewContext(req, rec)
 			
-			mockRepo.EXPECT().CreateUser(constants.TestUsers[0]).Return(nil, expectedCode, errors.New("Failed!"))
+			mockRepo.EXPECT().CreateUser(gomock.Any(), constants.TestUsers[0], anonymousAudit).Return(nil, ipErrors.New(expectedCode, errors.New("Failed!")))
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.CreateUser(ctx)
+			serve(ctx, userController.CreateUser)
 
 			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
 			
@@ -186,11 +516,11 @@ var _ = Describe("UserController", Ordered, func() {
 			req.Header.Add("Content-Type", "application/json")
 			ctx = e.NewContext(req, rec)
 			
-			mockRepo.EXPECT().UpdateUser(expected).Return(&expected, ipErrors.ErrorCode(0), nil)
+			mockRepo.EXPECT().UpdateUser(gomock.Any(), expected, anonymousAudit).Return(&expected, nil)
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.UpdateUser(ctx)
+			serve(ctx, userController.UpdateUser)
 
 			b, _ := json.Marshal(response.Success(expected))
 			
@@ -198,18 +528,77 @@ var _ = Describe("UserController", Ordered, func() {
 			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
 		}) 
 
+		It("should pass the reason for the change along", func() {
+			expected := constants.TestUsers[0]
+			req = createTestRequest(http.MethodPut, "/users", models.UserUpdate{User: expected, Reason: "promoted"})
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			audit := anonymousAudit
+			audit.Reason = "promoted"
+
+			mockRepo.EXPECT().UpdateUser(gomock.Any(), expected, audit).Return(&expected, nil)
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.UpdateUser)
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should fail when a set field is invalid", func() {
+			expectedCode := ipErrors.UsersControllerUserValidationFailed
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			req = createTestRequest(http.MethodPut, "/users", models.User{UserId: 1, UserStatus: "X"})
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.UpdateUser)
+
+			b, _ := json.Marshal(response.ValidationFailure(expectedCode, expectedMsg, []ipErrors.FieldError{
+				ipErrors.NewFieldError("user_status", ipErrors.FieldInvalidValue, "I, A, T"),
+			}))
+
+			Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
+		It("should fail with conflict when the status transition is not allowed", func() {
+			expectedCode := ipErrors.UsersRepoIllegalStatusTransition
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			req = createTestRequest(http.MethodPut, "/users", constants.TestUsers[0])
+			req.Header.Add("Content-Type", "application/json")
+			ctx = e.NewContext(req, rec)
+
+			mockRepo.EXPECT().UpdateUser(gomock.Any(), constants.TestUsers[0], anonymousAudit).Return(nil, ipErrors.New(expectedCode, errors.New("Failed!")))
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.UpdateUser)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusConflict))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
+
 		It("should fail if user_id is not passed to body", func() {
 			input := constants.TestUsers[0]
 			input.UserId = 0
 			expectedCode := ipErrors.UsersRepoUpdateInvalidUserId
-			expectedMsg := ipErrors.GetErrorMessage(expectedCode)
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
 
 			req = createTestRequest(http.MethodPut, "/users", input)
 			req.Header.Add("Content-Type", "application/json")
 			ctx = e.NewContext(req, rec)
 			
 			userController := &controllers.UserController{}
-			userController.UpdateUser(ctx)
+			serve(ctx, userController.UpdateUser)
 
 			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
 			
@@ -219,17 +608,17 @@ var _ = Describe("UserController", Ordered, func() {
 
 		It("should fail if user with email already exists in DB", func() {
 			expectedCode := ipErrors.UsersRepoUserDuplicateEmail
-			expectedMsg := ipErrors.GetErrorMessage(expectedCode)
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
 
 			req = createTestRequest(http.MethodPost, "/users", constants.TestUsers[0])
 			req.Header.Add("Content-Type", "application/json")
 			ctx = e.NewContext(req, rec)
 			
-			mockRepo.EXPECT().CreateUser(constants.TestUsers[0]).Return(nil, expectedCode, errors.New("Failed!"))
+			mockRepo.EXPECT().CreateUser(gomock.Any(), constants.TestUsers[0], anonymousAudit).Return(nil, ipErrors.New(expectedCode, errors.New("Failed!")))
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.CreateUser(ctx)
+			serve(ctx, userController.CreateUser)
 
 			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
 			
@@ -241,17 +630,17 @@ var _ = Describe("UserController", Ordered, func() {
 			input := constants.TestUsers[0]
 
 			expectedCode := ipErrors.UsersRepoUpdateUserDBQueryFail
-			expectedMsg := ipErrors.GetErrorMessage(expectedCode)
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
 
 			req = createTestRequest(http.MethodPut, "/users", input)
 			req.Header.Add("Content-Type", "application/json")
 			ctx = e.NewContext(req, rec)
 			
-			mockRepo.EXPECT().UpdateUser(input).Return(nil, expectedCode, errors.New("DB error occurred!"))
+			mockRepo.EXPECT().UpdateUser(gomock.Any(), input, anonymousAudit).Return(nil, ipErrors.New(expectedCode, errors.New("DB error occurred!")))
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.UpdateUser(ctx)
+			serve(ctx, userController.UpdateUser)
 
 			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
 			
@@ -273,11 +662,11 @@ var _ = Describe("UserController", Ordered, func() {
 		})
 
 		It("should delete user successfully", func() {
-			mockRepo.EXPECT().DeleteUser(inputId).Return(true, ipErrors.ErrorCode(0), nil)
+			mockRepo.EXPECT().DeleteUser(gomock.Any(), inputId, anonymousAudit).Return(true, nil)
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.DeleteUser(ctx)
+			serve(ctx, userController.DeleteUser)
 
 			b, _ := json.Marshal(response.Success(inputId))
 			
@@ -286,11 +675,11 @@ var _ = Describe("UserController", Ordered, func() {
 		}) 
 
 		It("should return NotFound if user with Id does not exist", func() {	
-			mockRepo.EXPECT().DeleteUser(inputId).Return(false, ipErrors.ErrorCode(0), nil)
+			mockRepo.EXPECT().DeleteUser(gomock.Any(), inputId, anonymousAudit).Return(false, nil)
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.DeleteUser(ctx)
+			serve(ctx, userController.DeleteUser)
 
 			b, _ := json.Marshal(response.Success(nil))
 			
@@ -300,18 +689,34 @@ var _ = Describe("UserController", Ordered, func() {
 
 		It("should return error when DB returns an error", func() {	
 			expectedCode := ipErrors.UsersRepoDeleteUserDBQueryFail
-			expectedMsg := ipErrors.GetErrorMessage(expectedCode)
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
 
-			mockRepo.EXPECT().DeleteUser(inputId).Return(false, expectedCode, errors.New("DB error occurred!"))
+			mockRepo.EXPECT().DeleteUser(gomock.Any(), inputId, anonymousAudit).Return(false, ipErrors.New(expectedCode, errors.New("DB error occurred!")))
 			userController := &controllers.UserController{
 				Repo: mockRepo,
 			}
-			userController.DeleteUser(ctx)
+			serve(ctx, userController.DeleteUser)
 
 			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
 			
 			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
 			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
 		}) 
+
+		It("should return BadRequest when userId is not a number", func() {
+			expectedCode := ipErrors.UsersControllerInvalidUserIdParam
+			expectedMsg := ipErrors.GetErrorMessage(expectedCode, ipErrors.DefaultLocale)
+
+			ctx.SetParamValues("abc")
+			userController := &controllers.UserController{
+				Repo: mockRepo,
+			}
+			serve(ctx, userController.DeleteUser)
+
+			b, _ := json.Marshal(response.Failure(expectedCode, expectedMsg))
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			Expect(strings.ReplaceAll(rec.Body.String(), "\n", "")).To(Equal(string(b)))
+		})
 	})
 })
@@ -0,0 +1,90 @@
+// package cors answers cross-origin requests with the configured policy,
+// which can be changed while the server is running.
+package cors
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Policy is the CORS policy applied to requests, which is swapped as a
+// whole when it is changed so a request never sees part of a change.
+type Policy struct {
+	current atomic.Pointer[echo.MiddlewareFunc]
+}
+
+// NewPolicy creates a Policy applying the config.
+func NewPolicy(c config.CORSConfig) *Policy {
+	p := &Policy{}
+	p.Set(c)
+
+	return p
+}
+
+// Set applies the config to every request from now on.
+func (p *Policy) Set(c config.CORSConfig) {
+	m := middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins:     c.AllowOrigins,
+		AllowOriginFunc:  AllowOrigin(c.AllowOrigins),
+		AllowMethods:     c.AllowMethods,
+		AllowHeaders:     c.AllowHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	})
+
+	p.current.Store(&m)
+}
+
+// Middleware applies the current policy to every request.
+func (p *Policy) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			return (*p.current.Load())(next)(ctx)
+		}
+	}
+}
+
+// subdomainPattern matches the subdomain a wildcard origin stands in for,
+// one or more DNS labels
+var subdomainPattern = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9-]+)*$`)
+
+// AllowOrigin returns a func reporting whether the origin of a request is
+// one of the origins, ignoring case. An origin like
+// "https://*.example.com" allows any subdomain of example.com, but not
+// example.com itself, and "*" allows any origin.
+//
+// Unlike echo's own patterns, a wildcard only matches whole DNS labels,
+// so https://*.example.com does not allow https://evil.com?.example.com.
+func AllowOrigin(origins []string) func(origin string) (bool, error) {
+	return func(origin string) (bool, error) {
+		origin = strings.ToLower(origin)
+
+		for _, allowed := range origins {
+			allowed = strings.ToLower(allowed)
+
+			if allowed == "*" || allowed == origin {
+				return true, nil
+			}
+
+			// Split into the scheme and the domain the subdomain is of,
+			// along with any port
+			prefix, suffix, wildcard := strings.Cut(allowed, "*")
+			if !wildcard || !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) ||
+				len(origin) <= len(prefix)+len(suffix) {
+				continue
+			}
+
+			if subdomainPattern.MatchString(origin[len(prefix) : len(origin)-len(suffix)]) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
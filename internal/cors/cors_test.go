@@ -0,0 +1,133 @@
+package cors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/cors"
+
+	"github.com/labstack/echo/v4"
+)
+
+var _ = Describe("Policy", func() {
+	var policy *cors.Policy
+	var e *echo.Echo
+
+	// preflight sends a preflight request for a PATCH from the origin
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/users", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPatch)
+		req.Header.Set(echo.HeaderAccessControlRequestHeaders, "Content-Type")
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// request sends a request from the origin, returning the origin it is
+	// allowed for
+	request := func(origin string) string {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		return rec.Header().Get(echo.HeaderAccessControlAllowOrigin)
+	}
+
+	BeforeEach(func() {
+		policy = cors.NewPolicy(config.CORSConfig{
+			AllowOrigins:     []string{"https://app.example.com", "https://*.partners.example.com"},
+			AllowMethods:     []string{http.MethodGet, http.MethodPatch},
+			AllowHeaders:     []string{"Content-Type", "X-Request-ID"},
+			AllowCredentials: true,
+			MaxAge:           600,
+		})
+
+		e = echo.New()
+		e.Use(policy.Middleware())
+		e.GET("/users", func(ctx echo.Context) error {
+			return ctx.NoContent(http.StatusOK)
+		})
+	})
+
+	Describe("preflight requests", func() {
+		It("should allow an allowed origin with the configured policy", func() {
+			rec := preflight("https://app.example.com")
+
+			Expect(rec.Code).To(Equal(http.StatusNoContent))
+			Expect(rec.Header().Get(echo.HeaderAccessControlAllowOrigin)).To(Equal("https://app.example.com"))
+			Expect(rec.Header().Get(echo.HeaderAccessControlAllowMethods)).To(Equal("GET,PATCH"))
+			Expect(rec.Header().Get(echo.HeaderAccessControlAllowHeaders)).To(Equal("Content-Type,X-Request-ID"))
+			Expect(rec.Header().Get(echo.HeaderAccessControlAllowCredentials)).To(Equal("true"))
+			Expect(rec.Header().Get(echo.HeaderAccessControlMaxAge)).To(Equal("600"))
+			Expect(rec.Header().Values(echo.HeaderVary)).To(ContainElement(echo.HeaderOrigin))
+		})
+
+		It("should allow a subdomain of a wildcard origin", func() {
+			rec := preflight("https://acme.partners.example.com")
+
+			Expect(rec.Code).To(Equal(http.StatusNoContent))
+			Expect(rec.Header().Get(echo.HeaderAccessControlAllowOrigin)).To(Equal("https://acme.partners.example.com"))
+			Expect(rec.Header().Get(echo.HeaderAccessControlAllowMethods)).To(Equal("GET,PATCH"))
+		})
+
+		DescribeTable("should not allow an origin that isn't allowed",
+			func(origin string) {
+				rec := preflight(origin)
+
+				Expect(rec.Code).To(Equal(http.StatusNoContent))
+				Expect(rec.Header().Get(echo.HeaderAccessControlAllowOrigin)).To(BeEmpty())
+				Expect(rec.Header().Get(echo.HeaderAccessControlAllowMethods)).To(BeEmpty())
+				Expect(rec.Header().Get(echo.HeaderAccessControlAllowCredentials)).To(BeEmpty())
+			},
+			Entry("another origin", "https://evil.example.com"),
+			Entry("another scheme", "http://app.example.com"),
+			Entry("another port", "https://app.example.com:8443"),
+			Entry("the domain of a wildcard origin", "https://partners.example.com"),
+			Entry("a lookalike of a wildcard origin", "https://evilpartners.example.com"),
+		)
+	})
+
+	It("should only allow simple requests from allowed origins", func() {
+		Expect(request("https://app.example.com")).To(Equal("https://app.example.com"))
+		Expect(request("https://evil.example.com")).To(BeEmpty())
+	})
+
+	It("should apply a changed config to later requests", func() {
+		policy.Set(config.CORSConfig{AllowOrigins: []string{"https://admin.example.com"}})
+
+		Expect(request("https://admin.example.com")).To(Equal("https://admin.example.com"))
+		Expect(request("https://app.example.com")).To(BeEmpty())
+	})
+})
+
+var _ = DescribeTable("AllowOrigin",
+	func(allowed []string, origin string, expected bool) {
+		ok, err := cors.AllowOrigin(allowed)(origin)
+
+		Expect(err).To(BeNil())
+		Expect(ok).To(Equal(expected))
+	},
+	Entry("an exact match", []string{"https://app.example.com"}, "https://app.example.com", true),
+	Entry("a match in another case", []string{"https://App.Example.com"}, "https://app.example.COM", true),
+	Entry("any origin", []string{"*"}, "https://anything.test", true),
+	Entry("a subdomain", []string{"https://*.example.com"}, "https://app.example.com", true),
+	Entry("a nested subdomain", []string{"https://*.example.com"}, "https://eu.app.example.com", true),
+	Entry("a subdomain with the port", []string{"https://*.example.com:8443"}, "https://app.example.com:8443", true),
+	Entry("a subdomain without the port", []string{"https://*.example.com:8443"}, "https://app.example.com", false),
+	Entry("the domain itself", []string{"https://*.example.com"}, "https://example.com", false),
+	Entry("a subdomain of another scheme", []string{"https://*.example.com"}, "http://app.example.com", false),
+	Entry("a domain ending with the domain", []string{"https://*.example.com"}, "https://app.example.com.evil.test", false),
+	Entry("a host hidden in the subdomain", []string{"https://*.example.com"}, "https://evil.test?.example.com", false),
+	Entry("an empty subdomain", []string{"https://*.example.com"}, "https://.example.com", false),
+	Entry("no origins", []string{}, "https://app.example.com", false),
+)
@@ -0,0 +1,200 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+)
+
+// GetAuditEntries fetches the audit entries matching the filter from the DB,
+// newest first.
+//
+// Returns a slice of AuditEntries.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	entries := []models.AuditEntry{}
+
+	query := r.psql.
+		Select("audit_id", "actor", "action", "user_id", "changes", "COALESCE(request_id, '')", "COALESCE(reason, '')", "created_at").
+		From(constants.AuditLogTableName).
+		OrderBy("created_at DESC", "audit_id DESC").
+		Limit(filter.Limit)
+
+	if filter.UserId != 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	rows, err := query.RunWith(traced(r.DB)).QueryContext(ctx)
+	if err != nil {
+		return entries, ipErrors.New(ipErrors.AuditRepoGetEntriesDBQueryFail, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var entry models.AuditEntry
+		var changes []byte
+		if err := rows.Scan(
+			&entry.AuditId,
+			&entry.Actor,
+			&entry.Action,
+			&entry.UserId,
+			&changes,
+			&entry.RequestId,
+			&entry.Reason,
+			&entry.CreatedAt); err != nil {
+			logging.ErrorContext(ctx, "GetAuditEntries", "failed to scan audit data", err)
+		}
+
+		entry.Changes = changes
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// createAuditEntry records the change made to a user as part of the
+// transaction that made it.
+//
+// before is nil for creates and after is nil for deletes.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) createAuditEntry(
+	ctx context.Context,
+	tx *sql.Tx,
+	action string,
+	before *models.User,
+	after *models.User,
+	info models.AuditInfo,
+) error {
+	userId := 0
+	if after != nil {
+		userId = after.UserId
+	} else if before != nil {
+		userId = before.UserId
+	}
+
+	return r.insertAuditEntry(ctx, tx, action, userId, diffFields(before, after), info)
+}
+
+// createStatusChangeAuditEntry records a status change being scheduled or
+// cancelled against its user, as part of the transaction that did it.
+//
+// before is nil when the change is scheduled.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) createStatusChangeAuditEntry(
+	ctx context.Context,
+	tx *sql.Tx,
+	action string,
+	before *models.ScheduledStatusChange,
+	after *models.ScheduledStatusChange,
+	info models.AuditInfo,
+) error {
+	return r.insertAuditEntry(ctx, tx, action, after.UserId, diffFields(before, after), info)
+}
+
+// insertAuditEntry inserts an entry into the audit log.
+//
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) insertAuditEntry(
+	ctx context.Context,
+	tx *sql.Tx,
+	action string,
+	userId int,
+	fieldChanges map[string]models.FieldChange,
+	info models.AuditInfo,
+) error {
+	changes, err := json.Marshal(fieldChanges)
+	if err != nil {
+		return ipErrors.New(ipErrors.AuditRepoCreateEntryDBQueryFail, err)
+	}
+
+	var requestId interface{}
+	if info.RequestId != "" {
+		requestId = info.RequestId
+	}
+
+	var reason interface{}
+	if info.Reason != "" {
+		reason = info.Reason
+	}
+
+	_, err = r.psql.
+		Insert(constants.AuditLogTableName).
+		Columns("actor", "action", "user_id", "changes", "request_id", "reason").
+		Values(info.Actor, action, userId, string(changes), requestId, reason).
+		RunWith(traced(tx)).
+		ExecContext(ctx)
+
+	if err != nil {
+		return ipErrors.New(ipErrors.AuditRepoCreateEntryDBQueryFail, err)
+	}
+
+	return nil
+}
+
+// diffFields returns the fields that differ between before and after, keyed
+// by their JSON names. Both must be pointers to the same struct type.
+//
+// Either can be nil, in which case every field of the other is returned.
+func diffFields[T any](before *T, after *T) map[string]models.FieldChange {
+	changes := map[string]models.FieldChange{}
+
+	beforeFields := jsonFields(before)
+	afterFields := jsonFields(after)
+
+	for field, afterVal := range afterFields {
+		beforeVal := beforeFields[field]
+		if !reflect.DeepEqual(beforeVal, afterVal) {
+			changes[field] = models.FieldChange{Before: beforeVal, After: afterVal}
+		}
+	}
+
+	for field, beforeVal := range beforeFields {
+		if _, exists := afterFields[field]; !exists {
+			changes[field] = models.FieldChange{Before: beforeVal, After: nil}
+		}
+	}
+
+	return changes
+}
+
+// jsonFields returns the fields of value keyed by their JSON names.
+func jsonFields[T any](value *T) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if value == nil {
+		return fields
+	}
+
+	v := reflect.ValueOf(*value)
+	for i := 0; i < v.NumField(); i++ {
+		name := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fields[name] = v.Field(i).Interface()
+	}
+
+	return fields
+}
@@ -0,0 +1,102 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+)
+
+var _ = Describe("Audit", Ordered, func() {
+	var repo database.Repo
+	var dbMock sqlmock.Sqlmock
+	var closeFunc func()
+
+	columns := []string{"audit_id", "actor", "action", "user_id", "changes", "request_id", "reason", "created_at"}
+	selectColumns := "audit_id, actor, action, user_id, changes, COALESCE(request_id, ''), COALESCE(reason, ''), created_at"
+
+	BeforeAll(func() {
+		repo, dbMock, closeFunc = mocks.CreateRepoWithMockedDBDriver()
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	AfterAll(func() {
+		closeFunc()
+	})
+
+	Describe("GetAuditEntries", func() {
+		It("should return all audit entries", func() {
+			createdAt := time.Now()
+			rows := sqlmock.NewRows(columns).
+				AddRow(2, "admin", "DELETE", 1, []byte(`{"user_id":{"before":1,"after":null}}`), "", "left the company", createdAt).
+				AddRow(1, "admin", "CREATE", 1, []byte(`{"user_id":{"before":null,"after":1}}`), "request-1", "", createdAt)
+
+			dbMock.ExpectQuery(fmt.Sprintf(
+				"SELECT %s FROM %s ORDER BY created_at DESC, audit_id DESC LIMIT 100",
+				selectColumns,
+				constants.AuditLogTableName)).
+				WillReturnRows(rows)
+
+			entries, err := repo.GetAuditEntries(context.Background(), models.AuditFilter{Limit: 100})
+
+			Expect(err).To(BeNil())
+			Expect(len(entries)).To(Equal(2))
+			Expect(entries[0].Action).To(Equal(models.AuditActionDelete))
+			Expect(string(entries[1].Changes)).To(Equal(`{"user_id":{"before":null,"after":1}}`))
+			Expect(entries[1].RequestId).To(Equal("request-1"))
+			Expect(entries[0].Reason).To(Equal("left the company"))
+		})
+
+		It("should filter by user, actor and time range", func() {
+			from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+			to := from.AddDate(0, 1, 0)
+
+			dbMock.ExpectQuery(fmt.Sprintf(
+				"SELECT %s FROM %s WHERE user_id = $1 AND actor = $2 AND created_at >= $3 AND created_at < $4 ORDER BY created_at DESC, audit_id DESC LIMIT 10",
+				selectColumns,
+				constants.AuditLogTableName)).
+				WithArgs(1, "admin", from, to).
+				WillReturnRows(sqlmock.NewRows(columns))
+
+			entries, err := repo.GetAuditEntries(context.Background(), models.AuditFilter{
+				UserId: 1,
+				Actor:  "admin",
+				From:   from,
+				To:     to,
+				Limit:  10,
+			})
+
+			Expect(err).To(BeNil())
+			Expect(len(entries)).To(Equal(0))
+		})
+
+		It("should return error when db query fails", func() {
+			expectedErr := errors.New("DB query failed!")
+
+			dbMock.ExpectQuery(fmt.Sprintf(
+				"SELECT %s FROM %s ORDER BY created_at DESC, audit_id DESC LIMIT 100",
+				selectColumns,
+				constants.AuditLogTableName)).
+				WillReturnError(expectedErr)
+
+			entries, err := repo.GetAuditEntries(context.Background(), models.AuditFilter{Limit: 100})
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.AuditRepoGetEntriesDBQueryFail))
+			Expect(len(entries)).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/secrets"
+)
+
+// ConnConfig builds the config connections to the DB are opened with,
+// from the config's URL if set, otherwise from its connection fields.
+//
+// The config's application_name, search_path and statement_timeout are
+// set as runtime parameters, unless the URL already sets them.
+// Returns an error if the URL can't be parsed, without its password.
+func ConnConfig(dbConfig config.DatabaseConfig) (*pgx.ConnConfig, error) {
+	connString := dbConfig.URL
+	if connString == "" {
+		// Built as a URL so credentials with spaces or quotes are escaped
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(dbConfig.Username, dbConfig.Password),
+			Host:     net.JoinHostPort(dbConfig.Host, dbConfig.Port),
+			Path:     "/" + dbConfig.Name,
+			RawQuery: url.Values{"sslmode": {dbConfig.SSLMode}}.Encode(),
+		}
+		connString = dsn.String()
+	}
+
+	connConfig, err := pgx.ParseConfig(connString)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{
+		"application_name": dbConfig.ApplicationName,
+		"search_path":      dbConfig.SearchPath,
+	}
+	if dbConfig.StatementTimeout > 0 {
+		timeout := time.Duration(dbConfig.StatementTimeout) * time.Second
+		params["statement_timeout"] = strconv.FormatInt(timeout.Milliseconds(), 10)
+	}
+
+	for key, value := range params {
+		if _, set := connConfig.RuntimeParams[key]; !set && value != "" {
+			connConfig.RuntimeParams[key] = value
+		}
+	}
+
+	return connConfig, nil
+}
+
+// PasswordProvider returns the provider the DB password is loaded from,
+// or nil if the config's Password is used as is
+func PasswordProvider(dbConfig config.DatabaseConfig) secrets.Provider {
+	if dbConfig.PasswordFile != "" {
+		return secrets.File{Path: dbConfig.PasswordFile}
+	}
+
+	return nil
+}
+
+// openDB opens the pool of connections to the DB, without connecting yet.
+//
+// When the password has a provider, each new connection authenticates
+// with its latest value, reloaded every PasswordReloadInterval. Existing
+// connections are unaffected, so they are replaced with ones using a
+// rotated password as they reach ConnectionMaxLifetime.
+// Returns the reloader if the password has a provider, to be stopped once
+// the pool is closed, or an error if the password can't be loaded.
+func openDB(ctx context.Context, dbConfig config.DatabaseConfig, connConfig *pgx.ConnConfig) (*sqlx.DB, *secrets.Reloader, error) {
+	provider := PasswordProvider(dbConfig)
+	if provider == nil {
+		return sqlx.NewDb(stdlib.OpenDB(*connConfig), "pgx"), nil, nil
+	}
+
+	interval := time.Duration(dbConfig.PasswordReloadInterval) * time.Second
+	password := secrets.NewReloader("POSTGRES_PASSWORD_FILE", provider, interval)
+	if err := password.Load(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	db := stdlib.OpenDB(*connConfig, stdlib.OptionBeforeConnect(
+		func(_ context.Context, connConfig *pgx.ConnConfig) error {
+			connConfig.Password = password.Value()
+			return nil
+		}))
+
+	if interval > 0 {
+		password.Start()
+	}
+
+	return sqlx.NewDb(db, "pgx"), password, nil
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+)
+
+// Ping verifies a connection to the DB can still be made.
+//
+// Returns an errors.Error with the error code if the DB can't be reached.
+func (r ServiceRepo) Ping(ctx context.Context) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	if err := r.DB.PingContext(ctx); err != nil {
+		return ipErrors.New(ipErrors.DBRepoPingFailed, err)
+	}
+
+	return nil
+}
+
+// SchemaChangeDeployed reports whether the sqitch change has been
+// deployed to the DB.
+//
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) SchemaChangeDeployed(ctx context.Context, change string) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var deployed bool
+	err := r.psql.
+		Select("1").
+		Prefix("SELECT EXISTS (").
+		From(constants.SqitchChangesTableName).
+		Where(squirrel.Eq{"project": constants.SqitchProject, "change": change}).
+		Suffix(")").
+		RunWith(traced(r.DB)).
+		QueryRowContext(ctx).
+		Scan(&deployed)
+
+	if err != nil {
+		return false, ipErrors.New(ipErrors.DBRepoSchemaVersionQueryFail, err)
+	}
+
+	return deployed, nil
+}
@@ -0,0 +1,107 @@
+package database_test
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+)
+
+var _ = Describe("Health", func() {
+	var repo database.ServiceRepo
+	var dbMock sqlmock.Sqlmock
+	var db *sql.DB
+
+	BeforeEach(func() {
+		db, dbMock, _ = sqlmock.New(
+			sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+			sqlmock.MonitorPingsOption(true))
+
+		repo = database.CreateDefault()
+		repo.DB = sqlx.NewDb(db, "sqlmock")
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+		db.Close()
+	})
+
+	Describe("Ping", func() {
+		It("should succeed when the DB can be reached", func() {
+			dbMock.ExpectPing()
+
+			Expect(repo.Ping(context.Background())).To(Succeed())
+		})
+
+		It("should return error code when the DB can't be reached", func() {
+			dbMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+			err := repo.Ping(context.Background())
+
+			Expect(err).To(MatchError(ipErrors.DBRepoPingFailed))
+		})
+	})
+
+	Describe("SchemaChangeDeployed", func() {
+		query := fmt.Sprintf(
+			"SELECT EXISTS ( SELECT 1 FROM %s WHERE change = $1 AND project = $2 )",
+			constants.SqitchChangesTableName)
+
+		It("should return whether the change has been deployed", func() {
+			dbMock.ExpectQuery(query).
+				WithArgs(constants.SchemaVersion, constants.SqitchProject).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+			deployed, err := repo.SchemaChangeDeployed(context.Background(), constants.SchemaVersion)
+
+			Expect(err).To(BeNil())
+			Expect(deployed).To(BeTrue())
+		})
+
+		It("should return error code if the query fails", func() {
+			dbMock.ExpectQuery(query).
+				WithArgs(constants.SchemaVersion, constants.SqitchProject).
+				WillReturnError(errors.New("relation \"sqitch.changes\" does not exist"))
+
+			deployed, err := repo.SchemaChangeDeployed(context.Background(), constants.SchemaVersion)
+
+			Expect(err).To(MatchError(ipErrors.DBRepoSchemaVersionQueryFail))
+			Expect(deployed).To(BeFalse())
+		})
+	})
+
+	It("should expect the last change in the sqitch plan", func() {
+		file, err := os.Open("../../sqitch/sqitch.plan")
+		Expect(err).To(BeNil())
+		defer file.Close()
+
+		var project, change string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			switch {
+			case strings.HasPrefix(line, "%project="):
+				project = strings.TrimPrefix(line, "%project=")
+			case line == "", strings.HasPrefix(line, "%"), strings.HasPrefix(line, "@"), strings.HasPrefix(line, "#"):
+			default:
+				change, _, _ = strings.Cut(line, " ")
+			}
+		}
+
+		Expect(scanner.Err()).To(BeNil())
+		Expect(project).To(Equal(constants.SqitchProject))
+		Expect(change).To(Equal(constants.SchemaVersion))
+	})
+})
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/retry"
+)
+
+// Readiness is the part of health.Checker the Monitor reports the
+// connection to
+type Readiness interface {
+	SetDBLost(lost bool)
+}
+
+// Monitor periodically checks the connection to the DB, so losing it after
+// startup is logged and the app reported as not ready until it is back.
+//
+// The pool replaces broken connections itself the next time they are
+// used, so once the DB is lost it is only pinged with backoff to find out
+// when it can be reached again.
+type Monitor struct {
+	Repo      Repo
+	Interval  time.Duration
+	Backoff   retry.Backoff
+	Readiness Readiness
+
+	stop   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewMonitor creates a Monitor that checks the connection every interval,
+// pinging with the backoff once it is lost and reporting whether it is to
+// the readiness.
+func NewMonitor(repo Repo, interval time.Duration, backoff retry.Backoff, readiness Readiness) *Monitor {
+	// Reconnecting is retried for as long as it takes
+	backoff.MaxWait = 0
+
+	return &Monitor{
+		Repo:      repo,
+		Interval:  interval,
+		Backoff:   backoff,
+		Readiness: readiness,
+	}
+}
+
+// Start checks the connection in the background every interval until
+// Stop is called.
+func (m *Monitor) Start() {
+	m.stop = make(chan struct{})
+
+	// Cancelled on Stop so reconnecting does not hold it up
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	m.wg.Add(1)
+
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(m.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				m.Check(ctx)
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the monitor and waits for any check in progress to finish.
+func (m *Monitor) Stop() {
+	m.cancel()
+	close(m.stop)
+	m.wg.Wait()
+}
+
+// Check pings the DB and, if it can't be reached, reports it as lost and
+// keeps pinging it with backoff until it can or the context is done.
+//
+// Returns whether the DB could be reached.
+func (m *Monitor) Check(ctx context.Context) bool {
+	err := m.Repo.Ping(ctx)
+	if err == nil {
+		return true
+	}
+
+	logging.ErrorContext(ctx, "Check", "lost connection to DB, reconnecting", err)
+	m.Readiness.SetDBLost(true)
+	lost := time.Now()
+
+	// Left reported as lost if the context is done first, as the app is
+	// then stopping
+	if err := retry.Do(ctx, "reconnect to DB", m.Backoff, m.Repo.Ping); err != nil {
+		return false
+	}
+
+	m.Readiness.SetDBLost(false)
+
+	logging.Logger.InfoContext(ctx, "Reconnected to DB", "down_ms", time.Since(lost).Milliseconds())
+
+	return true
+}
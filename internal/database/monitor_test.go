@@ -0,0 +1,103 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/health"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/retry"
+)
+
+var _ = Describe("Monitor", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockRepo   *mocks.MockIRepo
+		mockLogger mocks.MockLogger
+		checker    *health.Checker
+		monitor    *database.Monitor
+	)
+
+	lostErr := ipErrors.New(ipErrors.DBRepoPingFailed, errors.New("connection reset by peer"))
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockIRepo(mockCtrl)
+		mockLogger = mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+		checker = health.NewChecker(mockRepo, time.Second)
+
+		monitor = database.NewMonitor(mockRepo, 10*time.Millisecond, retry.Backoff{
+			Initial: time.Millisecond,
+			Max:     5 * time.Millisecond,
+			MaxWait: time.Millisecond,
+		}, checker)
+	})
+
+	Describe("Check", func() {
+		It("should only ping while the DB can be reached", func() {
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+
+			Expect(monitor.Check(context.Background())).To(BeTrue())
+			Expect(mockLogger.GetBufferValue()).To(BeEmpty())
+			Expect(checker.DBLost()).To(BeFalse())
+		})
+
+		It("should report the DB as lost while reconnecting, and as back once reconnected", func() {
+			var lostWhileReconnecting bool
+			gomock.InOrder(
+				mockRepo.EXPECT().Ping(gomock.Any()).Return(lostErr),
+				mockRepo.EXPECT().Ping(gomock.Any()).DoAndReturn(func(context.Context) error {
+					lostWhileReconnecting = checker.DBLost()
+					return nil
+				}),
+			)
+
+			Expect(monitor.Check(context.Background())).To(BeTrue())
+			Expect(lostWhileReconnecting).To(BeTrue())
+			Expect(checker.DBLost()).To(BeFalse())
+		})
+
+		It("should keep pinging once the connection is lost until it is back", func() {
+			gomock.InOrder(
+				mockRepo.EXPECT().Ping(gomock.Any()).Return(lostErr).Times(3),
+				mockRepo.EXPECT().Ping(gomock.Any()).Return(nil),
+			)
+
+			Expect(monitor.Check(context.Background())).To(BeTrue())
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring("lost connection to DB"))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"operation":"reconnect to DB","attempt":2`))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring("Reconnected to DB"))
+		})
+
+		It("should stop reconnecting once the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(lostErr)
+			mockRepo.EXPECT().Ping(gomock.Any()).DoAndReturn(func(context.Context) error {
+				cancel()
+				return lostErr
+			})
+
+			Expect(monitor.Check(ctx)).To(BeFalse())
+			Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring("Reconnected to DB"))
+		})
+	})
+
+	Describe("Start", func() {
+		It("should check the connection every interval until stopped", func() {
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(nil).MinTimes(2)
+
+			monitor.Start()
+			time.Sleep(50 * time.Millisecond)
+			monitor.Stop()
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+)
+
+// CreatePasswordResetToken stores a hashed reset token for the user with the
+// associated email.
+//
+// The same single query is run whether or not the email exists so the
+// time taken does not reveal it to the caller.
+// Returns true if a user with the email exists and the token was stored.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) CreatePasswordResetToken(ctx context.Context, email string, tokenHash string, expiresAt time.Time) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	userQuery := r.psql.
+		Select("user_id").
+		Column("?", tokenHash).
+		Column("?::timestamptz", expiresAt).
+		From(constants.UsersTableName).
+		Where("LOWER(email) = LOWER(?)", email)
+
+	res, err := r.psql.
+		Insert(constants.PasswordResetTokensTableName).
+		Columns("user_id", "token_hash", "expires_at").
+		Select(userQuery).
+		RunWith(traced(r.DB)).
+		ExecContext(ctx)
+
+	if err != nil {
+		return false, ipErrors.New(ipErrors.PasswordResetRepoCreateTokenDBQueryFail, err)
+	}
+
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
+
+// ResetPassword consumes the reset token with the associated hash and sets
+// the password hash for the user it belongs to.
+//
+// Tokens can only be consumed once and only before they expire. Consuming a
+// token also invalidates any other outstanding tokens for the user.
+// Returns true if the token was valid and the password was updated.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) ResetPassword(ctx context.Context, tokenHash string, passwordHash string) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, ipErrors.New(ipErrors.PasswordResetRepoResetPasswordDBQueryFail, err)
+	}
+
+	defer rollback(ctx, "ResetPassword", tx)
+
+	var userId int
+	err = r.psql.
+		Update(constants.PasswordResetTokensTableName).
+		Set("used_at", squirrel.Expr("NOW()")).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > NOW()", tokenHash).
+		Suffix("RETURNING user_id").
+		RunWith(traced(tx)).
+		QueryRowContext(ctx).
+		Scan(&userId)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, ipErrors.New(ipErrors.PasswordResetRepoResetPasswordDBQueryFail, err)
+	}
+
+	// Invalidate any other tokens issued to the user
+	_, err = r.psql.
+		Update(constants.PasswordResetTokensTableName).
+		Set("used_at", squirrel.Expr("NOW()")).
+		Where("user_id = ? AND used_at IS NULL", userId).
+		RunWith(traced(tx)).
+		ExecContext(ctx)
+
+	if err != nil {
+		return false, ipErrors.New(ipErrors.PasswordResetRepoResetPasswordDBQueryFail, err)
+	}
+
+	_, err = r.psql.
+		Insert(constants.UserCredentialsTableName).
+		Columns("user_id", "password_hash").
+		Values(userId, passwordHash).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET password_hash = EXCLUDED.password_hash, updated_at = NOW()").
+		RunWith(traced(tx)).
+		ExecContext(ctx)
+
+	if err != nil {
+		return false, ipErrors.New(ipErrors.PasswordResetRepoResetPasswordDBQueryFail, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, ipErrors.New(ipErrors.PasswordResetRepoResetPasswordDBQueryFail, err)
+	}
+
+	return true, nil
+}
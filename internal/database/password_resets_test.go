@@ -0,0 +1,143 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+)
+
+var _ = Describe("PasswordResets", Ordered, func() {
+	var repo database.Repo
+	var dbMock sqlmock.Sqlmock
+	var closeFunc func()
+
+	BeforeAll(func() {
+		repo, dbMock, closeFunc = mocks.CreateRepoWithMockedDBDriver()
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	AfterAll(func() {
+		closeFunc()
+	})
+
+	Describe("CreatePasswordResetToken", func() {
+		insertQuery := fmt.Sprintf(
+			"INSERT INTO %s (user_id,token_hash,expires_at) SELECT user_id, $1, $2::timestamptz FROM %s WHERE LOWER(email) = LOWER($3)",
+			constants.PasswordResetTokensTableName,
+			constants.UsersTableName)
+		expiresAt := time.Now().Add(time.Hour)
+
+		It("should store the token when the email exists", func() {
+			dbMock.ExpectExec(insertQuery).
+				WithArgs("hash", expiresAt, "test@user.com").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			exists, err := repo.CreatePasswordResetToken(context.Background(), "test@user.com", "hash", expiresAt)
+
+			Expect(err).To(BeNil())
+			Expect(exists).To(Equal(true))
+		})
+
+		It("should return false when the email does not exist", func() {
+			dbMock.ExpectExec(insertQuery).
+				WithArgs("hash", expiresAt, "missing@user.com").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			exists, err := repo.CreatePasswordResetToken(context.Background(), "missing@user.com", "hash", expiresAt)
+
+			Expect(err).To(BeNil())
+			Expect(exists).To(Equal(false))
+		})
+
+		It("should return error if DB throws error", func() {
+			expectedErr := errors.New("DB threw an error!")
+
+			dbMock.ExpectExec(insertQuery).
+				WillReturnError(expectedErr)
+
+			exists, err := repo.CreatePasswordResetToken(context.Background(), "test@user.com", "hash", expiresAt)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.PasswordResetRepoCreateTokenDBQueryFail))
+			Expect(exists).To(Equal(false))
+		})
+	})
+
+	Describe("ResetPassword", func() {
+		consumeQuery := fmt.Sprintf(
+			"UPDATE %s SET used_at = NOW() WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW() RETURNING user_id",
+			constants.PasswordResetTokensTableName)
+		invalidateQuery := fmt.Sprintf(
+			"UPDATE %s SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL",
+			constants.PasswordResetTokensTableName)
+		credentialsQuery := fmt.Sprintf(
+			"INSERT INTO %s (user_id,password_hash) VALUES ($1,$2) ON CONFLICT (user_id) DO UPDATE SET password_hash = EXCLUDED.password_hash, updated_at = NOW()",
+			constants.UserCredentialsTableName)
+
+		It("should consume the token and set the password", func() {
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(consumeQuery).
+				WithArgs("hash").
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+			dbMock.ExpectExec(invalidateQuery).
+				WithArgs(1).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			dbMock.ExpectExec(credentialsQuery).
+				WithArgs(1, "passwordHash").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectCommit()
+
+			reset, err := repo.ResetPassword(context.Background(), "hash", "passwordHash")
+
+			Expect(err).To(BeNil())
+			Expect(reset).To(Equal(true))
+		})
+
+		It("should return false when the token is invalid, used or expired", func() {
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(consumeQuery).
+				WithArgs("hash").
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+			dbMock.ExpectRollback()
+
+			reset, err := repo.ResetPassword(context.Background(), "hash", "passwordHash")
+
+			Expect(err).To(BeNil())
+			Expect(reset).To(Equal(false))
+		})
+
+		It("should rollback and return error if DB throws error", func() {
+			expectedErr := errors.New("DB threw an error!")
+
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(consumeQuery).
+				WithArgs("hash").
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+			dbMock.ExpectExec(invalidateQuery).
+				WithArgs(1).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			dbMock.ExpectExec(credentialsQuery).
+				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
+
+			reset, err := repo.ResetPassword(context.Background(), "hash", "passwordHash")
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.PasswordResetRepoResetPasswordDBQueryFail))
+			Expect(reset).To(Equal(false))
+		})
+	})
+})
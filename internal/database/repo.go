@@ -1,54 +1,111 @@
 package database
 
 import (
-	"fmt"
+	"context"
+	"database/sql"
 	"time"
 
 	"github.com/Masterminds/squirrel"
-	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
 
 	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
-	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/metrics"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/retry"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/secrets"
 )
 
 type Repo interface {
-	GetAllUsers() ([]models.User, errors.ErrorCode, error)
-	CreateUser(models.User) (*models.User, errors.ErrorCode, error)
-	UpdateUser(models.User) (*models.User, errors.ErrorCode, error)
-	DeleteUser(userId int) (bool, errors.ErrorCode, error)
+	GetAllUsers(ctx context.Context) ([]models.User, error)
+	GetAllUsersAsOf(ctx context.Context, asOf time.Time) ([]models.User, error)
+	GetUser(ctx context.Context, userId int) (*models.User, error)
+	GetUserAsOf(ctx context.Context, userId int, asOf time.Time) (*models.User, error)
+	GetUserHistory(ctx context.Context, userId int) ([]models.UserVersion, error)
+	CreateUser(ctx context.Context, user models.User, info models.AuditInfo) (*models.User, error)
+	UpdateUser(ctx context.Context, user models.User, info models.AuditInfo) (*models.User, error)
+	DeleteUser(ctx context.Context, userId int, info models.AuditInfo) (bool, error)
+
+	GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+
+	CreateScheduledStatusChange(ctx context.Context, change models.ScheduledStatusChange, info models.AuditInfo) (*models.ScheduledStatusChange, error)
+	GetPendingStatusChanges(ctx context.Context, userId int) ([]models.ScheduledStatusChange, error)
+	CancelScheduledStatusChange(ctx context.Context, changeId int, info models.AuditInfo) (bool, error)
+	ApplyDueStatusChanges(ctx context.Context, now time.Time) (int, error)
+
+	CreatePasswordResetToken(ctx context.Context, email string, tokenHash string, expiresAt time.Time) (bool, error)
+	ResetPassword(ctx context.Context, tokenHash string, passwordHash string) (bool, error)
+
+	Ping(ctx context.Context) error
+	SchemaChangeDeployed(ctx context.Context, change string) (bool, error)
+
+	Close() error
 }
 
 type ServiceRepo struct {
 	DB 		*sqlx.DB
 	psql 	squirrel.StatementBuilderType
+
+	// StatusTransitions are the user status changes UpdateUser and
+	// CreateScheduledStatusChange will allow
+	StatusTransitions models.StatusTransitions
+
+	// QueryTimeout limits how long each call to the repo can spend querying
+	// the DB. No limit is applied when it is 0.
+	QueryTimeout time.Duration
+
+	// Reloads the password new connections authenticate with, if it is
+	// read from a file
+	password *secrets.Reloader
 }
 
 func CreateDefault() ServiceRepo {
 	return ServiceRepo{
 		psql: 	squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		StatusTransitions: models.DefaultStatusTransitions,
 	}
 }
 
-// CreateNewRepo returns a new repo instance with an initialized sqlx.DB client
+// CreateNewRepo returns a new repo instance with an initialized sqlx.DB client,
+// allowing the user status changes in statusTransitions
+//
+// The config is validated first, returning an errors.Error with the error
+// code listing every invalid setting.
+// Connecting is retried with backoff, as the DB may still be starting up,
+// until the config's ConnectMaxWait passes or the context is done.
 //
 // Returns error if either the sqlx.DB client fails to open, or if we cannot
 // verify the connection to the DB.
 // If error is returned, an error code associated with it will be returned as well.
-func CreateNewRepo(dbConfig config.DatabaseConfig) (Repo, error) {
-	dsnString := fmt.Sprintf(
-		"user=%s password=%s host=%s port=%s database=%s sslmode=%s", 
-		dbConfig.Username, 
-		dbConfig.Password,
-		dbConfig.Host,
-		dbConfig.Port, 
-		dbConfig.Name, 
-		dbConfig.SSLMode)
-
-	db, err := sqlx.Connect("pgx", dsnString)
+func CreateNewRepo(
+	ctx context.Context,
+	dbConfig config.DatabaseConfig,
+	statusTransitions models.StatusTransitions,
+) (Repo, error) {
+	if err := dbConfig.Validate(); err != nil {
+		return nil, ipErrors.New(ipErrors.DBRepoInvalidConfig, err)
+	}
+
+	connConfig, err := ConnConfig(dbConfig)
+	if err != nil {
+		return nil, ipErrors.New(ipErrors.DBRepoInvalidConfig, err)
+	}
+
+	db, password, err := openDB(ctx, dbConfig, connConfig)
+	if err != nil {
+		return nil, ipErrors.New(ipErrors.DBRepoInvalidConfig, err)
+	}
+
+	repo := CreateDefault()
+	repo.DB = db
+	repo.StatusTransitions = statusTransitions
+	repo.QueryTimeout = time.Duration(dbConfig.QueryTimeout) * time.Second
+	repo.password = password
+
+	err = retry.Do(ctx, "connect to DB", ConnectBackoff(dbConfig), db.PingContext)
 	if err != nil {
+		repo.Close()
 		return nil, err
 	}
 
@@ -56,12 +113,61 @@ func CreateNewRepo(dbConfig config.DatabaseConfig) (Repo, error) {
 	// idle connections for too long
 	db.SetMaxIdleConns(dbConfig.MaxIdleConnections)
 	db.SetConnMaxIdleTime(time.Duration(dbConfig.ConnectionMaxIdleTime) * time.Minute)
+	db.SetMaxOpenConns(dbConfig.MaxOpenConnections)
+	db.SetConnMaxLifetime(time.Duration(dbConfig.ConnectionMaxLifetime) * time.Minute)
 
-	logging.Logger.Info("Successfully connected to DB")
+	logging.Logger.Info("Successfully connected to DB",
+		"host", connConfig.Host,
+		"database", connConfig.Database,
+		"application_name", connConfig.RuntimeParams["application_name"])
 
-	repo := CreateDefault()
-	repo.DB = db
+	// Expose the pool stats so connection exhaustion shows up in metrics
+	if err := metrics.RegisterDB(db.DB, connConfig.Database); err != nil {
+		logging.Error("CreateNewRepo", "failed to register DB pool metrics", err)
+	}
 
 	return &repo, nil
 }
 
+// ConnectBackoff returns the backoff connecting to the DB is retried with
+func ConnectBackoff(dbConfig config.DatabaseConfig) retry.Backoff {
+	return retry.Backoff{
+		Initial: time.Duration(dbConfig.ConnectRetryInitial) * time.Millisecond,
+		Max:     time.Duration(dbConfig.ConnectRetryMax) * time.Millisecond,
+		MaxWait: time.Duration(dbConfig.ConnectMaxWait) * time.Second,
+	}
+}
+
+// Close closes the DB connection pool, waiting for queries in progress
+// to finish, and stops reloading its password.
+func (r ServiceRepo) Close() error {
+	if r.password != nil {
+		r.password.Stop()
+	}
+
+	return r.DB.Close()
+}
+
+// withTimeout returns a copy of the context that is cancelled once the
+// repo's QueryTimeout has passed.
+//
+// The cancel func must be called to release the context's resources.
+func (r ServiceRepo) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, r.QueryTimeout)
+}
+
+// rollback rolls back the transaction and logs if it fails.
+//
+// Does nothing if the transaction has already been committed, or was
+// rolled back because its context was cancelled, so it is safe to defer
+// straight after beginning a transaction.
+func rollback(ctx context.Context, fromFunc string, tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		logging.ErrorContext(ctx, fromFunc, "failed to rollback transaction", err)
+	}
+}
+
@@ -0,0 +1,58 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+)
+
+var _ = Describe("Repo", func() {
+	var repo database.ServiceRepo
+	var dbMock sqlmock.Sqlmock
+	var db *sql.DB
+
+	BeforeEach(func() {
+		db, dbMock, _ = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+
+		repo = database.CreateDefault()
+		repo.DB = sqlx.NewDb(db, "sqlmock")
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+		db.Close()
+	})
+
+	It("should not query the DB when the context is already cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		users, err := repo.GetAllUsers(ctx)
+
+		Expect(err).To(MatchError(ipErrors.UsersRepoGetAllUsersDBQueryFail))
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(users).To(BeEmpty())
+	})
+
+	It("should stop the query once the query timeout has passed", func() {
+		repo.QueryTimeout = 10 * time.Millisecond
+
+		dbMock.ExpectQuery("SELECT * FROM integra_partners.users").
+			WillDelayFor(time.Second).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+		start := time.Now()
+		_, err := repo.GetAllUsers(context.Background())
+
+		Expect(err).To(MatchError(ipErrors.UsersRepoGetAllUsersDBQueryFail))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+})
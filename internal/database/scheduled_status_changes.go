@@ -0,0 +1,371 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+)
+
+// scheduledStatusChangeColumns are the columns of the scheduled_status_changes
+// table in the order they are scanned by scanScheduledStatusChange.
+var scheduledStatusChangeColumns = []string{
+	"change_id", "user_id", "user_status", "effective_at", "actor", "reason", "created_at", "applied_at", "cancelled_at",
+}
+
+// pendingStatusChange is the condition matching changes that have neither
+// been applied nor cancelled.
+var pendingStatusChange = squirrel.Expr("applied_at IS NULL AND cancelled_at IS NULL")
+
+// CreateScheduledStatusChange stores a status change for a user that will be
+// applied once its effective time has passed.
+//
+// The user is locked and read first so the change can be refused straight
+// away if the user can't change from their current status to it.
+// The change is recorded in the audit log in the same transaction.
+// Returns the created ScheduledStatusChange if successful.
+// Returns an errors.Error with UsersRepoIllegalStatusTransition if the status change
+// is not allowed by the repo's StatusTransitions.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) CreateScheduledStatusChange(
+	ctx context.Context,
+	change models.ScheduledStatusChange,
+	info models.AuditInfo,
+) (*models.ScheduledStatusChange, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, ipErrors.New(ipErrors.StatusChangesRepoCreateDBQueryFail, err)
+	}
+	defer rollback(ctx, "CreateScheduledStatusChange", tx)
+
+	currentUser, err := r.lockUser(ctx, tx, change.UserId)
+	if err == sql.ErrNoRows {
+		return nil, ipErrors.New(ipErrors.StatusChangesRepoUserNotFound, err)
+	}
+
+	if err != nil {
+		return nil, ipErrors.New(ipErrors.StatusChangesRepoCreateDBQueryFail, err)
+	}
+
+	if err := r.checkStatusTransition(currentUser, change.UserStatus); err != nil {
+		return nil, err
+	}
+
+	var requestId interface{}
+	if info.RequestId != "" {
+		requestId = info.RequestId
+	}
+
+	var reason interface{}
+	if change.Reason != "" {
+		reason = change.Reason
+	}
+
+	row := r.psql.
+		Insert(constants.ScheduledStatusChangesTableName).
+		Columns("user_id", "user_status", "effective_at", "actor", "request_id", "reason").
+		Values(change.UserId, change.UserStatus, change.EffectiveAt, info.Actor, requestId, reason).
+		Suffix("RETURNING " + strings.Join(scheduledStatusChangeColumns, ", ")).
+		RunWith(traced(tx)).
+		QueryRowContext(ctx)
+
+	returnedChange, err := scanScheduledStatusChange(row)
+	if err != nil {
+		if valid, errCode := checkUserDBError(err); valid {
+			return nil, ipErrors.New(errCode, err)
+		}
+
+		return nil, ipErrors.New(ipErrors.StatusChangesRepoCreateDBQueryFail, err)
+	}
+
+	info.Reason = change.Reason
+	if err := r.createStatusChangeAuditEntry(
+		ctx, tx, models.AuditActionScheduleStatusChange, nil, returnedChange, info); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ipErrors.New(ipErrors.StatusChangesRepoCreateDBQueryFail, err)
+	}
+
+	return returnedChange, nil
+}
+
+// GetPendingStatusChanges fetches the status changes that have not been
+// applied or cancelled yet, ordered by when they take effect.
+//
+// Only changes for the user with the associated id are returned, unless
+// the id is 0.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) GetPendingStatusChanges(ctx context.Context, userId int) ([]models.ScheduledStatusChange, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	changes := []models.ScheduledStatusChange{}
+
+	query := r.psql.
+		Select(scheduledStatusChangeColumns...).
+		From(constants.ScheduledStatusChangesTableName).
+		Where(pendingStatusChange).
+		OrderBy("effective_at", "change_id")
+
+	if userId != 0 {
+		query = query.Where("user_id = ?", userId)
+	}
+
+	rows, err := query.RunWith(traced(r.DB)).QueryContext(ctx)
+	if err != nil {
+		return changes, ipErrors.New(ipErrors.StatusChangesRepoGetDBQueryFail, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		change, err := scanScheduledStatusChange(rows)
+		if err != nil {
+			logging.ErrorContext(ctx, "GetPendingStatusChanges", "failed to scan scheduled status change data", err)
+			continue
+		}
+
+		changes = append(changes, *change)
+	}
+
+	return changes, nil
+}
+
+// CancelScheduledStatusChange cancels the pending status change with the
+// associated id.
+//
+// The cancellation is recorded in the audit log in the same transaction.
+// Returns true if a pending change was cancelled, false if no pending
+// change with the id exists.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) CancelScheduledStatusChange(ctx context.Context, changeId int, info models.AuditInfo) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, ipErrors.New(ipErrors.StatusChangesRepoCancelDBQueryFail, err)
+	}
+	defer rollback(ctx, "CancelScheduledStatusChange", tx)
+
+	row := r.psql.
+		Update(constants.ScheduledStatusChangesTableName).
+		Set("cancelled_at", squirrel.Expr("NOW()")).
+		Where("change_id = ?", changeId).
+		Where(pendingStatusChange).
+		Suffix("RETURNING " + strings.Join(scheduledStatusChangeColumns, ", ")).
+		RunWith(traced(tx)).
+		QueryRowContext(ctx)
+
+	cancelledChange, err := scanScheduledStatusChange(row)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, ipErrors.New(ipErrors.StatusChangesRepoCancelDBQueryFail, err)
+	}
+
+	// The change was pending until now, so it only differs by being cancelled
+	pendingChange := *cancelledChange
+	pendingChange.CancelledAt = nil
+
+	if err := r.createStatusChangeAuditEntry(
+		ctx, tx, models.AuditActionCancelStatusChange, &pendingChange, cancelledChange, info); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, ipErrors.New(ipErrors.StatusChangesRepoCancelDBQueryFail, err)
+	}
+
+	return true, nil
+}
+
+// ApplyDueStatusChanges applies every pending status change that has taken
+// effect by now.
+//
+// Each change is applied in its own transaction along with marking it as
+// applied, so a change is only ever applied once even with multiple
+// instances running. Changes that fail are left pending to be retried,
+// except for illegal status transitions which are cancelled instead.
+// Returns the number of changes applied.
+// Stops applying changes once the context is done.
+// Returns an errors.Error with the error code if fetching the due changes fails.
+func (r ServiceRepo) ApplyDueStatusChanges(ctx context.Context, now time.Time) (int, error) {
+	// Each change gets its own timeout, so only the fetch is limited here
+	queryCtx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.psql.
+		Select("change_id").
+		From(constants.ScheduledStatusChangesTableName).
+		Where(pendingStatusChange).
+		Where("effective_at <= ?", now).
+		OrderBy("effective_at", "change_id").
+		RunWith(traced(r.DB)).
+		QueryContext(queryCtx)
+
+	if err != nil {
+		return 0, ipErrors.New(ipErrors.StatusChangesRepoApplyDBQueryFail, err)
+	}
+
+	changeIds := []int{}
+	for rows.Next() {
+		var changeId int
+		if err := rows.Scan(&changeId); err != nil {
+			logging.ErrorContext(ctx, "ApplyDueStatusChanges", "failed to scan scheduled status change id", err)
+			continue
+		}
+
+		changeIds = append(changeIds, changeId)
+	}
+	rows.Close()
+
+	applied := 0
+	for _, changeId := range changeIds {
+		if err := ctx.Err(); err != nil {
+			return applied, ipErrors.New(ipErrors.StatusChangesRepoApplyDBQueryFail, err)
+		}
+
+		ok, err := r.applyStatusChange(ctx, changeId)
+		if err != nil {
+			logging.ErrorContext(ctx, "ApplyDueStatusChanges", "failed to apply scheduled status change", err)
+			continue
+		}
+
+		if ok {
+			applied++
+		}
+	}
+
+	return applied, nil
+}
+
+// applyStatusChange applies the scheduled status change with the associated
+// id to its user and marks it as applied.
+//
+// Returns false if the change is no longer pending, e.g. it was applied by
+// another instance or cancelled since it was fetched, or if the change was
+// cancelled because its status transition is not allowed.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) applyStatusChange(ctx context.Context, changeId int) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, ipErrors.New(ipErrors.StatusChangesRepoApplyDBQueryFail, err)
+	}
+	defer rollback(ctx, "applyStatusChange", tx)
+
+	// SKIP LOCKED lets another instance applying the same change win
+	// instead of both waiting on each other
+	var change models.ScheduledStatusChange
+	var requestId, reason sql.NullString
+	err = r.psql.
+		Select("change_id", "user_id", "user_status", "actor", "request_id", "reason").
+		From(constants.ScheduledStatusChangesTableName).
+		Where("change_id = ?", changeId).
+		Where(pendingStatusChange).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		RunWith(traced(tx)).
+		QueryRowContext(ctx).
+		Scan(&change.ChangeId, &change.UserId, &change.UserStatus, &change.Actor, &requestId, &reason)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, ipErrors.New(ipErrors.StatusChangesRepoApplyDBQueryFail, err)
+	}
+
+	currentUser, err := r.lockUser(ctx, tx, change.UserId)
+	if err != nil {
+		return false, ipErrors.New(ipErrors.StatusChangesRepoApplyDBQueryFail, err)
+	}
+
+	user := *currentUser
+	user.UserStatus = change.UserStatus
+
+	info := models.AuditInfo{Actor: change.Actor, RequestId: requestId.String, Reason: reason.String}
+	_, err = r.updateUser(ctx, tx, currentUser, user, info)
+
+	// Terminal statuses never become legal to leave, so retrying
+	// the change would only fail again
+	if errors.Is(err, ipErrors.UsersRepoIllegalStatusTransition) {
+		logging.ErrorContext(ctx, "applyStatusChange", "cancelling scheduled status change", err)
+
+		return false, r.finishStatusChange(ctx, tx, changeId, "cancelled_at")
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.finishStatusChange(ctx, tx, changeId, "applied_at"); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// finishStatusChange sets the column marking how the change finished and
+// commits the transaction.
+//
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) finishStatusChange(ctx context.Context, tx *sql.Tx, changeId int, column string) error {
+	_, err := r.psql.
+		Update(constants.ScheduledStatusChangesTableName).
+		Set(column, squirrel.Expr("NOW()")).
+		Where("change_id = ?", changeId).
+		RunWith(traced(tx)).
+		ExecContext(ctx)
+
+	if err != nil {
+		return ipErrors.New(ipErrors.StatusChangesRepoApplyDBQueryFail, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ipErrors.New(ipErrors.StatusChangesRepoApplyDBQueryFail, err)
+	}
+
+	return nil
+}
+
+// scanScheduledStatusChange scans a row selected with the
+// scheduledStatusChangeColumns into a ScheduledStatusChange.
+func scanScheduledStatusChange(row squirrel.RowScanner) (*models.ScheduledStatusChange, error) {
+	change := new(models.ScheduledStatusChange)
+	var reason sql.NullString
+
+	err := row.Scan(
+		&change.ChangeId,
+		&change.UserId,
+		&change.UserStatus,
+		&change.EffectiveAt,
+		&change.Actor,
+		&reason,
+		&change.CreatedAt,
+		&change.AppliedAt,
+		&change.CancelledAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	change.Reason = reason.String
+	return change, nil
+}
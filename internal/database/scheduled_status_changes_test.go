@@ -0,0 +1,429 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+)
+
+var _ = Describe("ScheduledStatusChanges", Ordered, func() {
+	var repo database.Repo
+	var dbMock sqlmock.Sqlmock
+	var closeFunc func()
+
+	audit := models.AuditInfo{Actor: "admin", RequestId: "request-1"}
+	auditQuery := fmt.Sprintf(
+		"INSERT INTO %s (actor,action,user_id,changes,request_id,reason) VALUES ($1,$2,$3,$4,$5,$6)",
+		constants.AuditLogTableName)
+	changeColumns := []string{"change_id", "user_id", "user_status", "effective_at", "actor", "reason", "created_at", "applied_at", "cancelled_at"}
+	returningColumns := "change_id, user_id, user_status, effective_at, actor, reason, created_at, applied_at, cancelled_at"
+	effectiveAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	testChange := models.ScheduledStatusChange{
+		ChangeId:    1,
+		UserId:      1,
+		UserStatus:  "I",
+		EffectiveAt: effectiveAt,
+		Actor:       "admin",
+		CreatedAt:   createdAt,
+	}
+
+	BeforeAll(func() {
+		repo, dbMock, closeFunc = mocks.CreateRepoWithMockedDBDriver()
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	AfterAll(func() {
+		closeFunc()
+	})
+
+	Describe("CreateScheduledStatusChange", func() {
+		lockUserQuery := fmt.Sprintf(
+			"SELECT * FROM %s WHERE user_id = $1 FOR UPDATE",
+			constants.UsersTableName)
+		insertQuery := fmt.Sprintf(
+			"INSERT INTO %s (user_id,user_status,effective_at,actor,request_id,reason) VALUES ($1,$2,$3,$4,$5,$6) RETURNING %s",
+			constants.ScheduledStatusChangesTableName, returningColumns)
+		userColumns := []string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}
+
+		// expectLockUser expects the user to be locked and read with the status
+		expectLockUser := func(status string) {
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(lockUserQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow("1", "testUser", "test", "user", "test@user.com", status, "sales"))
+		}
+
+		It("should create the scheduled status change", func() {
+			expectLockUser("A")
+			dbMock.ExpectQuery(insertQuery).
+				WithArgs(1, "I", effectiveAt, audit.Actor, audit.RequestId, "on leave").
+				WillReturnRows(sqlmock.NewRows(changeColumns).
+					AddRow(1, 1, "I", effectiveAt, "admin", "on leave", createdAt, nil, nil))
+			dbMock.ExpectExec(auditQuery).
+				WithArgs(audit.Actor, models.AuditActionScheduleStatusChange, 1, sqlmock.AnyArg(), audit.RequestId, "on leave").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectCommit()
+
+			change, err := repo.CreateScheduledStatusChange(
+				context.Background(),
+				models.ScheduledStatusChange{UserId: 1, UserStatus: "I", EffectiveAt: effectiveAt, Reason: "on leave"}, audit)
+
+			expected := testChange
+			expected.Reason = "on leave"
+
+			Expect(err).To(BeNil())
+			Expect(change).To(Equal(&expected))
+		})
+
+		It("should rollback the change when the audit entry fails", func() {
+			expectedErr := errors.New("DB encountered and error!")
+
+			expectLockUser("A")
+			dbMock.ExpectQuery(insertQuery).
+				WillReturnRows(sqlmock.NewRows(changeColumns).
+					AddRow(1, 1, "I", effectiveAt, "admin", nil, createdAt, nil, nil))
+			dbMock.ExpectExec(auditQuery).
+				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
+
+			change, err := repo.CreateScheduledStatusChange(context.Background(), testChange, audit)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.AuditRepoCreateEntryDBQueryFail))
+			Expect(change).To(BeNil())
+		})
+
+		It("should fail when the user can't change from their current status", func() {
+			expectLockUser("T")
+			dbMock.ExpectRollback()
+
+			change, err := repo.CreateScheduledStatusChange(context.Background(), testChange, audit)
+
+			Expect(err).To(MatchError(ipErrors.UsersRepoIllegalStatusTransition))
+			Expect(change).To(BeNil())
+		})
+
+		It("should fail when the user does not exist", func() {
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(lockUserQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(userColumns))
+			dbMock.ExpectRollback()
+
+			change, err := repo.CreateScheduledStatusChange(context.Background(), testChange, audit)
+
+			Expect(err).To(MatchError(ipErrors.StatusChangesRepoUserNotFound))
+			Expect(change).To(BeNil())
+		})
+
+		It("should fail when the user status is invalid", func() {
+			expectedErr := &pgconn.PgError{
+				Code:    pgerrcode.InvalidTextRepresentation,
+				Message: "invalid input value for enum user_status",
+			}
+
+			expectLockUser("")
+			dbMock.ExpectQuery(insertQuery).
+				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
+
+			change, err := repo.CreateScheduledStatusChange(context.Background(), testChange, audit)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoUserInvalidUserStatus))
+			Expect(change).To(BeNil())
+		})
+
+		It("should fail due to DB error", func() {
+			expectedErr := errors.New("DB encountered and error!")
+
+			expectLockUser("A")
+			dbMock.ExpectQuery(insertQuery).
+				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
+
+			change, err := repo.CreateScheduledStatusChange(context.Background(), testChange, audit)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.StatusChangesRepoCreateDBQueryFail))
+			Expect(change).To(BeNil())
+		})
+	})
+
+	Describe("GetPendingStatusChanges", func() {
+		selectQuery := fmt.Sprintf(
+			"SELECT %s FROM %s WHERE applied_at IS NULL AND cancelled_at IS NULL ORDER BY effective_at, change_id",
+			returningColumns, constants.ScheduledStatusChangesTableName)
+		userSelectQuery := fmt.Sprintf(
+			"SELECT %s FROM %s WHERE applied_at IS NULL AND cancelled_at IS NULL AND user_id = $1 ORDER BY effective_at, change_id",
+			returningColumns, constants.ScheduledStatusChangesTableName)
+
+		It("should return all pending changes", func() {
+			dbMock.ExpectQuery(selectQuery).
+				WillReturnRows(sqlmock.NewRows(changeColumns).
+					AddRow(1, 1, "I", effectiveAt, "admin", nil, createdAt, nil, nil))
+
+			changes, err := repo.GetPendingStatusChanges(context.Background(), 0)
+
+			Expect(err).To(BeNil())
+			Expect(changes).To(Equal([]models.ScheduledStatusChange{testChange}))
+		})
+
+		It("should only return pending changes for the user", func() {
+			dbMock.ExpectQuery(userSelectQuery).
+				WithArgs(2).
+				WillReturnRows(sqlmock.NewRows(changeColumns))
+
+			changes, err := repo.GetPendingStatusChanges(context.Background(), 2)
+
+			Expect(err).To(BeNil())
+			Expect(changes).To(BeEmpty())
+		})
+
+		It("should return error if DB throws error", func() {
+			expectedErr := errors.New("DB threw an error!")
+
+			dbMock.ExpectQuery(selectQuery).
+				WillReturnError(expectedErr)
+
+			changes, err := repo.GetPendingStatusChanges(context.Background(), 0)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.StatusChangesRepoGetDBQueryFail))
+			Expect(changes).To(BeEmpty())
+		})
+	})
+
+	Describe("CancelScheduledStatusChange", func() {
+		cancelQuery := fmt.Sprintf(
+			"UPDATE %s SET cancelled_at = NOW() WHERE change_id = $1 AND applied_at IS NULL AND cancelled_at IS NULL RETURNING %s",
+			constants.ScheduledStatusChangesTableName, returningColumns)
+		cancelledAt := time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)
+
+		It("should cancel the pending change and record it in the audit log", func() {
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(cancelQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(changeColumns).
+					AddRow(1, 1, "I", effectiveAt, "admin", nil, createdAt, nil, cancelledAt))
+			dbMock.ExpectExec(auditQuery).
+				WithArgs(
+					audit.Actor,
+					models.AuditActionCancelStatusChange,
+					1,
+					`{"cancelled_at":{"before":null,"after":"2026-02-15T00:00:00Z"}}`,
+					audit.RequestId,
+					nil).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectCommit()
+
+			cancelled, err := repo.CancelScheduledStatusChange(context.Background(), 1, audit)
+
+			Expect(err).To(BeNil())
+			Expect(cancelled).To(BeTrue())
+		})
+
+		It("should return false when no pending change exists", func() {
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(cancelQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(changeColumns))
+			dbMock.ExpectRollback()
+
+			cancelled, err := repo.CancelScheduledStatusChange(context.Background(), 1, audit)
+
+			Expect(err).To(BeNil())
+			Expect(cancelled).To(BeFalse())
+		})
+
+		It("should keep the change pending when the audit entry fails", func() {
+			expectedErr := errors.New("DB threw an error!")
+
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(cancelQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(changeColumns).
+					AddRow(1, 1, "I", effectiveAt, "admin", nil, createdAt, nil, cancelledAt))
+			dbMock.ExpectExec(auditQuery).
+				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
+
+			cancelled, err := repo.CancelScheduledStatusChange(context.Background(), 1, audit)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.AuditRepoCreateEntryDBQueryFail))
+			Expect(cancelled).To(BeFalse())
+		})
+
+		It("should return error if DB throws error", func() {
+			expectedErr := errors.New("DB threw an error!")
+
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(cancelQuery).
+				WithArgs(1).
+				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
+
+			cancelled, err := repo.CancelScheduledStatusChange(context.Background(), 1, audit)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.StatusChangesRepoCancelDBQueryFail))
+			Expect(cancelled).To(BeFalse())
+		})
+	})
+
+	Describe("ApplyDueStatusChanges", func() {
+		now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+		dueQuery := fmt.Sprintf(
+			"SELECT change_id FROM %s WHERE applied_at IS NULL AND cancelled_at IS NULL AND effective_at <= $1 ORDER BY effective_at, change_id",
+			constants.ScheduledStatusChangesTableName)
+		lockChangeQuery := fmt.Sprintf(
+			"SELECT change_id, user_id, user_status, actor, request_id, reason FROM %s WHERE change_id = $1 AND applied_at IS NULL AND cancelled_at IS NULL FOR UPDATE SKIP LOCKED",
+			constants.ScheduledStatusChangesTableName)
+		lockUserQuery := fmt.Sprintf(
+			"SELECT * FROM %s WHERE user_id = $1 FOR UPDATE",
+			constants.UsersTableName)
+		updateUserQuery := fmt.Sprintf(
+			"UPDATE %s SET department = $1, email = $2, first_name = $3, last_name = $4, user_name = $5, user_status = $6 WHERE user_id = $7 RETURNING *",
+			constants.UsersTableName)
+		appliedQuery := fmt.Sprintf(
+			"UPDATE %s SET applied_at = NOW() WHERE change_id = $1",
+			constants.ScheduledStatusChangesTableName)
+		cancelledQuery := fmt.Sprintf(
+			"UPDATE %s SET cancelled_at = NOW() WHERE change_id = $1",
+			constants.ScheduledStatusChangesTableName)
+		userColumns := []string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}
+		lockChangeColumns := []string{"change_id", "user_id", "user_status", "actor", "request_id", "reason"}
+
+		It("should apply the due changes", func() {
+			dbMock.ExpectQuery(dueQuery).
+				WithArgs(now).
+				WillReturnRows(sqlmock.NewRows([]string{"change_id"}).AddRow(1))
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(lockChangeQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(lockChangeColumns).
+					AddRow(1, 1, "I", "admin", "request-1", "on leave"))
+			dbMock.ExpectQuery(lockUserQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow("1", "testUser", "test", "user", "test@user.com", "A", "sales"))
+			dbMock.ExpectQuery(updateUserQuery).
+				WithArgs("sales", "test@user.com", "test", "user", "testUser", "I", 1).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow("1", "testUser", "test", "user", "test@user.com", "I", "sales"))
+			dbMock.ExpectExec(auditQuery).
+				WithArgs(
+					"admin",
+					models.AuditActionUpdate,
+					1,
+					`{"user_status":{"before":"A","after":"I"}}`,
+					"request-1",
+					"on leave").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectExec(appliedQuery).
+				WithArgs(1).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.ExpectCommit()
+
+			applied, err := repo.ApplyDueStatusChanges(context.Background(), now)
+
+			Expect(err).To(BeNil())
+			Expect(applied).To(Equal(1))
+		})
+
+		It("should cancel changes with an illegal status transition", func() {
+			dbMock.ExpectQuery(dueQuery).
+				WithArgs(now).
+				WillReturnRows(sqlmock.NewRows([]string{"change_id"}).AddRow(1))
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(lockChangeQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(lockChangeColumns).
+					AddRow(1, 1, "A", "admin", nil, nil))
+			dbMock.ExpectQuery(lockUserQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow("1", "testUser", "test", "user", "test@user.com", "T", "sales"))
+			dbMock.ExpectExec(cancelledQuery).
+				WithArgs(1).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.ExpectCommit()
+
+			applied, err := repo.ApplyDueStatusChanges(context.Background(), now)
+
+			Expect(err).To(BeNil())
+			Expect(applied).To(Equal(0))
+		})
+
+		It("should skip changes that are no longer pending", func() {
+			dbMock.ExpectQuery(dueQuery).
+				WithArgs(now).
+				WillReturnRows(sqlmock.NewRows([]string{"change_id"}).AddRow(1))
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(lockChangeQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(lockChangeColumns))
+			dbMock.ExpectRollback()
+
+			applied, err := repo.ApplyDueStatusChanges(context.Background(), now)
+
+			Expect(err).To(BeNil())
+			Expect(applied).To(Equal(0))
+		})
+
+		It("should leave a change pending when applying it fails", func() {
+			dbMock.ExpectQuery(dueQuery).
+				WithArgs(now).
+				WillReturnRows(sqlmock.NewRows([]string{"change_id"}).AddRow(1).AddRow(2))
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(lockChangeQuery).
+				WithArgs(1).
+				WillReturnError(errors.New("DB threw an error!"))
+			dbMock.ExpectRollback()
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(lockChangeQuery).
+				WithArgs(2).
+				WillReturnRows(sqlmock.NewRows(lockChangeColumns))
+			dbMock.ExpectRollback()
+
+			applied, err := repo.ApplyDueStatusChanges(context.Background(), now)
+
+			Expect(err).To(BeNil())
+			Expect(applied).To(Equal(0))
+		})
+
+		It("should return error if fetching due changes fails", func() {
+			expectedErr := errors.New("DB threw an error!")
+
+			dbMock.ExpectQuery(dueQuery).
+				WithArgs(now).
+				WillReturnError(expectedErr)
+
+			applied, err := repo.ApplyDueStatusChanges(context.Background(), now)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.StatusChangesRepoApplyDBQueryFail))
+			Expect(applied).To(Equal(0))
+		})
+	})
+})
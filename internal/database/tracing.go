@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/tracing"
+)
+
+// Attribute keys of DB spans not covered by the semantic conventions
+const (
+	RowsAffectedKey = attribute.Key("db.rows_affected")
+	// SQLSTATE code of the error returned by Postgres
+	ResponseStatusCodeKey = attribute.Key("db.response.status_code")
+)
+
+var tablePattern = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE|JOIN)\s+([A-Za-z0-9_."]+)`)
+
+// tracedRunner runs each statement in a span recording the statement, the
+// table it acts on and the rows it affected, as a child of the span in
+// the context it is run with.
+//
+// The args are left out of the span, so the values of the statement
+// aren't exported.
+type tracedRunner struct {
+	runner squirrel.StdSqlCtx
+}
+
+// traced wraps the DB or transaction so the statements squirrel runs with
+// it are traced
+func traced(runner squirrel.StdSqlCtx) tracedRunner {
+	return tracedRunner{runner: runner}
+}
+
+func (t tracedRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.ExecContext(context.Background(), query, args...)
+}
+
+func (t tracedRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.QueryContext(context.Background(), query, args...)
+}
+
+func (t tracedRunner) QueryRow(query string, args ...interface{}) squirrel.RowScanner {
+	return t.QueryRowContext(context.Background(), query, args...)
+}
+
+func (t tracedRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	result, err := t.runner.ExecContext(ctx, query, args...)
+	if err != nil {
+		recordQueryError(span, err)
+		return result, err
+	}
+
+	if rows, err := result.RowsAffected(); err == nil {
+		span.SetAttributes(RowsAffectedKey.Int64(rows))
+	}
+
+	return result, nil
+}
+
+func (t tracedRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	rows, err := t.runner.QueryContext(ctx, query, args...)
+	if err != nil {
+		recordQueryError(span, err)
+	}
+
+	return rows, err
+}
+
+// QueryRowContext runs the statement in a span that ends once the row
+// has been scanned, as that is when its error is returned.
+func (t tracedRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	ctx, span := startQuerySpan(ctx, query)
+
+	return tracedRow{row: t.runner.QueryRowContext(ctx, query, args...), span: span}
+}
+
+type tracedRow struct {
+	row  *sql.Row
+	span trace.Span
+}
+
+// Scan scans the row and ends its span. No rows being found is not
+// recorded as an error, as callers treat it as a result.
+func (r tracedRow) Scan(dest ...interface{}) error {
+	defer r.span.End()
+
+	err := r.row.Scan(dest...)
+	if err == nil {
+		r.span.SetAttributes(RowsAffectedKey.Int64(1))
+	} else if errors.Is(err, sql.ErrNoRows) {
+		r.span.SetAttributes(RowsAffectedKey.Int64(0))
+	} else {
+		recordQueryError(r.span, err)
+	}
+
+	return err
+}
+
+// startQuerySpan starts a client span named after the statement's
+// operation and table, e.g. "SELECT integra_partners.users"
+func startQuerySpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	operation = strings.ToUpper(operation)
+	name := operation
+
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationName(operation),
+		semconv.DBQueryText(query),
+	}
+
+	if match := tablePattern.FindStringSubmatch(query); match != nil {
+		name = fmt.Sprintf("%s %s", operation, match[1])
+		attrs = append(attrs, semconv.DBCollectionName(match[1]))
+	}
+
+	return tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// recordQueryError records the error on the span, along with its SQLSTATE
+// code if it came from Postgres
+func recordQueryError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		span.SetAttributes(ResponseStatusCodeKey.String(pgErr.Code))
+	}
+}
@@ -0,0 +1,121 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+)
+
+var _ = Describe("Tracing", func() {
+	var repo database.ServiceRepo
+	var dbMock sqlmock.Sqlmock
+	var db *sql.DB
+	var recorder *tracetest.SpanRecorder
+	var previousProvider trace.TracerProvider
+
+	// attrs returns the attributes of the span by key
+	attrs := func(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		values := map[attribute.Key]attribute.Value{}
+		for _, attr := range span.Attributes() {
+			values[attr.Key] = attr.Value
+		}
+		return values
+	}
+
+	BeforeEach(func() {
+		db, dbMock, _ = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+
+		repo = database.CreateDefault()
+		repo.DB = sqlx.NewDb(db, "sqlmock")
+
+		recorder = tracetest.NewSpanRecorder()
+		previousProvider = otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	})
+
+	AfterEach(func() {
+		otel.SetTracerProvider(previousProvider)
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+		db.Close()
+	})
+
+	It("should run queries in a span with the statement and table", func() {
+		query := "SELECT * FROM integra_partners.users"
+		dbMock.ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+		ctx, parent := otel.Tracer("test").Start(context.Background(), "GET /users")
+		_, err := repo.GetAllUsers(ctx)
+		parent.End()
+
+		Expect(err).To(BeNil())
+
+		span := recorder.Ended()[0]
+		Expect(span.Name()).To(Equal("SELECT integra_partners.users"))
+		Expect(span.SpanKind()).To(Equal(trace.SpanKindClient))
+		Expect(span.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		Expect(attrs(span)).To(HaveKeyWithValue(semconv.DBQueryTextKey, attribute.StringValue(query)))
+		Expect(attrs(span)).To(HaveKeyWithValue(semconv.DBCollectionNameKey, attribute.StringValue(constants.UsersTableName)))
+		Expect(attrs(span)).To(HaveKeyWithValue(semconv.DBSystemKey, semconv.DBSystemPostgreSQL.Value))
+	})
+
+	It("should record the rows affected by statements", func() {
+		expiresAt := time.Now().Add(time.Hour)
+		dbMock.ExpectExec(fmt.Sprintf(
+			"INSERT INTO %s (user_id,token_hash,expires_at) SELECT user_id, $1, $2::timestamptz FROM %s WHERE LOWER(email) = LOWER($3)",
+			constants.PasswordResetTokensTableName,
+			constants.UsersTableName)).
+			WithArgs("hash", expiresAt, "test@user.com").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		_, err := repo.CreatePasswordResetToken(context.Background(), "test@user.com", "hash", expiresAt)
+
+		Expect(err).To(BeNil())
+
+		span := recorder.Ended()[0]
+		Expect(span.Name()).To(Equal("INSERT " + constants.PasswordResetTokensTableName))
+		Expect(attrs(span)).To(HaveKeyWithValue(database.RowsAffectedKey, attribute.Int64Value(1)))
+	})
+
+	It("should record the error and SQLSTATE of failed statements", func() {
+		pgErr := &pgconn.PgError{
+			Code:    pgerrcode.UniqueViolation,
+			Message: "duplicate key value violates unique constraint \"users_email_idx\"",
+		}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(fmt.Sprintf(
+			"INSERT INTO %s (user_name,first_name,last_name,email,user_status,department) VALUES ($1,$2,$3,$4,$5,$6) RETURNING *",
+			constants.UsersTableName)).
+			WillReturnError(pgErr)
+		dbMock.ExpectRollback()
+
+		_, err := repo.CreateUser(context.Background(), constants.TestUsers[0], models.AuditInfo{Actor: "admin"})
+
+		Expect(err).To(MatchError(pgErr))
+
+		span := recorder.Ended()[0]
+		Expect(span.Name()).To(Equal("INSERT " + constants.UsersTableName))
+		Expect(span.Status().Code).To(Equal(codes.Error))
+		Expect(attrs(span)).To(HaveKeyWithValue(database.ResponseStatusCodeKey, attribute.StringValue(pgerrcode.UniqueViolation)))
+	})
+})
@@ -0,0 +1,174 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+)
+
+// userHistoryColumns are the user columns of the users_history table in the
+// order they are scanned into a models.User.
+var userHistoryColumns = []string{
+	"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department",
+}
+
+// GetUser fetches the user entry with the associated id from the DB.
+//
+// Returns nil if no user with the id exists.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) GetUser(ctx context.Context, userId int) (*models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return r.getUser(
+		ctx,
+		r.psql.
+			Select("*").
+			From(constants.UsersTableName).
+			Where("user_id = ?", userId))
+}
+
+// GetUserAsOf reconstructs the user entry with the associated id as it was
+// at the given time from the users history.
+//
+// Returns nil if the user did not exist at that time.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) GetUserAsOf(ctx context.Context, userId int, asOf time.Time) (*models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return r.getUser(
+		ctx,
+		r.selectUsersAsOf(asOf).
+			Where("user_id = ?", userId))
+}
+
+// GetAllUsersAsOf reconstructs all user entries as they were at the given time
+// from the users history.
+//
+// Returns a slice of Users.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) GetAllUsersAsOf(ctx context.Context, asOf time.Time) ([]models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	users := []models.User{}
+
+	rows, err := r.selectUsersAsOf(asOf).
+		OrderBy("user_id").
+		RunWith(traced(r.DB)).
+		QueryContext(ctx)
+
+	if err != nil {
+		return users, ipErrors.New(ipErrors.UsersRepoGetAllUsersDBQueryFail, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(
+			&user.UserId,
+			&user.Username,
+			&user.Firstname,
+			&user.Lastname,
+			&user.Email,
+			&user.UserStatus,
+			&user.Department); err != nil {
+			logging.ErrorContext(ctx, "GetAllUsersAsOf", "failed to scan user data", err)
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// GetUserHistory fetches every version of the user with the associated id,
+// oldest first.
+//
+// Returns a slice of UserVersions, which is empty if the user never existed.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) GetUserHistory(ctx context.Context, userId int) ([]models.UserVersion, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	versions := []models.UserVersion{}
+
+	rows, err := r.psql.
+		Select(userHistoryColumns...).
+		Columns("operation", "valid_from", "valid_to").
+		From(constants.UsersHistoryTableName).
+		Where("user_id = ?", userId).
+		OrderBy("valid_from", "history_id").
+		RunWith(traced(r.DB)).
+		QueryContext(ctx)
+
+	if err != nil {
+		return versions, ipErrors.New(ipErrors.UsersRepoGetUserHistoryDBQueryFail, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var version models.UserVersion
+		if err := rows.Scan(
+			&version.UserId,
+			&version.Username,
+			&version.Firstname,
+			&version.Lastname,
+			&version.Email,
+			&version.UserStatus,
+			&version.Department,
+			&version.Operation,
+			&version.ValidFrom,
+			&version.ValidTo); err != nil {
+			logging.ErrorContext(ctx, "GetUserHistory", "failed to scan user history data", err)
+		}
+
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+// selectUsersAsOf creates the query selecting the version of each user that
+// was valid at the given time.
+//
+// Deletes are stored with an empty time range so they are never matched.
+func (r ServiceRepo) selectUsersAsOf(asOf time.Time) squirrel.SelectBuilder {
+	return r.psql.
+		Select(userHistoryColumns...).
+		From(constants.UsersHistoryTableName).
+		Where("valid_from <= ? AND (valid_to IS NULL OR valid_to > ?)", asOf, asOf)
+}
+
+// getUser runs the query for a single user and scans the result.
+func (r ServiceRepo) getUser(ctx context.Context, query squirrel.SelectBuilder) (*models.User, error) {
+	user := new(models.User)
+
+	err := query.
+		RunWith(traced(r.DB)).
+		QueryRowContext(ctx).
+		Scan(&user.UserId,
+			&user.Username,
+			&user.Firstname,
+			&user.Lastname,
+			&user.Email,
+			&user.UserStatus,
+			&user.Department)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, ipErrors.New(ipErrors.UsersRepoGetUserDBQueryFail, err)
+	}
+
+	return user, nil
+}
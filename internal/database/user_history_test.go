@@ -0,0 +1,186 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+)
+
+var _ = Describe("UserHistory", Ordered, func() {
+	var repo database.Repo
+	var dbMock sqlmock.Sqlmock
+	var closeFunc func()
+
+	userColumns := []string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}
+	selectColumns := "user_id, user_name, first_name, last_name, email, user_status, department"
+	asOf := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	BeforeAll(func() {
+		repo, dbMock, closeFunc = mocks.CreateRepoWithMockedDBDriver()
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	AfterAll(func() {
+		closeFunc()
+	})
+
+	Describe("GetUser", func() {
+		selectQuery := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1", constants.UsersTableName)
+
+		It("should return the user", func() {
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow("1", "testUser", "test", "user", "test@user.com", "A", "sales"))
+
+			user, err := repo.GetUser(context.Background(), 1)
+
+			Expect(err).To(BeNil())
+			Expect(user).To(Equal(&constants.TestUsers[0]))
+		})
+
+		It("should return nil if the user does not exist", func() {
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(userColumns))
+
+			user, err := repo.GetUser(context.Background(), 1)
+
+			Expect(err).To(BeNil())
+			Expect(user).To(BeNil())
+		})
+
+		It("should return error if DB throws error", func() {
+			expectedErr := errors.New("DB threw an error!")
+
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(1).
+				WillReturnError(expectedErr)
+
+			user, err := repo.GetUser(context.Background(), 1)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoGetUserDBQueryFail))
+			Expect(user).To(BeNil())
+		})
+	})
+
+	Describe("GetUserAsOf", func() {
+		selectQuery := fmt.Sprintf(
+			"SELECT %s FROM %s WHERE valid_from <= $1 AND (valid_to IS NULL OR valid_to > $2) AND user_id = $3",
+			selectColumns,
+			constants.UsersHistoryTableName)
+
+		It("should return the version of the user valid at the time", func() {
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(asOf, asOf, 1).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow("1", "testUser", "test", "user", "test@user.com", "A", "sales"))
+
+			user, err := repo.GetUserAsOf(context.Background(), 1, asOf)
+
+			Expect(err).To(BeNil())
+			Expect(user).To(Equal(&constants.TestUsers[0]))
+		})
+
+		It("should return nil if the user did not exist at the time", func() {
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(asOf, asOf, 1).
+				WillReturnRows(sqlmock.NewRows(userColumns))
+
+			user, err := repo.GetUserAsOf(context.Background(), 1, asOf)
+
+			Expect(err).To(BeNil())
+			Expect(user).To(BeNil())
+		})
+	})
+
+	Describe("GetAllUsersAsOf", func() {
+		selectQuery := fmt.Sprintf(
+			"SELECT %s FROM %s WHERE valid_from <= $1 AND (valid_to IS NULL OR valid_to > $2) ORDER BY user_id",
+			selectColumns,
+			constants.UsersHistoryTableName)
+
+		It("should return the users valid at the time", func() {
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(asOf, asOf).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow("1", "testUser", "test", "user", "test@user.com", "A", "sales").
+					AddRow("2", "testUser2", "test2", "user", "test2@user.com", "T", "management"))
+
+			users, err := repo.GetAllUsersAsOf(context.Background(), asOf)
+
+			Expect(err).To(BeNil())
+			Expect(users).To(Equal(constants.TestUsers))
+		})
+
+		It("should return error if DB throws error", func() {
+			expectedErr := errors.New("DB threw an error!")
+
+			dbMock.ExpectQuery(selectQuery).
+				WillReturnError(expectedErr)
+
+			users, err := repo.GetAllUsersAsOf(context.Background(), asOf)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoGetAllUsersDBQueryFail))
+			Expect(len(users)).To(Equal(0))
+		})
+	})
+
+	Describe("GetUserHistory", func() {
+		selectQuery := fmt.Sprintf(
+			"SELECT %s, operation, valid_from, valid_to FROM %s WHERE user_id = $1 ORDER BY valid_from, history_id",
+			selectColumns,
+			constants.UsersHistoryTableName)
+		historyColumns := append(append([]string{}, userColumns...), "operation", "valid_from", "valid_to")
+
+		It("should return every version of the user", func() {
+			updatedAt := asOf.AddDate(0, 1, 0)
+
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows(historyColumns).
+					AddRow("1", "testUser", "test", "user", "test@user.com", "I", "sales", "CREATE", asOf, updatedAt).
+					AddRow("1", "testUser", "test", "user", "test@user.com", "A", "sales", "UPDATE", updatedAt, nil))
+
+			versions, err := repo.GetUserHistory(context.Background(), 1)
+
+			Expect(err).To(BeNil())
+			Expect(len(versions)).To(Equal(2))
+			Expect(versions[0].UserStatus).To(Equal("I"))
+			Expect(versions[0].Operation).To(Equal(models.AuditActionCreate))
+			Expect(*versions[0].ValidTo).To(Equal(updatedAt))
+			Expect(versions[1].UserStatus).To(Equal("A"))
+			Expect(versions[1].ValidTo).To(BeNil())
+		})
+
+		It("should return error if DB throws error", func() {
+			expectedErr := errors.New("DB threw an error!")
+
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(1).
+				WillReturnError(expectedErr)
+
+			versions, err := repo.GetUserHistory(context.Background(), 1)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoGetUserHistoryDBQueryFail))
+			Expect(len(versions)).To(Equal(0))
+		})
+	})
+})
@@ -1,7 +1,10 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/Masterminds/squirrel"
@@ -16,19 +19,21 @@ import (
 // GetAllUsers fetchs all user entries from the DB.
 //
 // Returns a slice of Users.
-// Returns an error and error code if creating the SQL query or querying DB fails.
-// If error is returned, an error code associated with it will be returned as well.
-func (r ServiceRepo) GetAllUsers() ([]models.User, ipErrors.ErrorCode, error) {
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) GetAllUsers(ctx context.Context) ([]models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	users := []models.User{}
 
 	rows, err := r.psql.
 		Select("*").
 		From(constants.UsersTableName).
-		RunWith(r.DB).
-		Query()
+		RunWith(traced(r.DB)).
+		QueryContext(ctx)
 
 	if err != nil {
-		return users, ipErrors.UsersRepoGetAllUsersDBQueryFail, err
+		return users, ipErrors.New(ipErrors.UsersRepoGetAllUsersDBQueryFail, err)
 	}
 
 	defer rows.Close()
@@ -42,30 +47,39 @@ func (r ServiceRepo) GetAllUsers() ([]models.User, ipErrors.ErrorCode, error) {
 			&user.Email,
 			&user.UserStatus,
 			&user.Department); err != nil {
-			logging.Error("GetAllUsers", "failed to scan user data", err)
+			logging.ErrorContext(ctx, "GetAllUsers", "failed to scan user data", err)
 		}
 
 		users = append(users, user)
 	}
 
-	return users, 0, err
+	return users, nil
 }
 
 // CreateUser adds a new user entry into the DB.
 //
+// The creation is recorded in the audit log within the same transaction.
 // Returns the created User if successful.
-// Returns an error and error code if creating the SQL query or querying DB fails.
-// If error is returned, an error code associated with it will be returned as well.
-func (r ServiceRepo) CreateUser(user models.User) (*models.User, ipErrors.ErrorCode, error) {
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) CreateUser(ctx context.Context, user models.User, info models.AuditInfo) (*models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, ipErrors.New(ipErrors.UsersRepoCreateUserDBQueryFail, err)
+	}
+	defer rollback(ctx, "CreateUser", tx)
+
 	returnedUser := new(models.User)
 
-	err := r.psql.
+	err = r.psql.
 		Insert(constants.UsersTableName).
 		Columns("user_name", "first_name", "last_name", "email", "user_status", "department").
 		Values(user.Username, user.Firstname, user.Lastname, user.Email, user.UserStatus, user.Department).
 		Suffix("RETURNING *").
-		RunWith(r.DB).
-		QueryRow().
+		RunWith(traced(tx)).
+		QueryRowContext(ctx).
 		Scan(&returnedUser.UserId,
 			&returnedUser.Username,
 			&returnedUser.Firstname,
@@ -77,21 +91,106 @@ func (r ServiceRepo) CreateUser(user models.User) (*models.User, ipErrors.ErrorC
 	if err != nil {
 		// Check duplicate username/email err and return the appropriate error
 		if valid, errCode := checkUserDBError(err); valid {
-			return nil, errCode, err
+			return nil, ipErrors.New(errCode, err)
 		}
 
-		return nil, ipErrors.UsersRepoCreateUserDBQueryFail, err
+		return nil, ipErrors.New(ipErrors.UsersRepoCreateUserDBQueryFail, err)
+	}
+
+	if err := r.createAuditEntry(ctx, tx, models.AuditActionCreate, nil, returnedUser, info); err != nil {
+		return nil, err
 	}
 
-	return returnedUser, 0, nil
+	if err := tx.Commit(); err != nil {
+		return nil, ipErrors.New(ipErrors.UsersRepoCreateUserDBQueryFail, err)
+	}
+
+	return returnedUser, nil
 }
 
 // UpdateUser updates an existing user entry in the DB.
 //
+// The current user is locked and read first so the status transition can be
+// validated and the change recorded in the audit log within the same transaction.
+// Returns the updated User if successful.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) UpdateUser(ctx context.Context, user models.User, info models.AuditInfo) (*models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, ipErrors.New(ipErrors.UsersRepoUpdateUserDBQueryFail, err)
+	}
+	defer rollback(ctx, "UpdateUser", tx)
+
+	currentUser, err := r.lockUser(ctx, tx, user.UserId)
+	if err != nil {
+		return nil, ipErrors.New(ipErrors.UsersRepoUpdateUserDBQueryFail, err)
+	}
+
+	returnedUser, err := r.updateUser(ctx, tx, currentUser, user, info)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ipErrors.New(ipErrors.UsersRepoUpdateUserDBQueryFail, err)
+	}
+
+	return returnedUser, nil
+}
+
+// lockUser reads the user entry with the associated id and locks it
+// until the transaction ends.
+//
+// Returns sql.ErrNoRows if the user does not exist.
+func (r ServiceRepo) lockUser(ctx context.Context, tx *sql.Tx, userId int) (*models.User, error) {
+	currentUser := new(models.User)
+
+	err := r.psql.
+		Select("*").
+		From(constants.UsersTableName).
+		Where("user_id = ?", userId).
+		Suffix("FOR UPDATE").
+		RunWith(traced(tx)).
+		QueryRowContext(ctx).
+		Scan(&currentUser.UserId,
+			&currentUser.Username,
+			&currentUser.Firstname,
+			&currentUser.Lastname,
+			&currentUser.Email,
+			&currentUser.UserStatus,
+			&currentUser.Department)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return currentUser, nil
+}
+
+// updateUser updates the locked currentUser entry with the set fields of user
+// and records the change in the audit log as part of the transaction.
+//
 // Returns the updated User if successful.
-// Returns an error and error code if creating the SQL query or querying DB fails.
-// If error is returned, an error code associated with it will be returned as well.
-func (r ServiceRepo) UpdateUser(user models.User) (*models.User, ipErrors.ErrorCode, error) {
+// Returns an errors.Error with UsersRepoIllegalStatusTransition if the status change
+// is not allowed by the repo's StatusTransitions.
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) updateUser(
+	ctx context.Context,
+	tx *sql.Tx,
+	currentUser *models.User,
+	user models.User,
+	info models.AuditInfo,
+) (*models.User, error) {
+	// An empty status is left out of the update, so it never changes
+	if user.UserStatus != "" {
+		if err := r.checkStatusTransition(currentUser, user.UserStatus); err != nil {
+			return nil, err
+		}
+	}
+
 	returnedUser := new(models.User)
 
 	// Creates our set statements
@@ -99,10 +198,10 @@ func (r ServiceRepo) UpdateUser(user models.User) (*models.User, ipErrors.ErrorC
 
 	err := r.psql.Update(constants.UsersTableName).
 		SetMap(setMap).
-		Where("user_id = ?", user.UserId).
+		Where("user_id = ?", currentUser.UserId).
 		Suffix("RETURNING *").
-		RunWith(r.DB).
-		QueryRow().
+		RunWith(traced(tx)).
+		QueryRowContext(ctx).
 		Scan(&returnedUser.UserId,
 			&returnedUser.Username,
 			&returnedUser.Firstname,
@@ -114,33 +213,89 @@ func (r ServiceRepo) UpdateUser(user models.User) (*models.User, ipErrors.ErrorC
 	if err != nil {
 		// Check duplicate username/email err and return the appropriate error
 		if valid, errCode := checkUserDBError(err); valid {
-			return nil, errCode, err
+			return nil, ipErrors.New(errCode, err)
 		}
 
-		return nil, ipErrors.UsersRepoUpdateUserDBQueryFail, err
+		return nil, ipErrors.New(ipErrors.UsersRepoUpdateUserDBQueryFail, err)
+	}
+
+	if err := r.createAuditEntry(ctx, tx, models.AuditActionUpdate, currentUser, returnedUser, info); err != nil {
+		return nil, err
+	}
+
+	return returnedUser, nil
+}
+
+// checkStatusTransition checks the user is allowed to change to the status
+// by the repo's StatusTransitions.
+//
+// Returns an errors.Error with UsersRepoIllegalStatusTransition if it is not.
+func (r ServiceRepo) checkStatusTransition(currentUser *models.User, status string) error {
+	if r.StatusTransitions.Allows(currentUser.UserStatus, status) {
+		return nil
 	}
 
-	return returnedUser, 0, nil
+	return ipErrors.New(ipErrors.UsersRepoIllegalStatusTransition, fmt.Errorf(
+		"user %d cannot change status from %q to %q",
+		currentUser.UserId,
+		currentUser.UserStatus,
+		status))
 }
 
 // DeleteUser remove user entry in the DB with the associated id.
 //
+// The removal is recorded in the audit log within the same transaction.
 // Returns true if the user was successfully removed.
-// Returns an error and error code if creating the SQL query or querying DB fails.
-// If error is returned, an error code associated with it will be returned as well.
-func (r ServiceRepo) DeleteUser(userId int) (bool, ipErrors.ErrorCode, error) {
-	res, err := r.psql.Delete(constants.UsersTableName).
+// Returns an errors.Error with the error code if creating the SQL query or querying DB fails.
+func (r ServiceRepo) DeleteUser(ctx context.Context, userId int, info models.AuditInfo) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, ipErrors.New(ipErrors.UsersRepoDeleteUserDBQueryFail, err)
+	}
+	defer rollback(ctx, "DeleteUser", tx)
+
+	deletedUser := new(models.User)
+
+	// squirrel's DeleteBuilder has no QueryRow, so we run the query ourselves
+	query, args, err := r.psql.Delete(constants.UsersTableName).
 		Where("user_id = ?", userId).
-		Suffix("RETURNING user_id").
-		RunWith(r.DB).
-		Exec()
+		Suffix("RETURNING *").
+		ToSql()
+
+	if err != nil {
+		return false, ipErrors.New(ipErrors.UsersRepoDeleteUserDBQueryFail, err)
+	}
+
+	err = traced(tx).QueryRowContext(ctx, query, args...).
+		Scan(&deletedUser.UserId,
+			&deletedUser.Username,
+			&deletedUser.Firstname,
+			&deletedUser.Lastname,
+			&deletedUser.Email,
+			&deletedUser.UserStatus,
+			&deletedUser.Department)
+
+	// No user with the id existed, so nothing was deleted
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
 
 	if err != nil {
-		return false, ipErrors.UsersRepoDeleteUserDBQueryFail, err
+		return false, ipErrors.New(ipErrors.UsersRepoDeleteUserDBQueryFail, err)
+	}
+
+	if err := r.createAuditEntry(ctx, tx, models.AuditActionDelete, deletedUser, nil, info); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, ipErrors.New(ipErrors.UsersRepoDeleteUserDBQueryFail, err)
 	}
 
-	rows, _ := res.RowsAffected()
-	return rows > 0, 0, nil
+	return true, nil
 }
 
 // checkUserDBError checks to see if error from the DB is specific
@@ -1,6 +1,8 @@
 package database_test
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 
@@ -23,6 +25,11 @@ var _ = Describe("Users", Ordered, func() {
 	var closeFunc func()
 	var testUser models.User
 
+	audit := models.AuditInfo{Actor: "admin", RequestId: "request-1"}
+	auditQuery := fmt.Sprintf(
+		"INSERT INTO %s (actor,action,user_id,changes,request_id,reason) VALUES ($1,$2,$3,$4,$5,$6)",
+		constants.AuditLogTableName)
+
 	BeforeAll(func() {
 		repo, dbMock, closeFunc = mocks.CreateRepoWithMockedDBDriver()
 	})
@@ -31,6 +38,10 @@ var _ = Describe("Users", Ordered, func() {
 		testUser = constants.TestUsers[0]
 	})
 
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
 	AfterAll(func() {
 		closeFunc()
 	})
@@ -45,9 +56,8 @@ var _ = Describe("Users", Ordered, func() {
 			dbMock.ExpectQuery("SELECT * FROM integra_partners.users").
 				WillReturnRows(rows)
 
-			users, errCode, err := repo.GetAllUsers()
+			users, err := repo.GetAllUsers(context.Background())
 
-			Expect(errCode).To(Equal(ipErrors.ErrorCode(0)))
 			Expect(err).To(BeNil())
 			Expect(len(users)).To(Equal(2))
 			// Spot check some values
@@ -61,10 +71,10 @@ var _ = Describe("Users", Ordered, func() {
 			dbMock.ExpectQuery("SELECT * FROM integra_partners.users").
 				WillReturnError(expectedErr)
 
-			users, errCode, err := repo.GetAllUsers()
+			users, err := repo.GetAllUsers(context.Background())
 
-			Expect(errCode).To(Equal(ipErrors.UsersRepoGetAllUsersDBQueryFail))
-			Expect(err).To(Equal(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoGetAllUsersDBQueryFail))
+			Expect(err).To(MatchError(expectedErr))
 			Expect(len(users)).To(Equal(0))
 		})
 	})
@@ -78,13 +88,17 @@ var _ = Describe("Users", Ordered, func() {
 			rows := sqlmock.NewRows([]string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}).
 				AddRow("1", "testUser", "test", "user", "test@user.com", "A", "sales")
 
+			dbMock.ExpectBegin()
 			dbMock.ExpectQuery(insertQuery).
 				WillReturnRows(rows)
+			dbMock.ExpectExec(auditQuery).
+				WithArgs(audit.Actor, models.AuditActionCreate, 1, sqlmock.AnyArg(), audit.RequestId, nil).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectCommit()
 
-			user, errCode, err := repo.CreateUser(testUser)
+			user, err := repo.CreateUser(context.Background(), testUser, audit)
 
 			Expect(err).To(BeNil())
-			Expect(errCode).To(Equal(ipErrors.ErrorCode(0)))
 			Expect(user).To(Equal(&testUser))
 		})
 
@@ -94,26 +108,30 @@ var _ = Describe("Users", Ordered, func() {
 				Message: "duplicate key value violates unique constraint \"users_user_name_idx\"",
 			}
 
+			dbMock.ExpectBegin()
 			dbMock.ExpectQuery(insertQuery).
 				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
 
-			user, errCode, err := repo.CreateUser(testUser)
+			user, err := repo.CreateUser(context.Background(), testUser, audit)
 
-			Expect(err).To(Equal(expectedErr))
-			Expect(errCode).To(Equal(ipErrors.UsersRepoUserDuplicateUsername))
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoUserDuplicateUsername))
 			Expect(user).To(BeNil())
 		})
 
 		It("should fail due to duplicate email", func() {
 			expectedErr := &pgconn.PgError{Code: pgerrcode.UniqueViolation, Message: "duplicate key value violates unique constraint \"users_email_idx\""}
 
+			dbMock.ExpectBegin()
 			dbMock.ExpectQuery(insertQuery).
 				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
 
-			user, errCode, err := repo.CreateUser(testUser)
+			user, err := repo.CreateUser(context.Background(), testUser, audit)
 
-			Expect(err).To(Equal(expectedErr))
-			Expect(errCode).To(Equal(ipErrors.UsersRepoUserDuplicateEmail))
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoUserDuplicateEmail))
 			Expect(user).To(BeNil())
 		})
 
@@ -123,46 +141,92 @@ var _ = Describe("Users", Ordered, func() {
 				Message: "invalid input value for enum integra_partners.user_status: \"e\" (SQLSTATE 22P02)",
 			}
 
+			dbMock.ExpectBegin()
 			dbMock.ExpectQuery(insertQuery).
 				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
 
-			user, errCode, err := repo.CreateUser(testUser)
+			user, err := repo.CreateUser(context.Background(), testUser, audit)
 
-			Expect(err).To(Equal(expectedErr))
-			Expect(errCode).To(Equal(ipErrors.UsersRepoUserInvalidUserStatus))
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoUserInvalidUserStatus))
 			Expect(user).To(BeNil())
 		})
 
 		It("should fail due to DB error", func() {
 			expectedErr := errors.New("DB encountered and error!")
 
+			dbMock.ExpectBegin()
 			dbMock.ExpectQuery(insertQuery).
 				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
 
-			user, errCode, err := repo.CreateUser(testUser)
+			user, err := repo.CreateUser(context.Background(), testUser, audit)
 
-			Expect(err).To(Equal(expectedErr))
-			Expect(errCode).To(Equal(ipErrors.UsersRepoCreateUserDBQueryFail))
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoCreateUserDBQueryFail))
+			Expect(user).To(BeNil())
+		})
+
+		It("should rollback the user when the audit entry fails", func() {
+			expectedErr := errors.New("DB encountered and error!")
+			rows := sqlmock.NewRows([]string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}).
+				AddRow("1", "testUser", "test", "user", "test@user.com", "A", "sales")
+
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(insertQuery).
+				WillReturnRows(rows)
+			dbMock.ExpectExec(auditQuery).
+				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
+
+			user, err := repo.CreateUser(context.Background(), testUser, audit)
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.AuditRepoCreateEntryDBQueryFail))
 			Expect(user).To(BeNil())
 		})
 	})
 
 	Describe("UpdateUser", func() {
+		selectQuery := fmt.Sprintf(
+			"SELECT * FROM %s WHERE user_id = $1 FOR UPDATE",
+			constants.UsersTableName)
 		fullUpdateQuery := fmt.Sprintf(
 			"UPDATE %s SET department = $1, email = $2, first_name = $3, last_name = $4, user_name = $5, user_status = $6 WHERE user_id = $7 RETURNING *",
 			constants.UsersTableName)
 
+		var currentRows *sqlmock.Rows
+
+		BeforeEach(func() {
+			currentRows = sqlmock.NewRows([]string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}).
+				AddRow("1", "testUser", "test", "user", "test@user.com", "I", "sales")
+		})
+
 		It("should successfully update a user", func() {
 			rows := sqlmock.NewRows([]string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}).
 				AddRow("1", "testUser", "test", "user", "test@user.com", "A", "sales")
 
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(1).
+				WillReturnRows(currentRows)
 			dbMock.ExpectQuery(fullUpdateQuery).
 				WillReturnRows(rows)
+			dbMock.ExpectExec(auditQuery).
+				WithArgs(
+					audit.Actor,
+					models.AuditActionUpdate,
+					1,
+					`{"user_status":{"before":"I","after":"A"}}`,
+					audit.RequestId,
+					nil).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectCommit()
 
-			user, errCode, err := repo.UpdateUser(testUser)
+			user, err := repo.UpdateUser(context.Background(), testUser, audit)
 
 			Expect(err).To(BeNil())
-			Expect(errCode).To(Equal(ipErrors.ErrorCode(0)))
 			Expect(user).To(Equal(&testUser))
 		})
 
@@ -175,45 +239,140 @@ var _ = Describe("Users", Ordered, func() {
 				"UPDATE %s SET department = $1, user_name = $2 WHERE user_id = $3 RETURNING *",
 				constants.UsersTableName)
 
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(1).
+				WillReturnRows(currentRows)
 			dbMock.ExpectQuery(partialUpdateQuery).
 				WillReturnRows(rows)
+			dbMock.ExpectExec(auditQuery).
+				WithArgs(audit.Actor, models.AuditActionUpdate, 1, sqlmock.AnyArg(), audit.RequestId, nil).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectCommit()
 
-			user, errCode, err := repo.UpdateUser(updateUser)
+			user, err := repo.UpdateUser(context.Background(), updateUser, audit)
 
 			testUser.Username = updateUser.Username
 			testUser.Department = updateUser.Department
 
 			Expect(err).To(BeNil())
-			Expect(errCode).To(Equal(ipErrors.ErrorCode(0)))
 			Expect(user).To(Equal(&testUser))
 		})
 
+		It("should record the reason for the change", func() {
+			rows := sqlmock.NewRows([]string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}).
+				AddRow("1", "testUser", "test", "user", "test@user.com", "A", "sales")
+			reasonAudit := audit
+			reasonAudit.Reason = "returned from leave"
+
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(1).
+				WillReturnRows(currentRows)
+			dbMock.ExpectQuery(fullUpdateQuery).
+				WillReturnRows(rows)
+			dbMock.ExpectExec(auditQuery).
+				WithArgs(audit.Actor, models.AuditActionUpdate, 1, sqlmock.AnyArg(), audit.RequestId, "returned from leave").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectCommit()
+
+			user, err := repo.UpdateUser(context.Background(), testUser, reasonAudit)
+
+			Expect(err).To(BeNil())
+			Expect(user).To(Equal(&testUser))
+		})
+
+		It("should fail when the status transition is not allowed", func() {
+			terminatedRows := sqlmock.NewRows([]string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}).
+				AddRow("1", "testUser", "test", "user", "test@user.com", "T", "sales")
+
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(1).
+				WillReturnRows(terminatedRows)
+			dbMock.ExpectRollback()
+
+			user, err := repo.UpdateUser(context.Background(), testUser, audit)
+
+			Expect(err).ToNot(BeNil())
+			Expect(err).To(MatchError(ipErrors.UsersRepoIllegalStatusTransition))
+			Expect(user).To(BeNil())
+		})
+
+		It("should allow updating other fields of a terminated user", func() {
+			terminatedRows := sqlmock.NewRows([]string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}).
+				AddRow("1", "testUser", "test", "user", "test@user.com", "T", "sales")
+			rows := sqlmock.NewRows([]string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}).
+				AddRow("1", "testUser", "test", "user", "test@user.com", "T", "warehouse")
+			partialUpdateQuery := fmt.Sprintf(
+				"UPDATE %s SET department = $1 WHERE user_id = $2 RETURNING *",
+				constants.UsersTableName)
+
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(1).
+				WillReturnRows(terminatedRows)
+			dbMock.ExpectQuery(partialUpdateQuery).
+				WillReturnRows(rows)
+			dbMock.ExpectExec(auditQuery).
+				WithArgs(audit.Actor, models.AuditActionUpdate, 1, sqlmock.AnyArg(), audit.RequestId, nil).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectCommit()
+
+			user, err := repo.UpdateUser(context.Background(), models.User{UserId: 1, Department: "warehouse"}, audit)
+
+			Expect(err).To(BeNil())
+			Expect(user.UserStatus).To(Equal("T"))
+		})
+
+		It("should fail when the user does not exist", func() {
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(selectQuery).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+			dbMock.ExpectRollback()
+
+			user, err := repo.UpdateUser(context.Background(), testUser, audit)
+
+			Expect(err).To(MatchError(sql.ErrNoRows))
+			Expect(err).To(MatchError(ipErrors.UsersRepoUpdateUserDBQueryFail))
+			Expect(user).To(BeNil())
+		})
+
 		It("should fail due to duplicate username", func() {
 			expectedErr := &pgconn.PgError{
 				Code:    pgerrcode.UniqueViolation,
 				Message: "duplicate key value violates unique constraint \"users_user_name_idx\"",
 			}
 
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(selectQuery).
+				WillReturnRows(currentRows)
 			dbMock.ExpectQuery(fullUpdateQuery).
 				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
 
-			user, errCode, err := repo.UpdateUser(testUser)
+			user, err := repo.UpdateUser(context.Background(), testUser, audit)
 
-			Expect(err).To(Equal(expectedErr))
-			Expect(errCode).To(Equal(ipErrors.UsersRepoUserDuplicateUsername))
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoUserDuplicateUsername))
 			Expect(user).To(BeNil())
 		})
 
 		It("should fail due to duplicate email", func() {
 			expectedErr := &pgconn.PgError{Code: pgerrcode.UniqueViolation, Message: "duplicate key value violates unique constraint \"users_email_idx\""}
 
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(selectQuery).
+				WillReturnRows(currentRows)
 			dbMock.ExpectQuery(fullUpdateQuery).
 				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
 
-			user, errCode, err := repo.UpdateUser(testUser)
+			user, err := repo.UpdateUser(context.Background(), testUser, audit)
 
-			Expect(err).To(Equal(expectedErr))
-			Expect(errCode).To(Equal(ipErrors.UsersRepoUserDuplicateEmail))
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoUserDuplicateEmail))
 			Expect(user).To(BeNil())
 		})
 
@@ -223,68 +382,86 @@ var _ = Describe("Users", Ordered, func() {
 				Message: "invalid input value for enum integra_partners.user_status: \"e\" (SQLSTATE 22P02)",
 			}
 
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(selectQuery).
+				WillReturnRows(currentRows)
 			dbMock.ExpectQuery(fullUpdateQuery).
 				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
 
-			user, errCode, err := repo.UpdateUser(testUser)
+			user, err := repo.UpdateUser(context.Background(), testUser, audit)
 
-			Expect(err).To(Equal(expectedErr))
-			Expect(errCode).To(Equal(ipErrors.UsersRepoUserInvalidUserStatus))
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoUserInvalidUserStatus))
 			Expect(user).To(BeNil())
 		})
 
 		It("should fail due to DB error", func() {
 			expectedErr := errors.New("DB encountered and error!")
 
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(selectQuery).
+				WillReturnRows(currentRows)
 			dbMock.ExpectQuery(fullUpdateQuery).
 				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
 
-			user, errCode, err := repo.UpdateUser(testUser)
+			user, err := repo.UpdateUser(context.Background(), testUser, audit)
 
-			Expect(err).To(Equal(expectedErr))
-			Expect(errCode).To(Equal(ipErrors.UsersRepoUpdateUserDBQueryFail))
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoUpdateUserDBQueryFail))
 			Expect(user).To(BeNil())
 		})
 	})
 
 	Describe("DeleteUser", func() {
-		deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1 RETURNING user_id", constants.UsersTableName)
+		deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1 RETURNING *", constants.UsersTableName)
 
 		It("should successfully delete user", func() {
-			dbMock.ExpectExec(deleteQuery).
+			rows := sqlmock.NewRows([]string{"user_id", "user_name", "first_name", "last_name", "email", "user_status", "department"}).
+				AddRow("1", "testUser", "test", "user", "test@user.com", "A", "sales")
+
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(deleteQuery).
 				WithArgs(1).
+				WillReturnRows(rows)
+			dbMock.ExpectExec(auditQuery).
+				WithArgs(audit.Actor, models.AuditActionDelete, 1, sqlmock.AnyArg(), audit.RequestId, nil).
 				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectCommit()
 
-			deleted, errCode, err := repo.DeleteUser(testUser.UserId)
+			deleted, err := repo.DeleteUser(context.Background(), testUser.UserId, audit)
 
 			Expect(err).To(BeNil())
-			Expect(errCode).To(Equal(ipErrors.ErrorCode(0)))
 			Expect(deleted).To(Equal(true))
 		})
 
 		It("should return nil user if they do not exist", func() {
-			dbMock.ExpectExec(deleteQuery).
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(deleteQuery).
 				WithArgs(1).
-				WillReturnResult(sqlmock.NewResult(1, 0))
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+			dbMock.ExpectRollback()
 
-			deleted, errCode, err := repo.DeleteUser(testUser.UserId)
+			deleted, err := repo.DeleteUser(context.Background(), testUser.UserId, audit)
 
 			Expect(err).To(BeNil())
-			Expect(errCode).To(Equal(ipErrors.ErrorCode(0)))
 			Expect(deleted).To(Equal(false))
 		})
 
 		It("should return error if DB throws error", func() {
-			err := errors.New("DB threw an error!")
+			expectedErr := errors.New("DB threw an error!")
 
-			dbMock.ExpectExec(deleteQuery).
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(deleteQuery).
 				WithArgs(1).
-				WillReturnError(err)
+				WillReturnError(expectedErr)
+			dbMock.ExpectRollback()
 
-			deleted, errCode, err := repo.DeleteUser(testUser.UserId)
+			deleted, err := repo.DeleteUser(context.Background(), testUser.UserId, audit)
 
-			Expect(err).To(Equal(err))
-			Expect(errCode).To(Equal(ipErrors.UsersRepoDeleteUserDBQueryFail))
+			Expect(err).To(MatchError(expectedErr))
+			Expect(err).To(MatchError(ipErrors.UsersRepoDeleteUserDBQueryFail))
 			Expect(deleted).To(Equal(false))
 		})
 	})
@@ -0,0 +1,129 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+//go:generate go run ../../cmd/errcatalog -out ../../docs
+
+// CatalogEntry describes an error code the API can respond with.
+type CatalogEntry struct {
+	Code    ErrorCode `json:"code"`
+	Name    string    `json:"name"`
+	Message string    `json:"message"`
+	Status  int       `json:"status"`
+}
+
+// mappedNames are the symbolic names of the codes, matching their
+// identifiers in this package.
+var mappedNames = map[ErrorCode]string{
+	DBRepoFailedToInitialize:                  "DBRepoFailedToInitialize",
+	UsersRepoGetAllUsersDBQueryFail:           "UsersRepoGetAllUsersDBQueryFail",
+	UsersRepoCreateUserDBQueryFail:            "UsersRepoCreateUserDBQueryFail",
+	UsersRepoUserDuplicateUsername:            "UsersRepoUserDuplicateUsername",
+	UsersRepoUserDuplicateEmail:               "UsersRepoUserDuplicateEmail",
+	UsersRepoUserInvalidUserStatus:            "UsersRepoUserInvalidUserStatus",
+	UsersRepoUpdateUserDBQueryFail:            "UsersRepoUpdateUserDBQueryFail",
+	UsersRepoUpdateInvalidUserId:              "UsersRepoUpdateInvalidUserId",
+	UsersRepoDeleteUserDBQueryFail:            "UsersRepoDeleteUserDBQueryFail",
+	UsersControllerUserFailedToBindBody:       "UsersControllerUserFailedToBindBody",
+	UsersControllerInvalidUserIdParam:         "UsersControllerInvalidUserIdParam",
+	PasswordResetRepoCreateTokenDBQueryFail:   "PasswordResetRepoCreateTokenDBQueryFail",
+	PasswordResetRepoResetPasswordDBQueryFail: "PasswordResetRepoResetPasswordDBQueryFail",
+	PasswordResetControllerFailedToBindBody:   "PasswordResetControllerFailedToBindBody",
+	PasswordResetControllerInvalidEmail:       "PasswordResetControllerInvalidEmail",
+	PasswordResetControllerInvalidPassword:    "PasswordResetControllerInvalidPassword",
+	PasswordResetControllerInvalidToken:       "PasswordResetControllerInvalidToken",
+	PasswordResetControllerRateLimited:        "PasswordResetControllerRateLimited",
+	PasswordResetControllerFailedToHash:       "PasswordResetControllerFailedToHash",
+	AuditRepoCreateEntryDBQueryFail:           "AuditRepoCreateEntryDBQueryFail",
+	AuditRepoGetEntriesDBQueryFail:            "AuditRepoGetEntriesDBQueryFail",
+	AuditControllerInvalidFilter:              "AuditControllerInvalidFilter",
+	UsersRepoGetUserDBQueryFail:               "UsersRepoGetUserDBQueryFail",
+	UsersRepoGetUserHistoryDBQueryFail:        "UsersRepoGetUserHistoryDBQueryFail",
+	UsersControllerInvalidAsOfParam:           "UsersControllerInvalidAsOfParam",
+	StatusChangesRepoCreateDBQueryFail:        "StatusChangesRepoCreateDBQueryFail",
+	StatusChangesRepoGetDBQueryFail:           "StatusChangesRepoGetDBQueryFail",
+	StatusChangesRepoCancelDBQueryFail:        "StatusChangesRepoCancelDBQueryFail",
+	StatusChangesRepoApplyDBQueryFail:         "StatusChangesRepoApplyDBQueryFail",
+	StatusChangesRepoUserNotFound:             "StatusChangesRepoUserNotFound",
+	StatusChangesControllerFailedToBindBody:   "StatusChangesControllerFailedToBindBody",
+	StatusChangesControllerInvalidChangeId:    "StatusChangesControllerInvalidChangeId",
+	StatusChangesControllerInvalidEffectiveAt: "StatusChangesControllerInvalidEffectiveAt",
+	UsersRepoIllegalStatusTransition:          "UsersRepoIllegalStatusTransition",
+	UsersControllerUserValidationFailed:       "UsersControllerUserValidationFailed",
+	DBRepoPingFailed:                          "DBRepoPingFailed",
+	DBRepoSchemaVersionQueryFail:              "DBRepoSchemaVersionQueryFail",
+	DBRepoInvalidConfig:                       "DBRepoInvalidConfig",
+	FieldRequired:                             "FieldRequired",
+	FieldTooLong:                              "FieldTooLong",
+	FieldInvalidEmail:                         "FieldInvalidEmail",
+	FieldInvalidValue:                         "FieldInvalidValue",
+	FieldInvalidType:                          "FieldInvalidType",
+	IdentityClientNotAllowed:                  "IdentityClientNotAllowed",
+	AuditControllerInvalidActor:               "AuditControllerInvalidActor",
+}
+
+// Name returns the symbolic name of the code
+//
+// Returns an empty string if it does not exist
+func (c ErrorCode) Name() string {
+	return mappedNames[c]
+}
+
+// Catalog returns an entry for every code in mappedErrors, ordered by code.
+func Catalog() []CatalogEntry {
+	entries := make([]CatalogEntry, 0, len(mappedErrors))
+	for code, message := range mappedErrors {
+		entries = append(entries, CatalogEntry{
+			Code:    code,
+			Name:    code.Name(),
+			Message: message,
+			Status:  GetHttpStatus(code),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Code < entries[j].Code
+	})
+
+	return entries
+}
+
+// WriteCatalogJSON writes the Catalog as an indented JSON array.
+func WriteCatalogJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(Catalog())
+}
+
+// WriteCatalogMarkdown writes the Catalog as a markdown table.
+func WriteCatalogMarkdown(w io.Writer) error {
+	_, err := fmt.Fprint(w,
+		"# Error Codes\n\n"+
+			"Generated from internal/errors by `make errors-gen`, do not edit.\n\n"+
+			"| Code | Name | HTTP Status | Message |\n"+
+			"| ---- | ---- | ----------- | ------- |\n")
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range Catalog() {
+		_, err := fmt.Fprintf(w, "| %d | %s | %d %s | %s |\n",
+			entry.Code,
+			entry.Name,
+			entry.Status,
+			http.StatusText(entry.Status),
+			entry.Message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package errors_test
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+)
+
+// declaredCodes parses errors.go and returns the ErrorCode constant
+// identifiers in the order they are declared.
+func declaredCodes() []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "errors.go", nil, 0)
+	Expect(err).To(BeNil())
+
+	names := []string{}
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				names = append(names, name.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+var _ = Describe("Catalog", func() {
+
+	It("should have an entry for every code in order", func() {
+		names := declaredCodes()
+		catalog := errors.Catalog()
+
+		Expect(len(catalog)).To(Equal(len(names)))
+		for i, entry := range catalog {
+			Expect(entry.Code).To(Equal(errors.DBRepoFailedToInitialize + errors.ErrorCode(i)))
+			Expect(entry.Name).To(Equal(names[i]))
+			Expect(entry.Message).To(Equal(errors.GetErrorMessage(entry.Code, errors.DefaultLocale)))
+			Expect(entry.Status).To(Equal(errors.GetHttpStatus(entry.Code)))
+		}
+	})
+
+	It("should match the generated docs", func() {
+		var jsonCatalog, markdownCatalog bytes.Buffer
+		Expect(errors.WriteCatalogJSON(&jsonCatalog)).To(Succeed())
+		Expect(errors.WriteCatalogMarkdown(&markdownCatalog)).To(Succeed())
+
+		jsonDoc, err := os.ReadFile("../../docs/errors.json")
+		Expect(err).To(BeNil())
+		markdownDoc, err := os.ReadFile("../../docs/errors.md")
+		Expect(err).To(BeNil())
+
+		Expect(string(jsonDoc)).To(Equal(jsonCatalog.String()), "run make errors-gen")
+		Expect(string(markdownDoc)).To(Equal(markdownCatalog.String()), "run make errors-gen")
+	})
+})
@@ -0,0 +1,173 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Error is returned when an operation fails with a known ErrorCode.
+//
+// It carries the HTTP status and client safe message for the code along
+// with the underlying cause, which is only ever logged.
+type Error struct {
+	Code    ErrorCode
+	Status  int
+	Message string
+	// Detail is client safe information about this occurrence that is
+	// appended to the message
+	Detail string
+	// Fields that failed validation, if any
+	Fields []FieldError
+	Err    error
+}
+
+// New creates an Error for the code, wrapping the cause.
+//
+// The status and message are the ones mapped to the code, with the
+// message in the DefaultLocale.
+func New(code ErrorCode, cause error) *Error {
+	return &Error{
+		Code:    code,
+		Status:  GetHttpStatus(code),
+		Message: GetErrorMessage(code, DefaultLocale),
+		Err:     cause,
+	}
+}
+
+// WithDetail returns a copy of the Error with the detail set.
+func (e *Error) WithDetail(detail string) *Error {
+	err := *e
+	err.Detail = detail
+	return &err
+}
+
+// LocalizedMessage returns the client message for the code in the locale,
+// followed by the detail if there is one.
+func (e *Error) LocalizedMessage(locale Locale) string {
+	return e.withDetail(GetErrorMessage(e.Code, locale))
+}
+
+// withDetail appends the detail to the message if there is one.
+func (e *Error) withDetail(message string) string {
+	if e.Detail == "" {
+		return message
+	}
+
+	return fmt.Sprintf("%s. %s", message, e.Detail)
+}
+
+// WithFields returns a copy of the Error with the fields that failed
+// validation.
+func (e *Error) WithFields(fields []FieldError) *Error {
+	err := *e
+	err.Fields = fields
+	return &err
+}
+
+// LocalizedFields returns the fields that failed validation with their
+// messages in the locale.
+func (e *Error) LocalizedFields(locale Locale) []FieldError {
+	if e.Fields == nil {
+		return nil
+	}
+
+	fields := make([]FieldError, len(e.Fields))
+	for i, field := range e.Fields {
+		fields[i] = field.Localized(locale)
+	}
+
+	return fields
+}
+
+// Error formats the error with its code, message and cause.
+//
+// Format will be "Code: {code}. {message}. Error: {cause}".
+// The cause will be omitted if it is nil.
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("Code: %d. %s", e.Code, e.withDetail(e.Message))
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s. Error: %s", msg, e.Err.Error())
+	}
+
+	return msg
+}
+
+// Unwrap returns the cause so it can be matched with errors.Is and errors.As
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is this Error's code or another Error with
+// the same code.
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case ErrorCode:
+		return e.Code == t
+	case *Error:
+		return e.Code == t.Code
+	}
+
+	return false
+}
+
+// Error returns the DefaultLocale message mapped to the code, allowing
+// codes to be matched against an Error with errors.Is.
+func (c ErrorCode) Error() string {
+	return GetErrorMessage(c, DefaultLocale)
+}
+
+// mappedStatuses are the HTTP statuses for codes caused by the client.
+// Any code not mapped is a server error.
+var mappedStatuses = map[ErrorCode]int{
+	// Users repo errors
+	UsersRepoUserDuplicateUsername:   http.StatusConflict,
+	UsersRepoUserDuplicateEmail:      http.StatusConflict,
+	UsersRepoUserInvalidUserStatus:   http.StatusBadRequest,
+	UsersRepoUpdateInvalidUserId:     http.StatusBadRequest,
+	UsersRepoIllegalStatusTransition: http.StatusConflict,
+
+	// Users controller errors
+	UsersControllerUserFailedToBindBody: http.StatusBadRequest,
+	UsersControllerInvalidUserIdParam:   http.StatusBadRequest,
+	UsersControllerInvalidAsOfParam:     http.StatusBadRequest,
+	UsersControllerUserValidationFailed: http.StatusUnprocessableEntity,
+
+	// Password reset controller errors
+	PasswordResetControllerFailedToBindBody: http.StatusBadRequest,
+	PasswordResetControllerInvalidEmail:     http.StatusBadRequest,
+	PasswordResetControllerInvalidPassword:  http.StatusBadRequest,
+	PasswordResetControllerInvalidToken:     http.StatusBadRequest,
+	PasswordResetControllerRateLimited:      http.StatusTooManyRequests,
+
+	// Audit controller errors
+	AuditControllerInvalidFilter: http.StatusBadRequest,
+	AuditControllerInvalidActor:  http.StatusUnprocessableEntity,
+
+	// Scheduled status change errors
+	StatusChangesRepoUserNotFound:             http.StatusNotFound,
+	StatusChangesControllerFailedToBindBody:   http.StatusBadRequest,
+	StatusChangesControllerInvalidChangeId:    http.StatusBadRequest,
+	StatusChangesControllerInvalidEffectiveAt: http.StatusBadRequest,
+
+	// Field validation errors, which are reported within another error
+	FieldRequired:     http.StatusUnprocessableEntity,
+	FieldTooLong:      http.StatusUnprocessableEntity,
+	FieldInvalidEmail: http.StatusUnprocessableEntity,
+	FieldInvalidValue: http.StatusUnprocessableEntity,
+	FieldInvalidType:  http.StatusBadRequest,
+
+	// Authorization errors
+	IdentityClientNotAllowed: http.StatusForbidden,
+}
+
+// GetHttpStatus returns the HTTP status to respond with for the code
+//
+// Returns http.StatusInternalServerError (500) if the code does not have
+// a specific status.
+func GetHttpStatus(code ErrorCode) int {
+	if status, ok := mappedStatuses[code]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
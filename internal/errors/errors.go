@@ -22,6 +22,55 @@ const (
 
 	UsersControllerUserFailedToBindBody
 	UsersControllerInvalidUserIdParam
+
+	PasswordResetRepoCreateTokenDBQueryFail
+	PasswordResetRepoResetPasswordDBQueryFail
+
+	PasswordResetControllerFailedToBindBody
+	PasswordResetControllerInvalidEmail
+	PasswordResetControllerInvalidPassword
+	PasswordResetControllerInvalidToken
+	PasswordResetControllerRateLimited
+	PasswordResetControllerFailedToHash
+
+	AuditRepoCreateEntryDBQueryFail
+	AuditRepoGetEntriesDBQueryFail
+
+	AuditControllerInvalidFilter
+
+	UsersRepoGetUserDBQueryFail
+	UsersRepoGetUserHistoryDBQueryFail
+
+	UsersControllerInvalidAsOfParam
+
+	StatusChangesRepoCreateDBQueryFail
+	StatusChangesRepoGetDBQueryFail
+	StatusChangesRepoCancelDBQueryFail
+	StatusChangesRepoApplyDBQueryFail
+	StatusChangesRepoUserNotFound
+
+	StatusChangesControllerFailedToBindBody
+	StatusChangesControllerInvalidChangeId
+	StatusChangesControllerInvalidEffectiveAt
+
+	UsersRepoIllegalStatusTransition
+
+	UsersControllerUserValidationFailed
+
+	DBRepoPingFailed
+	DBRepoSchemaVersionQueryFail
+
+	DBRepoInvalidConfig
+
+	FieldRequired
+	FieldTooLong
+	FieldInvalidEmail
+	FieldInvalidValue
+	FieldInvalidType
+
+	IdentityClientNotAllowed
+
+	AuditControllerInvalidActor
 )
 
 var mappedErrors = map[ErrorCode]string{
@@ -29,28 +78,94 @@ var mappedErrors = map[ErrorCode]string{
 	DBRepoFailedToInitialize: constants.ErrDBRepoFailedToInitializeMessage,
 
 	// User repo errors
-	UsersRepoGetAllUsersDBQueryFail: constants.ErrUsersRepoGetAllUsersDBQueryFailMessage,
-	UsersRepoCreateUserDBQueryFail:  constants.ErrUsersRepoCreateUserDBQueryFailMessage,
-	UsersRepoUserDuplicateUsername:  constants.ErrUsersRepoUserDuplicateUserNameMessage,
-	UsersRepoUserDuplicateEmail:     constants.ErrUsersRepoUserDuplicateEmailMessage,
-	UsersRepoUserInvalidUserStatus:  constants.ErrUsersRepoInvalidUserStatusMessage,
-	UsersRepoUpdateUserDBQueryFail:  constants.ErrUsersRepoUpdateUserDBQueryFailMessage,
-	UsersRepoUpdateInvalidUserId:    constants.ErrUsersRepoUpdateInvalidUserIdMessage,
-	UsersRepoDeleteUserDBQueryFail:  constants.ErrUsersRepoDeleteUserDBQueryFailMessage,
+	UsersRepoGetAllUsersDBQueryFail:    constants.ErrUsersRepoGetAllUsersDBQueryFailMessage,
+	UsersRepoCreateUserDBQueryFail:     constants.ErrUsersRepoCreateUserDBQueryFailMessage,
+	UsersRepoUserDuplicateUsername:     constants.ErrUsersRepoUserDuplicateUserNameMessage,
+	UsersRepoUserDuplicateEmail:        constants.ErrUsersRepoUserDuplicateEmailMessage,
+	UsersRepoUserInvalidUserStatus:     constants.ErrUsersRepoInvalidUserStatusMessage,
+	UsersRepoUpdateUserDBQueryFail:     constants.ErrUsersRepoUpdateUserDBQueryFailMessage,
+	UsersRepoUpdateInvalidUserId:       constants.ErrUsersRepoUpdateInvalidUserIdMessage,
+	UsersRepoDeleteUserDBQueryFail:     constants.ErrUsersRepoDeleteUserDBQueryFailMessage,
+	UsersRepoGetUserDBQueryFail:        constants.ErrUsersRepoGetUserDBQueryFailMessage,
+	UsersRepoGetUserHistoryDBQueryFail: constants.ErrUsersRepoGetUserHistoryDBQueryFailMessage,
 
 	// User controller errors
 	UsersControllerUserFailedToBindBody: constants.ErrUsersControllerUserFailedToBindBodyFailMessage,
 	UsersControllerInvalidUserIdParam:   constants.ErrUsersControllerInvalidUserIdParamMessage,
+	UsersControllerInvalidAsOfParam:     constants.ErrUsersControllerInvalidAsOfParamMessage,
+
+	// Password reset repo errors
+	PasswordResetRepoCreateTokenDBQueryFail:   constants.ErrPasswordResetRepoCreateTokenDBQueryFailMessage,
+	PasswordResetRepoResetPasswordDBQueryFail: constants.ErrPasswordResetRepoResetPasswordDBQueryFailMessage,
+
+	// Password reset controller errors
+	PasswordResetControllerFailedToBindBody: constants.ErrPasswordResetControllerFailedToBindBodyMessage,
+	PasswordResetControllerInvalidEmail:     constants.ErrPasswordResetControllerInvalidEmailMessage,
+	PasswordResetControllerInvalidPassword:  constants.ErrPasswordResetControllerInvalidPasswordMessage,
+	PasswordResetControllerInvalidToken:     constants.ErrPasswordResetControllerInvalidTokenMessage,
+	PasswordResetControllerRateLimited:      constants.ErrPasswordResetControllerRateLimitedMessage,
+	PasswordResetControllerFailedToHash:     constants.ErrPasswordResetControllerFailedToHashMessage,
+
+	// Audit repo errors
+	AuditRepoCreateEntryDBQueryFail: constants.ErrAuditRepoCreateEntryDBQueryFailMessage,
+	AuditRepoGetEntriesDBQueryFail:  constants.ErrAuditRepoGetEntriesDBQueryFailMessage,
+
+	// Audit controller errors
+	AuditControllerInvalidFilter: constants.ErrAuditControllerInvalidFilterMessage,
+
+	// Scheduled status change repo errors
+	StatusChangesRepoCreateDBQueryFail: constants.ErrStatusChangesRepoCreateDBQueryFailMessage,
+	StatusChangesRepoGetDBQueryFail:    constants.ErrStatusChangesRepoGetDBQueryFailMessage,
+	StatusChangesRepoCancelDBQueryFail: constants.ErrStatusChangesRepoCancelDBQueryFailMessage,
+	StatusChangesRepoApplyDBQueryFail:  constants.ErrStatusChangesRepoApplyDBQueryFailMessage,
+	StatusChangesRepoUserNotFound:      constants.ErrStatusChangesRepoUserNotFoundMessage,
+
+	// Scheduled status change controller errors
+	StatusChangesControllerFailedToBindBody:   constants.ErrStatusChangesControllerFailedToBindBodyMessage,
+	StatusChangesControllerInvalidChangeId:    constants.ErrStatusChangesControllerInvalidChangeIdMessage,
+	StatusChangesControllerInvalidEffectiveAt: constants.ErrStatusChangesControllerInvalidEffectiveAtMessage,
+
+	// User status transition errors
+	UsersRepoIllegalStatusTransition: constants.ErrUsersRepoIllegalStatusTransitionMessage,
+
+	// User validation errors
+	UsersControllerUserValidationFailed: constants.ErrUsersControllerUserValidationFailedMessage,
+
+	// Health check errors
+	DBRepoPingFailed:             constants.ErrDBRepoPingFailedMessage,
+	DBRepoSchemaVersionQueryFail: constants.ErrDBRepoSchemaVersionQueryFailMessage,
+
+	// DB config errors
+	DBRepoInvalidConfig: constants.ErrDBRepoInvalidConfigMessage,
+
+	// Field validation errors
+	FieldRequired:     constants.ErrFieldRequiredMessage,
+	FieldTooLong:      constants.ErrFieldTooLongMessage,
+	FieldInvalidEmail: constants.ErrFieldInvalidEmailMessage,
+	FieldInvalidValue: constants.ErrFieldInvalidValueMessage,
+	FieldInvalidType:  constants.ErrFieldInvalidTypeMessage,
+
+	// Authorization errors
+	IdentityClientNotAllowed: constants.ErrIdentityClientNotAllowedMessage,
+
+	// Audit controller errors
+	AuditControllerInvalidActor: constants.ErrAuditControllerInvalidActorMessage,
 }
 
-// GetErrorMessage returns the error message for the specified code
+// GetErrorMessage returns the error message for the specified code in
+// the locale
 //
+// Falls back to the DefaultLocale message if the locale has no translation.
 // Returns an empty string if it does not exist
-func GetErrorMessage(code ErrorCode) string {
+func GetErrorMessage(code ErrorCode, locale Locale) string {
+	if message, ok := localizedErrors[locale][code]; ok {
+		return message
+	}
+
 	return mappedErrors[code]
 }
 
-// GetErrorCode returns the code for the specified error message
+// GetErrorCode returns the code for the specified DefaultLocale error message
 //
 // Returns 0 if the message was not found
 func GetErrorCode(message string) ErrorCode {
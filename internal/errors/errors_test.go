@@ -1,6 +1,10 @@
 package errors_test
 
 import (
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -13,10 +17,10 @@ var _ = Describe("Errors", func() {
 	Describe("GetErrorMessage", func() {
 		It("should return message associated with the code", func() {
 			Expect(
-				errors.GetErrorMessage(errors.DBRepoFailedToInitialize)).
+				errors.GetErrorMessage(errors.DBRepoFailedToInitialize, errors.DefaultLocale)).
 				To(Equal(constants.ErrDBRepoFailedToInitializeMessage))
 			Expect(
-				errors.GetErrorMessage(errors.UsersControllerInvalidUserIdParam)).
+				errors.GetErrorMessage(errors.UsersControllerInvalidUserIdParam, errors.DefaultLocale)).
 				To(Equal(constants.ErrUsersControllerInvalidUserIdParamMessage))
 		})
 	})
@@ -31,4 +35,88 @@ var _ = Describe("Errors", func() {
 				To(Equal(errors.UsersRepoUpdateInvalidUserId))
 		})
 	})
+
+	Describe("GetHttpStatus", func() {
+		It("should return the status mapped to the code", func() {
+			Expect(errors.GetHttpStatus(errors.UsersRepoUserDuplicateEmail)).To(Equal(http.StatusConflict))
+			Expect(errors.GetHttpStatus(errors.UsersControllerInvalidUserIdParam)).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return InternalServerError for codes without a status", func() {
+			Expect(errors.GetHttpStatus(errors.UsersRepoDeleteUserDBQueryFail)).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("Error", func() {
+		cause := stdErrors.New("DB error occurred!")
+
+		It("should carry the status and message for the code", func() {
+			err := errors.New(errors.UsersRepoUserDuplicateUsername, cause)
+
+			Expect(err.Code).To(Equal(errors.UsersRepoUserDuplicateUsername))
+			Expect(err.Status).To(Equal(http.StatusConflict))
+			Expect(err.Message).To(Equal(constants.ErrUsersRepoUserDuplicateUserNameMessage))
+		})
+
+		It("should format the code, message and cause", func() {
+			code := errors.UsersRepoGetAllUsersDBQueryFail
+
+			Expect(errors.New(code, cause).Error()).To(Equal(fmt.Sprintf(
+				"Code: %d. %s. Error: DB error occurred!", code, errors.GetErrorMessage(code, errors.DefaultLocale))))
+			Expect(errors.New(code, nil).Error()).To(Equal(fmt.Sprintf(
+				"Code: %d. %s", code, errors.GetErrorMessage(code, errors.DefaultLocale))))
+		})
+
+		It("should match its code and cause with errors.Is", func() {
+			err := fmt.Errorf("wrapped: %w", errors.New(errors.UsersRepoGetUserDBQueryFail, cause))
+
+			Expect(stdErrors.Is(err, errors.UsersRepoGetUserDBQueryFail)).To(BeTrue())
+			Expect(stdErrors.Is(err, errors.New(errors.UsersRepoGetUserDBQueryFail, nil))).To(BeTrue())
+			Expect(stdErrors.Is(err, cause)).To(BeTrue())
+			Expect(stdErrors.Is(err, errors.UsersRepoGetAllUsersDBQueryFail)).To(BeFalse())
+		})
+
+		It("should be found with errors.As", func() {
+			err := fmt.Errorf("wrapped: %w", errors.New(errors.UsersRepoGetUserDBQueryFail, cause))
+
+			var appErr *errors.Error
+			Expect(stdErrors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code).To(Equal(errors.UsersRepoGetUserDBQueryFail))
+			Expect(appErr.Unwrap()).To(Equal(cause))
+		})
+
+		It("should append the detail without changing the original", func() {
+			err := errors.New(errors.UsersControllerUserFailedToBindBody, cause)
+			withDetail := err.WithDetail("unexpected EOF")
+
+			Expect(withDetail.LocalizedMessage(errors.LocaleEnglish)).To(Equal("user input body is invalid. unexpected EOF"))
+			Expect(err.LocalizedMessage(errors.LocaleEnglish)).To(Equal("user input body is invalid"))
+		})
+
+		It("should localize the message and keep the detail", func() {
+			err := errors.New(errors.UsersControllerUserFailedToBindBody, cause).WithDetail("unexpected EOF")
+
+			Expect(err.Message).To(Equal(constants.ErrUsersControllerUserFailedToBindBodyFailMessage))
+			Expect(err.LocalizedMessage(errors.LocaleSpanish)).To(Equal(fmt.Sprintf(
+				"%s. unexpected EOF",
+				errors.GetErrorMessage(errors.UsersControllerUserFailedToBindBody, errors.LocaleSpanish))))
+		})
+	})
+
+	Describe("FieldError", func() {
+		It("should format the message for the code with the params", func() {
+			fieldErr := errors.NewFieldError("user_name", errors.FieldTooLong, 50)
+
+			Expect(fieldErr.Message).To(Equal("must be at most 50 characters"))
+		})
+
+		It("should localize the message without changing the original", func() {
+			fieldErr := errors.NewFieldError("user_status", errors.FieldInvalidValue, "I, A, T")
+			localized := fieldErr.Localized(errors.LocaleSpanish)
+
+			Expect(localized.Message).To(Equal("debe ser uno de I, A, T"))
+			Expect(localized.Code).To(Equal(errors.FieldInvalidValue))
+			Expect(fieldErr.Message).To(Equal("must be one of I, A, T"))
+		})
+	})
 })
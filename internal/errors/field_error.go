@@ -0,0 +1,39 @@
+package errors
+
+import "fmt"
+
+// FieldError describes a single field that failed validation
+type FieldError struct {
+	Field   string    `json:"field"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+
+	// Formatted into the message for the code, e.g. the max length
+	params []any
+}
+
+// NewFieldError creates a FieldError for the field with the message for the
+// code in the DefaultLocale, formatted with the params.
+func NewFieldError(field string, code ErrorCode, params ...any) FieldError {
+	fieldErr := FieldError{Field: field, Code: code, params: params}
+	fieldErr.Message = fieldErr.message(DefaultLocale)
+	return fieldErr
+}
+
+// Localized returns a copy of the FieldError with the message for its code
+// in the locale.
+func (f FieldError) Localized(locale Locale) FieldError {
+	f.Message = f.message(locale)
+	return f
+}
+
+// message returns the message for the code in the locale, formatted with
+// the params if there are any
+func (f FieldError) message(locale Locale) string {
+	message := GetErrorMessage(f.Code, locale)
+	if len(f.params) == 0 {
+		return message
+	}
+
+	return fmt.Sprintf(message, f.params...)
+}
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale is a language the error messages are translated to, identified
+// by its ISO 639-1 code.
+type Locale string
+
+const (
+	LocaleEnglish Locale = "en"
+	LocaleSpanish Locale = "es"
+
+	// DefaultLocale is used when the client does not accept any of the
+	// Locales, or a message has no translation for the locale
+	DefaultLocale = LocaleEnglish
+)
+
+// Locales are every locale with a message catalog.
+var Locales = []Locale{LocaleEnglish, LocaleSpanish}
+
+// localizedErrors are the message catalogs for each locale.
+var localizedErrors = map[Locale]map[ErrorCode]string{
+	LocaleEnglish: mappedErrors,
+	LocaleSpanish: mappedErrorsSpanish,
+}
+
+// MatchLocale returns the locale to respond in for the Accept-Language
+// header, e.g. "es-MX,es;q=0.9,en;q=0.8".
+//
+// Languages are matched on their primary subtag in order of quality.
+// Returns DefaultLocale if none of the Locales are accepted.
+func MatchLocale(acceptLanguage string) Locale {
+	type accepted struct {
+		locale  Locale
+		quality float64
+	}
+
+	languages := []accepted{}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		params := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(params[0]))
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+
+		if quality <= 0 {
+			continue
+		}
+
+		primary, _, _ := strings.Cut(tag, "-")
+		languages = append(languages, accepted{locale: Locale(primary), quality: quality})
+	}
+
+	// Stable so languages with the same quality keep the client's order
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	for _, language := range languages {
+		if _, ok := localizedErrors[language.locale]; ok {
+			return language.locale
+		}
+	}
+
+	return DefaultLocale
+}
@@ -0,0 +1,61 @@
+package errors_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+)
+
+var _ = Describe("Locales", func() {
+
+	It("should have a message for every code in every locale", func() {
+		for _, locale := range errors.Locales {
+			for _, entry := range errors.Catalog() {
+				Expect(errors.GetErrorMessage(entry.Code, locale)).NotTo(BeEmpty(),
+					"%s has no %s message", entry.Name, locale)
+			}
+		}
+	})
+
+	It("should translate every code in every other locale", func() {
+		for _, locale := range errors.Locales {
+			if locale == errors.DefaultLocale {
+				continue
+			}
+
+			for _, entry := range errors.Catalog() {
+				Expect(errors.GetErrorMessage(entry.Code, locale)).NotTo(Equal(entry.Message),
+					"%s is not translated to %s", entry.Name, locale)
+			}
+		}
+	})
+
+	Describe("GetErrorMessage", func() {
+		It("should return the message in the locale", func() {
+			Expect(errors.GetErrorMessage(errors.UsersRepoUserDuplicateEmail, errors.LocaleSpanish)).
+				To(Equal("ya existe un usuario con ese correo electrónico"))
+		})
+
+		It("should fall back to the default locale", func() {
+			Expect(errors.GetErrorMessage(errors.UsersRepoUserDuplicateEmail, errors.Locale("fr"))).
+				To(Equal(errors.GetErrorMessage(errors.UsersRepoUserDuplicateEmail, errors.DefaultLocale)))
+		})
+	})
+
+	DescribeTable("MatchLocale",
+		func(acceptLanguage string, expected errors.Locale) {
+			Expect(errors.MatchLocale(acceptLanguage)).To(Equal(expected))
+		},
+		Entry("no header", "", errors.DefaultLocale),
+		Entry("exact language", "es", errors.LocaleSpanish),
+		Entry("language with region", "es-MX", errors.LocaleSpanish),
+		Entry("case insensitive", "ES-mx", errors.LocaleSpanish),
+		Entry("first of equal quality", "en, es", errors.LocaleEnglish),
+		Entry("highest quality", "en;q=0.5, es;q=0.8", errors.LocaleSpanish),
+		Entry("skips unsupported languages", "fr-FR, fr;q=0.9, es;q=0.5", errors.LocaleSpanish),
+		Entry("skips rejected languages", "es;q=0, en;q=0.1", errors.LocaleEnglish),
+		Entry("nothing supported", "fr, de", errors.DefaultLocale),
+		Entry("wildcard", "*", errors.DefaultLocale),
+	)
+})
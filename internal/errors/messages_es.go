@@ -0,0 +1,81 @@
+package errors
+
+// mappedErrorsSpanish are the messages for the codes in Spanish.
+var mappedErrorsSpanish = map[ErrorCode]string{
+	// DB creation errors
+	DBRepoFailedToInitialize: "no se pudo inicializar la base de datos",
+
+	// User repo errors
+	UsersRepoGetAllUsersDBQueryFail:    "no se pudieron obtener los usuarios de los registros",
+	UsersRepoCreateUserDBQueryFail:     "no se pudo crear el usuario en los registros",
+	UsersRepoUserDuplicateUsername:     "ya existe un usuario con ese nombre de usuario",
+	UsersRepoUserDuplicateEmail:        "ya existe un usuario con ese correo electrónico",
+	UsersRepoUserInvalidUserStatus:     "el valor de user_status no es válido",
+	UsersRepoUpdateUserDBQueryFail:     "no se pudo actualizar el usuario en los registros",
+	UsersRepoUpdateInvalidUserId:       "se requiere el id del usuario para actualizarlo",
+	UsersRepoDeleteUserDBQueryFail:     "no se pudo eliminar el usuario de los registros",
+	UsersRepoGetUserDBQueryFail:        "no se pudo obtener el usuario de los registros",
+	UsersRepoGetUserHistoryDBQueryFail: "no se pudo obtener el historial del usuario de los registros",
+
+	// User controller errors
+	UsersControllerUserFailedToBindBody: "el cuerpo de la solicitud del usuario no es válido",
+	UsersControllerInvalidUserIdParam:   "el id de usuario pasado como parámetro de URL no es válido",
+	UsersControllerInvalidAsOfParam:     "el parámetro as_of debe ser una marca de tiempo RFC 3339",
+
+	// Password reset repo errors
+	PasswordResetRepoCreateTokenDBQueryFail:   "no se pudo crear el token de restablecimiento de contraseña en los registros",
+	PasswordResetRepoResetPasswordDBQueryFail: "no se pudo restablecer la contraseña en los registros",
+
+	// Password reset controller errors
+	PasswordResetControllerFailedToBindBody: "el cuerpo de la solicitud de restablecimiento de contraseña no es válido",
+	PasswordResetControllerInvalidEmail:     "se requiere el correo electrónico para restablecer la contraseña",
+	PasswordResetControllerInvalidPassword:  "la contraseña debe tener entre 8 y 72 bytes",
+	PasswordResetControllerInvalidToken:     "el token de restablecimiento de contraseña no es válido o ha expirado",
+	PasswordResetControllerRateLimited:      "demasiadas solicitudes de restablecimiento de contraseña, inténtelo más tarde",
+	PasswordResetControllerFailedToHash:     "no se pudo procesar el restablecimiento de contraseña",
+
+	// Audit repo errors
+	AuditRepoCreateEntryDBQueryFail: "no se pudo crear la entrada de auditoría en los registros",
+	AuditRepoGetEntriesDBQueryFail:  "no se pudieron obtener las entradas de auditoría de los registros",
+
+	// Audit controller errors
+	AuditControllerInvalidFilter: "los parámetros de consulta del filtro de auditoría no son válidos",
+
+	// Scheduled status change repo errors
+	StatusChangesRepoCreateDBQueryFail: "no se pudo crear el cambio de estado programado en los registros",
+	StatusChangesRepoGetDBQueryFail:    "no se pudieron obtener los cambios de estado programados de los registros",
+	StatusChangesRepoCancelDBQueryFail: "no se pudo cancelar el cambio de estado programado en los registros",
+	StatusChangesRepoApplyDBQueryFail:  "no se pudo aplicar el cambio de estado programado en los registros",
+	StatusChangesRepoUserNotFound:      "el usuario del cambio de estado programado no existe",
+
+	// Scheduled status change controller errors
+	StatusChangesControllerFailedToBindBody:   "el cuerpo de la solicitud del cambio de estado programado no es válido",
+	StatusChangesControllerInvalidChangeId:    "el id del cambio de estado programado pasado como parámetro de URL no es válido",
+	StatusChangesControllerInvalidEffectiveAt: "se requiere effective_at para programar un cambio de estado",
+
+	// User status transition errors
+	UsersRepoIllegalStatusTransition: "el estado del usuario no puede cambiar de su estado actual al estado solicitado",
+
+	// User validation errors
+	UsersControllerUserValidationFailed: "el cuerpo de la solicitud del usuario tiene campos no válidos",
+
+	// Health check errors
+	DBRepoPingFailed:             "no se pudo conectar con la base de datos",
+	DBRepoSchemaVersionQueryFail: "no se pudo obtener la versión del esquema desplegado de la base de datos",
+
+	// DB config errors
+	DBRepoInvalidConfig: "la configuración de la base de datos no es válida",
+
+	// Field validation errors
+	FieldRequired:     "es obligatorio",
+	FieldTooLong:      "debe tener como máximo %v caracteres",
+	FieldInvalidEmail: "debe ser un correo electrónico válido",
+	FieldInvalidValue: "debe ser uno de %v",
+	FieldInvalidType:  "debe ser de tipo %v",
+
+	// Authorization errors
+	IdentityClientNotAllowed: "el cliente no tiene permiso para llamar a la API",
+
+	// Audit controller errors
+	AuditControllerInvalidActor: "la cabecera del actor no es válida",
+}
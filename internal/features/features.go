@@ -0,0 +1,27 @@
+// package features reports whether features are turned on by their flags,
+// which can be changed while the server is running.
+package features
+
+import (
+	"maps"
+	"sync/atomic"
+)
+
+var flags atomic.Pointer[map[string]bool]
+
+func init() {
+	Set(nil)
+}
+
+// Set replaces every flag, so flags that are not set are turned off.
+func Set(newFlags map[string]bool) {
+	copied := maps.Clone(newFlags)
+	flags.Store(&copied)
+}
+
+// Enabled reports whether the feature's flag is turned on.
+//
+// Returns false for flags that are not set.
+func Enabled(name string) bool {
+	return (*flags.Load())[name]
+}
@@ -0,0 +1,42 @@
+package features_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/features"
+)
+
+var _ = Describe("Features", func() {
+
+	AfterEach(func() {
+		features.Set(nil)
+	})
+
+	It("should report flags that are not set as off", func() {
+		Expect(features.Enabled("bulk_import")).To(BeFalse())
+	})
+
+	It("should report the flags that were set", func() {
+		features.Set(map[string]bool{"bulk_import": true, "audit_export": false})
+
+		Expect(features.Enabled("bulk_import")).To(BeTrue())
+		Expect(features.Enabled("audit_export")).To(BeFalse())
+	})
+
+	It("should turn off flags that are left out when set again", func() {
+		features.Set(map[string]bool{"bulk_import": true})
+		features.Set(map[string]bool{"audit_export": true})
+
+		Expect(features.Enabled("bulk_import")).To(BeFalse())
+	})
+
+	It("should not be changed by changes to the map it was set with", func() {
+		flags := map[string]bool{"bulk_import": true}
+		features.Set(flags)
+
+		flags["bulk_import"] = false
+
+		Expect(features.Enabled("bulk_import")).To(BeTrue())
+	})
+})
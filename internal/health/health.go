@@ -0,0 +1,146 @@
+package health
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Components reported on by Ready
+const (
+	ComponentServer   = "server"
+	ComponentDatabase = "database"
+	ComponentSchema   = "schema"
+)
+
+type Component struct {
+	Status Status `json:"status"`
+	// Why the component is down, or the version it is at
+	Detail string `json:"detail,omitempty"`
+}
+
+type Report struct {
+	// Down if any of the components are down
+	Status     Status               `json:"status"`
+	Components map[string]Component `json:"components,omitempty"`
+}
+
+// DB is the part of database.Repo readiness depends on
+type DB interface {
+	Ping(ctx context.Context) error
+	SchemaChangeDeployed(ctx context.Context, change string) (bool, error)
+}
+
+// Checker reports whether the app is alive and ready to serve traffic.
+type Checker struct {
+	DB DB
+	// How long checking the DB can take before it is reported down
+	Timeout time.Duration
+
+	shuttingDown atomic.Bool
+	dbLost       atomic.Bool
+}
+
+// NewChecker returns a Checker checking the DB within the timeout
+func NewChecker(db DB, timeout time.Duration) *Checker {
+	return &Checker{DB: db, Timeout: timeout}
+}
+
+// SetShuttingDown marks the app as shutting down, so it is reported as
+// not ready from then on.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// ShuttingDown reports whether SetShuttingDown has been called
+func (c *Checker) ShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+// SetDBLost marks whether the connection to the DB has been lost, so the
+// app is reported as not ready until it is back, without every probe
+// pinging the DB in the meantime.
+func (c *Checker) SetDBLost(lost bool) {
+	c.dbLost.Store(lost)
+}
+
+// DBLost reports whether the connection to the DB has been marked as lost
+func (c *Checker) DBLost() bool {
+	return c.dbLost.Load()
+}
+
+// Live reports the app as up. It does not depend on the DB, so an outage
+// doesn't get the process restarted.
+func (c *Checker) Live() Report {
+	return Report{Status: StatusUp}
+}
+
+// Ready reports whether the app can serve traffic: it is not shutting
+// down, the DB can be reached within the Timeout, and the SchemaVersion
+// has been deployed to it.
+func (c *Checker) Ready(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	report := Report{
+		Status: StatusUp,
+		Components: map[string]Component{
+			ComponentServer:   {Status: StatusUp},
+			ComponentDatabase: {Status: StatusUp},
+			ComponentSchema:   {Status: StatusUp, Detail: constants.SchemaVersion},
+		},
+	}
+
+	if c.ShuttingDown() {
+		report.Components[ComponentServer] = Component{Status: StatusDown, Detail: "shutting down"}
+	}
+
+	if c.DBLost() {
+		report.Components[ComponentDatabase] = Component{Status: StatusDown, Detail: "reconnecting"}
+		report.Components[ComponentSchema] = Component{Status: StatusDown, Detail: "database is down"}
+	} else if err := c.DB.Ping(ctx); err != nil {
+		report.Components[ComponentDatabase] = down(ctx, err)
+		report.Components[ComponentSchema] = Component{Status: StatusDown, Detail: "database is down"}
+	} else if deployed, err := c.DB.SchemaChangeDeployed(ctx, constants.SchemaVersion); err != nil {
+		report.Components[ComponentSchema] = down(ctx, err)
+	} else if !deployed {
+		report.Components[ComponentSchema] = Component{
+			Status: StatusDown,
+			Detail: fmt.Sprintf("%s is not deployed", constants.SchemaVersion),
+		}
+	}
+
+	for _, component := range report.Components {
+		if component.Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+// down logs the error and returns a down Component with its message, so
+// the cause isn't exposed by the report
+func down(ctx context.Context, err error) Component {
+	var appErr *errors.Error
+	if !stdErrors.As(err, &appErr) {
+		logging.ErrorContext(ctx, "Ready", "health check failed", err)
+		return Component{Status: StatusDown}
+	}
+
+	logging.ErrorWithCodeContext(ctx, appErr.Code, appErr.Message, appErr.Err)
+	return Component{Status: StatusDown, Detail: appErr.Message}
+}
@@ -0,0 +1,135 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/health"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+)
+
+var _ = Describe("Checker", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockRepo   *mocks.MockIRepo
+		mockLogger mocks.MockLogger
+		checker    *health.Checker
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockIRepo(mockCtrl)
+		mockLogger = mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+
+		checker = health.NewChecker(mockRepo, time.Second)
+	})
+
+	Describe("Live", func() {
+		It("should report up without checking the DB", func() {
+			Expect(checker.Live()).To(Equal(health.Report{Status: health.StatusUp}))
+		})
+	})
+
+	Describe("Ready", func() {
+		It("should report every component up", func() {
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+			mockRepo.EXPECT().SchemaChangeDeployed(gomock.Any(), constants.SchemaVersion).Return(true, nil)
+
+			report := checker.Ready(context.Background())
+
+			Expect(report).To(Equal(health.Report{
+				Status: health.StatusUp,
+				Components: map[string]health.Component{
+					health.ComponentServer:   {Status: health.StatusUp},
+					health.ComponentDatabase: {Status: health.StatusUp},
+					health.ComponentSchema:   {Status: health.StatusUp, Detail: constants.SchemaVersion},
+				},
+			}))
+		})
+
+		It("should report down when the DB can't be reached, without exposing the cause", func() {
+			code := ipErrors.DBRepoPingFailed
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(ipErrors.New(code, errors.New("connection refused")))
+
+			report := checker.Ready(context.Background())
+
+			Expect(report.Status).To(Equal(health.StatusDown))
+			Expect(report.Components[health.ComponentDatabase]).To(Equal(health.Component{
+				Status: health.StatusDown,
+				Detail: ipErrors.GetErrorMessage(code, ipErrors.DefaultLocale),
+			}))
+			Expect(report.Components[health.ComponentSchema].Status).To(Equal(health.StatusDown))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring("connection refused"))
+		})
+
+		It("should report down when checking the DB takes longer than the timeout", func() {
+			checker.Timeout = 10 * time.Millisecond
+			mockRepo.EXPECT().Ping(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ipErrors.New(ipErrors.DBRepoPingFailed, ctx.Err())
+			})
+
+			report := checker.Ready(context.Background())
+
+			Expect(report.Components[health.ComponentDatabase].Status).To(Equal(health.StatusDown))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring(context.DeadlineExceeded.Error()))
+		})
+
+		It("should report down when the schema version is not deployed", func() {
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+			mockRepo.EXPECT().SchemaChangeDeployed(gomock.Any(), constants.SchemaVersion).Return(false, nil)
+
+			report := checker.Ready(context.Background())
+
+			Expect(report.Status).To(Equal(health.StatusDown))
+			Expect(report.Components[health.ComponentDatabase].Status).To(Equal(health.StatusUp))
+			Expect(report.Components[health.ComponentSchema].Status).To(Equal(health.StatusDown))
+			Expect(report.Components[health.ComponentSchema].Detail).To(ContainSubstring(constants.SchemaVersion))
+		})
+
+		It("should report down when the schema version can't be checked", func() {
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+			mockRepo.EXPECT().SchemaChangeDeployed(gomock.Any(), gomock.Any()).
+				Return(false, ipErrors.New(ipErrors.DBRepoSchemaVersionQueryFail, errors.New("relation does not exist")))
+
+			report := checker.Ready(context.Background())
+
+			Expect(report.Status).To(Equal(health.StatusDown))
+			Expect(report.Components[health.ComponentSchema].Status).To(Equal(health.StatusDown))
+		})
+
+		It("should report down without pinging the DB while it is lost", func() {
+			checker.SetDBLost(true)
+			report := checker.Ready(context.Background())
+
+			Expect(report.Status).To(Equal(health.StatusDown))
+			Expect(report.Components[health.ComponentDatabase]).To(Equal(health.Component{
+				Status: health.StatusDown,
+				Detail: "reconnecting",
+			}))
+			Expect(report.Components[health.ComponentSchema].Status).To(Equal(health.StatusDown))
+			Expect(report.Components[health.ComponentServer].Status).To(Equal(health.StatusUp))
+		})
+
+		It("should report down once shutting down", func() {
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+			mockRepo.EXPECT().SchemaChangeDeployed(gomock.Any(), gomock.Any()).Return(true, nil)
+
+			checker.SetShuttingDown()
+			report := checker.Ready(context.Background())
+
+			Expect(checker.ShuttingDown()).To(BeTrue())
+			Expect(report.Status).To(Equal(health.StatusDown))
+			Expect(report.Components[health.ComponentServer].Status).To(Equal(health.StatusDown))
+		})
+	})
+})
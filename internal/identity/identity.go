@@ -0,0 +1,85 @@
+// package identity identifies the client making a request by the
+// certificate it presented over mutual TLS, so it can be authorized and
+// recorded as the actor of the changes it makes.
+package identity
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the client's identity.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the identity of the client carried by the context.
+//
+// Returns an empty string if the client was not identified.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// FromCertificate returns the identity of the client the certificate was
+// issued to, which is the common name of its subject, or the whole
+// subject if it has no common name.
+func FromCertificate(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+
+	return cert.Subject.String()
+}
+
+// Middleware identifies clients that presented a certificate verified
+// against the client CA bundle, storing their identity in the request's
+// context.
+//
+// Requests without a verified certificate are left unidentified.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+
+			// Verified chains start with the client's own certificate
+			if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 {
+				id := FromCertificate(req.TLS.VerifiedChains[0][0])
+				ctx.SetRequest(req.WithContext(NewContext(req.Context(), id)))
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// Authorize refuses requests from clients whose identity is not one of the
+// allowed, including clients that were not identified, with
+// errors.IdentityClientNotAllowed.
+//
+// Requests for the exempt paths, such as health checks, are let through.
+func Authorize(allowed []string, exempt ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			if slices.Contains(exempt, req.URL.Path) {
+				return next(ctx)
+			}
+
+			id := FromContext(req.Context())
+			if id == "" || !slices.Contains(allowed, id) {
+				return errors.New(errors.IdentityClientNotAllowed, fmt.Errorf("client %q is not allowed", id))
+			}
+
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package identity_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/identity"
+
+	"github.com/labstack/echo/v4"
+)
+
+var _ = Describe("Identity", func() {
+
+	Describe("FromCertificate", func() {
+		It("should return the common name of the subject", func() {
+			cert := &x509.Certificate{Subject: pkix.Name{CommonName: "billing-service", Organization: []string{"Integra"}}}
+
+			Expect(identity.FromCertificate(cert)).To(Equal("billing-service"))
+		})
+
+		It("should return the whole subject if it has no common name", func() {
+			cert := &x509.Certificate{Subject: pkix.Name{Organization: []string{"Integra"}, OrganizationalUnit: []string{"Billing"}}}
+
+			Expect(identity.FromCertificate(cert)).To(Equal("OU=Billing,O=Integra"))
+		})
+	})
+
+	Describe("Middleware", func() {
+		var e *echo.Echo
+		var req *http.Request
+
+		// serve returns the identity the request was handled with
+		serve := func() string {
+			var id string
+			handler := identity.Middleware()(func(ctx echo.Context) error {
+				id = identity.FromContext(ctx.Request().Context())
+				return nil
+			})
+
+			Expect(handler(e.NewContext(req, httptest.NewRecorder()))).To(Succeed())
+			return id
+		}
+
+		BeforeEach(func() {
+			e = echo.New()
+			req = httptest.NewRequest(http.MethodGet, "/", nil)
+		})
+
+		It("should identify a client with a verified certificate", func() {
+			client := &x509.Certificate{Subject: pkix.Name{CommonName: "billing-service"}}
+			ca := &x509.Certificate{Subject: pkix.Name{CommonName: "Test CA"}}
+			req.TLS = &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{client},
+				VerifiedChains:   [][]*x509.Certificate{{client, ca}},
+			}
+
+			Expect(serve()).To(Equal("billing-service"))
+		})
+
+		It("should not identify a client whose certificate wasn't verified", func() {
+			req.TLS = &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "billing-service"}}},
+			}
+
+			Expect(serve()).To(BeEmpty())
+		})
+
+		It("should not identify a client over plain HTTP", func() {
+			Expect(serve()).To(BeEmpty())
+		})
+	})
+
+	Describe("Authorize", func() {
+		var e *echo.Echo
+
+		// serve serves a request for the path from the client with the
+		// identity, returning the error the middleware responded with
+		serve := func(path string, id string) error {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if id != "" {
+				req = req.WithContext(identity.NewContext(req.Context(), id))
+			}
+
+			handler := identity.Authorize([]string{"billing-service"}, "/healthz")(func(ctx echo.Context) error {
+				return nil
+			})
+			return handler(e.NewContext(req, httptest.NewRecorder()))
+		}
+
+		BeforeEach(func() {
+			e = echo.New()
+		})
+
+		It("should let an allowed client through", func() {
+			Expect(serve("/users", "billing-service")).To(Succeed())
+		})
+
+		It("should refuse a client that isn't allowed", func() {
+			Expect(serve("/users", "reporting-service")).To(MatchError(ipErrors.IdentityClientNotAllowed))
+		})
+
+		It("should refuse a client that wasn't identified", func() {
+			Expect(serve("/users", "")).To(MatchError(ipErrors.IdentityClientNotAllowed))
+		})
+
+		It("should let any client request an exempt path", func() {
+			Expect(serve("/healthz", "")).To(Succeed())
+		})
+	})
+})
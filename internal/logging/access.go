@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// AccessLogger returns middleware that logs every request handled with
+// the Logger, once the response has been written.
+//
+// Requests that failed with a server error are logged at error level,
+// all others at info level.
+func AccessLogger() echo.MiddlewareFunc {
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		// Let the error handler write the response first so the status
+		// logged is the one the client received
+		HandleError:     true,
+		LogLatency:      true,
+		LogRemoteIP:     true,
+		LogMethod:       true,
+		LogURI:          true,
+		LogRoutePath:    true,
+		LogStatus:       true,
+		LogResponseSize: true,
+		LogUserAgent:    true,
+		LogError:        true,
+		LogValuesFunc: func(ctx echo.Context, v middleware.RequestLoggerValues) error {
+			lvl := slog.LevelInfo
+			if v.Status >= http.StatusInternalServerError {
+				lvl = slog.LevelError
+			}
+
+			Logger.LogAttrs(ctx.Request().Context(), lvl, "request",
+				slog.String("method", v.Method),
+				slog.String("uri", v.URI),
+				slog.String("route", v.RoutePath),
+				slog.Int("status", v.Status),
+				slog.Int64("latency_ms", v.Latency.Milliseconds()),
+				slog.Int64("bytes_out", v.ResponseSize),
+				slog.String("remote_ip", v.RemoteIP),
+				slog.String("user_agent", v.UserAgent),
+				Err(v.Error))
+
+			return nil
+		},
+	})
+}
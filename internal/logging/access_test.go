@@ -0,0 +1,69 @@
+package logging_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/requestid"
+)
+
+var _ = Describe("AccessLogger", func() {
+	var mockLogger mocks.MockLogger
+	var e *echo.Echo
+
+	// serve handles a request through the request id and access logging
+	// middleware, returning the line the access logger wrote
+	serve := func(req *http.Request) map[string]any {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var line map[string]any
+		Expect(json.Unmarshal([]byte(mockLogger.GetBufferValue()), &line)).To(Succeed())
+		return line
+	}
+
+	BeforeEach(func() {
+		mockLogger = mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+
+		e = echo.New()
+		e.Use(requestid.Middleware())
+		e.Use(logging.AccessLogger())
+		e.GET("/users/:id", func(ctx echo.Context) error {
+			return ctx.String(http.StatusOK, "ok")
+		})
+		e.GET("/broken", func(ctx echo.Context) error {
+			return echo.ErrServiceUnavailable
+		})
+	})
+
+	It("should log the request with its route, status and request id", func() {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		req.Header.Set(echo.HeaderXRequestID, "request-1")
+
+		line := serve(req)
+
+		Expect(line["level"]).To(Equal("INFO"))
+		Expect(line["method"]).To(Equal(http.MethodGet))
+		Expect(line["uri"]).To(Equal("/users/1"))
+		Expect(line["route"]).To(Equal("/users/:id"))
+		Expect(line["status"]).To(BeEquivalentTo(http.StatusOK))
+		Expect(line[logging.RequestIdKey]).To(Equal("request-1"))
+		Expect(line).To(HaveKey("latency_ms"))
+	})
+
+	It("should log server errors at error level with the status written", func() {
+		line := serve(httptest.NewRequest(http.MethodGet, "/broken", nil))
+
+		Expect(line["level"]).To(Equal("ERROR"))
+		Expect(line["status"]).To(BeEquivalentTo(http.StatusServiceUnavailable))
+		Expect(line).To(HaveKey(logging.ErrKey))
+	})
+})
@@ -1,37 +1,199 @@
 package logging
 
 import (
+	"context"
+	stdErrors "errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
 
 	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/requestid"
+)
+
+// Attribute keys shared by every log line that carries them
+const (
+	RequestIdKey = "request_id"
+	ErrorCodeKey = "error_code"
+	FuncKey      = "func"
+	ErrKey       = "err"
+	DBErrKey     = "db_err"
+)
+
+// Formats the Logger can write records in
+const (
+	FormatJSON = "json"
+	FormatText = "text"
 )
 
-var Logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+// level is shared by every handler New creates so the level can be
+// changed without replacing the Logger
+var level = new(slog.LevelVar)
+
+var Logger = New(os.Stdout, FormatJSON)
 
-// Error prints an error log with specific formatting.
+// New returns a logger writing records to w in the format, filtered by
+// the level set with SetLevel and masked by the redaction set with
+// SetRedaction.
 //
-// Format will be "{fromFunc}: {message}. Error: {err.Error()}".
-// The Error will be omitted if it is nil.
-func Error(fromFunc string, message string, err error) {
-	logMsg := fmt.Sprintf("%s: %s", fromFunc, message)
-	if err != nil {
-		logMsg = fmt.Sprintf("%s. Error: %s", logMsg, err.Error())
+// Falls back to JSON if the format is not known.
+func New(w io.Writer, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if strings.EqualFold(format, FormatText) {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
 	}
 
-	Logger.Error(logMsg)
+	return slog.New(NewContextHandler(NewRedactHandler(handler)))
 }
 
-// Error prints an error log with specific formatting.
+// Configure replaces the Logger with one writing to stdout in the format
+// and sets the level.
 //
-// Format will be "Code: {code}. {message} Error: {err.Error()}".
-// The Error will be omitted if it is nil.
-func ErrorWithCode(code errors.ErrorCode, message string, err error) {
-	logMsg := fmt.Sprintf("Code: %d. %s", code, message)
-	if err != nil {
-		logMsg = fmt.Sprintf("%s. Error: %s", logMsg, err.Error())
+// Returns an error if the level or format is not known, leaving the
+// Logger unchanged.
+func Configure(levelName string, format string) error {
+	if !strings.EqualFold(format, FormatJSON) && !strings.EqualFold(format, FormatText) {
+		return fmt.Errorf("unknown log format %q", format)
+	}
+
+	if err := SetLevel(levelName); err != nil {
+		return err
+	}
+
+	Logger = New(os.Stdout, format)
+	return nil
+}
+
+// SetLevel sets the lowest level that will be logged. Accepts the slog
+// level names, e.g. "debug", "info", "warn" and "error".
+//
+// Returns an error if the level is not known, leaving it unchanged.
+func SetLevel(levelName string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(levelName)); err != nil {
+		return fmt.Errorf("unknown log level %q", levelName)
+	}
+
+	level.Set(l)
+	return nil
+}
+
+// Level returns the lowest level that will be logged
+func Level() slog.Level {
+	return level.Level()
+}
+
+// ErrorCode returns the attribute a log line carries an error code under
+func ErrorCode(code errors.ErrorCode) slog.Attr {
+	return slog.Int(ErrorCodeKey, int(code))
+}
+
+// Func returns the attribute a log line carries the name of the function
+// it was logged from under
+func Func(name string) slog.Attr {
+	return slog.String(FuncKey, name)
+}
+
+// Err returns the attribute a log line carries an error under.
+//
+// The attribute is empty, and so left out of the log line, if err is nil.
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+
+	return slog.String(ErrKey, err.Error())
+}
+
+// DBErr returns the attribute a log line carries the code and constraint
+// of a Postgres error under. The detail is left out, as it names the
+// values the constraint was violated by.
+//
+// The attribute is empty, and so left out of the log line, if err is not
+// a Postgres error.
+func DBErr(err error) slog.Attr {
+	var pgErr *pgconn.PgError
+	if !stdErrors.As(err, &pgErr) {
+		return slog.Attr{}
+	}
+
+	attrs := []any{slog.String("code", pgErr.Code)}
+	if pgErr.ConstraintName != "" {
+		attrs = append(attrs, slog.String("constraint", pgErr.ConstraintName))
+	}
+
+	return slog.Group(DBErrKey, attrs...)
+}
+
+// RequestId returns the attribute a log line carries a request id under
+func RequestId(id string) slog.Attr {
+	return slog.String(RequestIdKey, id)
+}
+
+// ContextHandler adds the request id carried by the context, if any, to
+// each record before passing it to the wrapped handler.
+type ContextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler wraps the handler with a ContextHandler
+func NewContextHandler(handler slog.Handler) ContextHandler {
+	return ContextHandler{Handler: handler}
+}
+
+// Handle adds the request id to the record and handles it
+func (h ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		record.AddAttrs(RequestId(id))
 	}
-	
-	Logger.Error(logMsg)
-}
\ No newline at end of file
+
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a ContextHandler wrapping the handler with the attrs
+func (h ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a ContextHandler wrapping the handler with the group
+func (h ContextHandler) WithGroup(name string) slog.Handler {
+	return ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// Error logs the message at error level with the function it was logged
+// from and the error as attributes.
+//
+// The err attribute will be omitted if err is nil, and the db_err
+// attribute unless err is a Postgres error.
+func Error(fromFunc string, message string, err error) {
+	ErrorContext(context.Background(), fromFunc, message, err)
+}
+
+// ErrorContext logs the same attributes as Error, along with the request
+// id carried by the context.
+func ErrorContext(ctx context.Context, fromFunc string, message string, err error) {
+	Logger.LogAttrs(ctx, slog.LevelError, message, Func(fromFunc), Err(err), DBErr(err))
+}
+
+// ErrorWithCode logs the message at error level with the error code and
+// the error as attributes.
+//
+// The err attribute will be omitted if err is nil, and the db_err
+// attribute unless err is a Postgres error.
+func ErrorWithCode(code errors.ErrorCode, message string, err error) {
+	ErrorWithCodeContext(context.Background(), code, message, err)
+}
+
+// ErrorWithCodeContext logs the same attributes as ErrorWithCode, along
+// with the request id carried by the context.
+func ErrorWithCodeContext(ctx context.Context, code errors.ErrorCode, message string, err error) {
+	Logger.LogAttrs(ctx, slog.LevelError, message, ErrorCode(code), Err(err), DBErr(err))
+}
@@ -1,9 +1,11 @@
 package logging_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
-	"fmt"
-	"strings"
+	"log/slog"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -11,11 +13,21 @@ import (
 	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
 	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/requestid"
 )
 
 var _ = Describe("Logging", Ordered, func() {
 	var mockLogger mocks.MockLogger
 
+	// lastLine decodes the attributes of the last line logged
+	lastLine := func() map[string]any {
+		lines := bytes.Split(bytes.TrimSpace([]byte(mockLogger.GetBufferValue())), []byte("\n"))
+
+		var line map[string]any
+		Expect(json.Unmarshal(lines[len(lines)-1], &line)).To(Succeed())
+		return line
+	}
+
 	BeforeAll(func() {
 		mockLogger = mocks.NewMockLogger()
 		logging.Logger = mockLogger.Logger
@@ -23,47 +35,118 @@ var _ = Describe("Logging", Ordered, func() {
 
 	BeforeEach(func() {
 		mockLogger.ResetBuffer()
+		Expect(logging.SetLevel("info")).To(Succeed())
 	})
 
 	Describe("Error", func() {
-		It("should log an error message in a specified format, with error", func() {
-			fromFunc := "testFunc"
-			message := "test message to use!"
-			err := errors.New("a fake error to use!")
-			expected := fmt.Sprintf("%s: %s. Error: %s", fromFunc, message, err.Error())
-
-			logging.Error(fromFunc, message, err)
-			Expect(strings.Contains(mockLogger.GetBufferValue(), expected)).To(Equal(true))
+		It("should log the message with the function and error as attributes", func() {
+			logging.Error("testFunc", "test message to use!", errors.New("a fake error to use!"))
+
+			line := lastLine()
+			Expect(line["level"]).To(Equal("ERROR"))
+			Expect(line["msg"]).To(Equal("test message to use!"))
+			Expect(line[logging.FuncKey]).To(Equal("testFunc"))
+			Expect(line[logging.ErrKey]).To(Equal("a fake error to use!"))
 		})
-		It("should log an error message in a specified format, without error", func() {
-			fromFunc := "testFunc"
-			message := "test message to use!"
-			expected := fmt.Sprintf("%s: %s", fromFunc, message)
 
-			logging.Error(fromFunc, message, nil)
-			Expect(strings.Contains(mockLogger.GetBufferValue(), expected)).To(Equal(true))
+		It("should leave out the error attribute without an error", func() {
+			logging.Error("testFunc", "test message to use!", nil)
+
+			line := lastLine()
+			Expect(line[logging.FuncKey]).To(Equal("testFunc"))
+			Expect(line).NotTo(HaveKey(logging.ErrKey))
 		})
 	})
 
 	Describe("ErrorWithCode", func() {
-		It("should log an error message with code in a specified format, with error", func() {
+		It("should log the message with the code and error as attributes", func() {
 			code := ipErrors.UsersControllerInvalidUserIdParam
-			message := "test message to use!"
-			err := errors.New("a fake error to use!")
-			expected := fmt.Sprintf("Code: %d. %s. Error: %s", code, message, err.Error())
 
-			logging.ErrorWithCode(code, message, err)
-			Expect(strings.Contains(mockLogger.GetBufferValue(), expected)).To(Equal(true))
+			logging.ErrorWithCode(code, "test message to use!", errors.New("a fake error to use!"))
+
+			line := lastLine()
+			Expect(line["msg"]).To(Equal("test message to use!"))
+			Expect(line[logging.ErrorCodeKey]).To(BeEquivalentTo(code))
+			Expect(line[logging.ErrKey]).To(Equal("a fake error to use!"))
 		})
 
-		It("should log an error message with code in a specified format, without error", func() {
+		It("should leave out the error attribute without an error", func() {
+			logging.ErrorWithCode(ipErrors.UsersControllerInvalidUserIdParam, "test message to use!", nil)
+
+			Expect(lastLine()).NotTo(HaveKey(logging.ErrKey))
+		})
+	})
+
+	Describe("ErrorContext", func() {
+		It("should log the request id carried by the context", func() {
+			ctx := requestid.NewContext(context.Background(), "request-1")
+
+			logging.ErrorContext(ctx, "testFunc", "test message to use!", nil)
+			Expect(lastLine()[logging.RequestIdKey]).To(Equal("request-1"))
+		})
+
+		It("should not log a request id when the context has none", func() {
+			logging.ErrorContext(context.Background(), "testFunc", "test message to use!", nil)
+			Expect(lastLine()).NotTo(HaveKey(logging.RequestIdKey))
+		})
+	})
+
+	Describe("ErrorWithCodeContext", func() {
+		It("should log the request id carried by the context", func() {
+			ctx := requestid.NewContext(context.Background(), "request-1")
 			code := ipErrors.UsersControllerInvalidUserIdParam
-			message := "test message to use!"
 
-			expected := fmt.Sprintf("Code: %d. %s", code, message)
+			logging.ErrorWithCodeContext(ctx, code, "test message to use!", nil)
+
+			line := lastLine()
+			Expect(line[logging.ErrorCodeKey]).To(BeEquivalentTo(code))
+			Expect(line[logging.RequestIdKey]).To(Equal("request-1"))
+		})
+	})
+
+	Describe("ContextHandler", func() {
+		It("should keep adding the request id to loggers with attributes", func() {
+			ctx := requestid.NewContext(context.Background(), "request-1")
+
+			logging.Logger.With("component", "scheduler").InfoContext(ctx, "test message to use!")
+
+			line := lastLine()
+			Expect(line["component"]).To(Equal("scheduler"))
+			Expect(line[logging.RequestIdKey]).To(Equal("request-1"))
+		})
+	})
+
+	Describe("SetLevel", func() {
+		It("should only log lines at or above the level", func() {
+			Expect(logging.SetLevel("warn")).To(Succeed())
+
+			logging.Logger.Info("test message to use!")
+			Expect(mockLogger.GetBufferValue()).To(BeEmpty())
+
+			logging.Logger.Warn("test message to use!")
+			Expect(mockLogger.GetBufferValue()).NotTo(BeEmpty())
+		})
+
+		It("should return an error for an unknown level and leave it unchanged", func() {
+			Expect(logging.SetLevel("loud")).NotTo(Succeed())
+			Expect(logging.Level()).To(Equal(slog.LevelInfo))
+		})
+	})
+
+	Describe("Configure", func() {
+		It("should return an error for an unknown format and leave the logger unchanged", func() {
+			Expect(logging.Configure("debug", "xml")).NotTo(Succeed())
+			Expect(logging.Logger).To(Equal(mockLogger.Logger))
+			Expect(logging.Level()).To(Equal(slog.LevelInfo))
+		})
+	})
+
+	Describe("New", func() {
+		It("should write text lines when asked for the text format", func() {
+			var buff bytes.Buffer
 
-			logging.ErrorWithCode(code, message, nil)
-			Expect(strings.Contains(mockLogger.GetBufferValue(), expected)).To(Equal(true))
+			logging.New(&buff, logging.FormatText).Info("test message to use!", "component", "scheduler")
+			Expect(buff.String()).To(ContainSubstring(`msg="test message to use!" component=scheduler`))
 		})
 	})
 })
@@ -0,0 +1,229 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Redacted replaces values masked in log lines
+const Redacted = "[REDACTED]"
+
+// EmailField is the field name email addresses found anywhere in a log
+// line are masked under, so they can be allow-listed like any other field
+const EmailField = "email"
+
+// DefaultRedactedFields are always masked, along with any fields added
+// with SetRedaction
+var DefaultRedactedFields = []string{
+	EmailField,
+	"user_name",
+	"first_name",
+	"last_name",
+	"password",
+	"password_hash",
+	"token",
+	"token_hash",
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// redaction is shared by every handler New creates so it can be changed
+// without replacing the Logger
+var redaction atomic.Pointer[redactor]
+
+func init() {
+	SetRedaction(nil, nil)
+}
+
+// SetRedaction sets the fields masked in log lines, in addition to the
+// DefaultRedactedFields, and the fields left unmasked while the level is
+// debug.
+func SetRedaction(fields []string, allow []string) {
+	redaction.Store(newRedactor(append(append([]string{}, DefaultRedactedFields...), fields...), allow))
+}
+
+// Redact masks the email addresses and the values of redacted fields
+// found in the string, as they would be in a log line.
+func Redact(s string) string {
+	return redaction.Load().forLevel().redact(s)
+}
+
+// fieldPatterns match the values of a set of fields in the forms they
+// show up in log lines
+type fieldPatterns struct {
+	// Postgres error details, e.g. Key (email)=(test@user.com) or
+	// Key (lower(user_name::text))=(test)
+	pgKey *regexp.Regexp
+	// JSON, e.g. "email":"test@user.com"
+	json *regexp.Regexp
+	// Key value pairs, e.g. email=test@user.com
+	pair *regexp.Regexp
+}
+
+func newFieldPatterns(fields []string) *fieldPatterns {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = regexp.QuoteMeta(field)
+	}
+	names := fmt.Sprintf(`(?i:%s)`, strings.Join(quoted, "|"))
+
+	return &fieldPatterns{
+		pgKey: regexp.MustCompile(fmt.Sprintf(`(\([^=]*?\b%s\b[^=]*?\)=\().*?(\)(?:[\s.,;]|$))`, names)),
+		json:  regexp.MustCompile(fmt.Sprintf(`("%s"\s*:\s*)"(?:[^"\\]|\\.)*"`, names)),
+		pair:  regexp.MustCompile(fmt.Sprintf(`(\b%s=)(?:"(?:[^"\\]|\\.)*"|[^\s,;)]+)`, names)),
+	}
+}
+
+// redactor masks the configured fields, with a second set of patterns
+// leaving out the allow-listed fields for when the level is debug
+type redactor struct {
+	fields     map[string]bool
+	maskEmails bool
+	patterns   *fieldPatterns
+	debug      *redactor
+}
+
+func newRedactor(fields []string, allow []string) *redactor {
+	allowed := map[string]bool{}
+	for _, field := range allow {
+		allowed[strings.ToLower(strings.TrimSpace(field))] = true
+	}
+
+	r := &redactor{fields: map[string]bool{}, maskEmails: true}
+	debug := &redactor{fields: map[string]bool{}}
+	var debugFields []string
+
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" || r.fields[field] {
+			continue
+		}
+
+		r.fields[field] = true
+		if !allowed[field] {
+			debug.fields[field] = true
+			debugFields = append(debugFields, field)
+		}
+	}
+
+	r.patterns = newFieldPatterns(fieldNames(r.fields))
+	debug.maskEmails = debug.fields[EmailField]
+	debug.patterns = newFieldPatterns(debugFields)
+	r.debug = debug
+
+	return r
+}
+
+func fieldNames(fields map[string]bool) []string {
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+
+	return names
+}
+
+// forLevel returns the redactor for the current level, leaving the
+// allow-listed fields unmasked while it is debug
+func (r *redactor) forLevel() *redactor {
+	if Level() <= slog.LevelDebug {
+		return r.debug
+	}
+
+	return r
+}
+
+func (r *redactor) redact(s string) string {
+	if r.maskEmails {
+		s = emailPattern.ReplaceAllString(s, Redacted)
+	}
+
+	if r.patterns != nil {
+		s = r.patterns.pgKey.ReplaceAllString(s, "${1}"+Redacted+"${2}")
+		s = r.patterns.json.ReplaceAllString(s, `${1}"`+Redacted+`"`)
+		s = r.patterns.pair.ReplaceAllString(s, "${1}"+Redacted)
+	}
+
+	return s
+}
+
+func (r *redactor) redactAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	if r.fields[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, r.redact(attr.Value.String()))
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, a := range group {
+			attrs[i] = r.redactAttr(a)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, r.redact(err.Error()))
+		}
+	}
+
+	return attr
+}
+
+func (r *redactor) redactAttrs(attrs []slog.Attr) []slog.Attr {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = r.redactAttr(attr)
+	}
+
+	return redacted
+}
+
+// RedactHandler masks email addresses and the values of redacted fields
+// in each record's message and attributes before passing it to the
+// wrapped handler.
+type RedactHandler struct {
+	slog.Handler
+}
+
+// NewRedactHandler wraps the handler with a RedactHandler
+func NewRedactHandler(handler slog.Handler) RedactHandler {
+	return RedactHandler{Handler: handler}
+}
+
+// Handle masks the record's message and attributes and handles it
+func (h RedactHandler) Handle(ctx context.Context, record slog.Record) error {
+	r := redaction.Load().forLevel()
+
+	redacted := slog.NewRecord(record.Time, record.Level, r.redact(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(r.redactAttr(attr))
+		return true
+	})
+
+	return h.Handler.Handle(ctx, redacted)
+}
+
+// WithAttrs returns a RedactHandler wrapping the handler with the attrs
+// masked.
+//
+// The attrs are masked with the redaction set when WithAttrs is called.
+func (h RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return RedactHandler{Handler: h.Handler.WithAttrs(redaction.Load().forLevel().redactAttrs(attrs))}
+}
+
+// WithGroup returns a RedactHandler wrapping the handler with the group
+func (h RedactHandler) WithGroup(name string) slog.Handler {
+	return RedactHandler{Handler: h.Handler.WithGroup(name)}
+}
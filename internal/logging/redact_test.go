@@ -0,0 +1,145 @@
+package logging_test
+
+import (
+	stdErrors "errors"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+)
+
+var _ = Describe("Redaction", func() {
+	var mockLogger mocks.MockLogger
+
+	BeforeEach(func() {
+		mockLogger = mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+		Expect(logging.SetLevel("info")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		logging.SetRedaction(nil, nil)
+		Expect(logging.SetLevel("info")).To(Succeed())
+	})
+
+	// The errors the DB returns for the constraint violations
+	// checkUserDBError maps to error codes, with the detail naming the
+	// values that violated them
+	DescribeTable("should leave the detail of constraint violations from the DB out",
+		func(code ipErrors.ErrorCode, pgErr *pgconn.PgError, pii string) {
+			logging.ErrorWithCode(code, "failed to create user", ipErrors.New(code, pgErr))
+
+			Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring(pii))
+			Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring(pgErr.Detail))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"constraint":"` + pgErr.ConstraintName + `"`))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"code":"` + pgErr.Code + `"`))
+		},
+		Entry("duplicate username",
+			ipErrors.UsersRepoUserDuplicateUsername,
+			&pgconn.PgError{
+				Code:           pgerrcode.UniqueViolation,
+				Message:        "duplicate key value violates unique constraint \"users_user_name_idx\"",
+				Detail:         "Key (user_name)=(testUser) already exists.",
+				ConstraintName: "users_user_name_idx",
+			},
+			"testUser"),
+		Entry("duplicate email",
+			ipErrors.UsersRepoUserDuplicateEmail,
+			&pgconn.PgError{
+				Code:           pgerrcode.UniqueViolation,
+				Message:        "duplicate key value violates unique constraint \"users_email_idx\"",
+				Detail:         "Key (email)=(test@user.com) already exists.",
+				ConstraintName: "users_email_idx",
+			},
+			"test@user.com"),
+		Entry("duplicate username differing only by case",
+			ipErrors.UsersRepoUserDuplicateUsername,
+			&pgconn.PgError{
+				Code:           pgerrcode.UniqueViolation,
+				Message:        "duplicate key value violates unique constraint \"users_user_name_idx\"",
+				Detail:         "Key (lower(user_name::text))=(testuser) already exists.",
+				ConstraintName: "users_user_name_idx",
+			},
+			"testuser"),
+	)
+
+	It("should log the code of DB errors without a constraint", func() {
+		pgErr := &pgconn.PgError{
+			Code:    pgerrcode.ForeignKeyViolation,
+			Message: "insert or update on table \"audit_log\" violates foreign key constraint",
+			Detail:  "Key (user_id)=(1) is not present in table \"users\".",
+		}
+
+		logging.Error("createAuditEntry", "failed to create audit entry", pgErr)
+		Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring("Key (user_id)=(1)"))
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"code":"` + pgErr.Code + `"`))
+		Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring(`"constraint"`))
+	})
+
+	It("should leave the DB attribute out of errors not from the DB", func() {
+		logging.Error("createAuditEntry", "failed to create audit entry", stdErrors.New("connection refused"))
+		Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring(logging.DBErrKey))
+	})
+
+	It("should mask email addresses anywhere in the message", func() {
+		logging.Logger.Info("sent reset email to test@user.com")
+
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring("sent reset email to " + logging.Redacted))
+	})
+
+	It("should mask the values of redacted fields in the message", func() {
+		logging.Logger.Info(`updated user first_name=test {"last_name":"user"}`)
+
+		Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring("test"))
+		Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring(`\"user\"`))
+	})
+
+	It("should mask attributes keyed by redacted fields, including in groups", func() {
+		logging.Logger.With("first_name", "test").Info("updated user", "user_id", 1)
+		logging.Logger.WithGroup("user").Info("updated user", "last_name", "user", "department", "sales")
+
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"first_name":"` + logging.Redacted + `"`))
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"last_name":"` + logging.Redacted + `"`))
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"user_id":1`))
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"department":"sales"`))
+	})
+
+	It("should mask the configured fields along with the defaults", func() {
+		logging.SetRedaction([]string{"department"}, nil)
+
+		logging.Logger.Info("updated user", "department", "sales", "email", "test@user.com")
+
+		Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring("sales"))
+		Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring("test@user.com"))
+	})
+
+	It("should leave allow-listed fields unmasked at debug level", func() {
+		logging.SetRedaction(nil, []string{"email"})
+		Expect(logging.SetLevel("debug")).To(Succeed())
+
+		logging.Logger.Debug("sent reset email to test@user.com", "first_name", "test")
+
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring("sent reset email to test@user.com"))
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"first_name":"` + logging.Redacted + `"`))
+	})
+
+	It("should mask allow-listed fields above debug level", func() {
+		logging.SetRedaction(nil, []string{"email"})
+
+		logging.Logger.Info("sent reset email to test@user.com")
+
+		Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring("test@user.com"))
+	})
+
+	Describe("Redact", func() {
+		It("should mask the values in the string", func() {
+			Expect(logging.Redact("Key (email)=(test@user.com) already exists.")).
+				To(Equal("Key (email)=(" + logging.Redacted + ") already exists."))
+		})
+	})
+})
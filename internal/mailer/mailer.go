@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer is the abstraction used to deliver emails to users.
+type Mailer interface {
+	Send(message Message) error
+}
+
+// New returns the Mailer for the given config.
+//
+// Returns an SMTPMailer when a host is configured, otherwise returns
+// a LogMailer so local environments do not need an SMTP server.
+func New(mailerConfig config.MailerConfig) Mailer {
+	if mailerConfig.Host == "" {
+		return LogMailer{}
+	}
+
+	return SMTPMailer{Config: mailerConfig}
+}
+
+type SMTPMailer struct {
+	Config config.MailerConfig
+}
+
+// Send delivers the message through the configured SMTP server.
+//
+// Returns an error if the SMTP server rejects the message.
+func (m SMTPMailer) Send(message Message) error {
+	var auth smtp.Auth
+	if m.Config.Username != "" {
+		auth = smtp.PlainAuth("", m.Config.Username, m.Config.Password, m.Config.Host)
+	}
+
+	body := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		m.Config.From,
+		message.To,
+		message.Subject,
+		message.Body)
+
+	return smtp.SendMail(
+		fmt.Sprintf("%s:%s", m.Config.Host, m.Config.Port),
+		auth,
+		m.Config.From,
+		[]string{message.To},
+		[]byte(body))
+}
+
+type LogMailer struct{}
+
+// Send writes the message to the logs instead of delivering it.
+func (m LogMailer) Send(message Message) error {
+	logging.Logger.Info("Email not sent, no SMTP host configured",
+		"to", message.To,
+		"subject", message.Subject)
+
+	return nil
+}
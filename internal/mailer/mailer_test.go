@@ -0,0 +1,98 @@
+package mailer_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mailer"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+)
+
+var _ = Describe("Mailer", func() {
+
+	Describe("New", func() {
+		It("should return a LogMailer when no SMTP host is configured", func() {
+			Expect(mailer.New(config.MailerConfig{})).To(Equal(mailer.LogMailer{}))
+		})
+
+		It("should return an SMTPMailer when an SMTP host is configured", func() {
+			mailerConfig := config.MailerConfig{Host: "smtp.integrapartners.com", Port: "587"}
+
+			Expect(mailer.New(mailerConfig)).To(Equal(mailer.SMTPMailer{Config: mailerConfig}))
+		})
+	})
+
+	Describe("LogMailer", func() {
+		It("should log the subject with the recipient redacted", func() {
+			mockLogger := mocks.NewMockLogger()
+			logging.Logger = mockLogger.Logger
+
+			err := mailer.LogMailer{}.Send(mailer.Message{
+				To:      "test@user.com",
+				Subject: "Reset your password",
+				Body:    "secret token",
+			})
+
+			Expect(err).To(BeNil())
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"to":"` + logging.Redacted + `"`))
+			Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring("test@user.com"))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring("Reset your password"))
+			Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring("secret token"))
+		})
+	})
+
+	Describe("Queue", func() {
+		message := mailer.Message{To: "test@user.com", Subject: "Reset your password"}
+
+		It("should send queued messages in the background", func() {
+			mockMailer := mocks.NewMockMailer()
+			mockMailer.Delay = 100 * time.Millisecond
+			queue := mailer.NewQueue(mockMailer, 1)
+			queue.Start()
+
+			start := time.Now()
+			Expect(queue.Send(message)).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically("<", mockMailer.Delay))
+
+			// Stopping waits for the queued message to be sent
+			queue.Stop()
+			Expect(mockMailer.SentMessages()).To(Equal([]mailer.Message{message}))
+		})
+
+		It("should refuse messages once full", func() {
+			queue := mailer.NewQueue(mocks.NewMockMailer(), 1)
+
+			Expect(queue.Send(message)).To(Succeed())
+			Expect(queue.Send(message)).To(MatchError(mailer.ErrQueueFull))
+		})
+
+		It("should refuse messages once stopped", func() {
+			queue := mailer.NewQueue(mocks.NewMockMailer(), 1)
+			queue.Start()
+			queue.Stop()
+
+			Expect(queue.Send(message)).To(MatchError(mailer.ErrQueueStopped))
+		})
+
+		It("should log messages that fail to send", func() {
+			mockLogger := mocks.NewMockLogger()
+			logging.Logger = mockLogger.Logger
+
+			mockMailer := mocks.NewMockMailer()
+			mockMailer.Err = errors.New("SMTP is down!")
+			queue := mailer.NewQueue(mockMailer, 1)
+			queue.Start()
+
+			Expect(queue.Send(message)).To(Succeed())
+			queue.Stop()
+
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring("failed to send email"))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring("SMTP is down!"))
+		})
+	})
+})
@@ -0,0 +1,85 @@
+package mailer
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+)
+
+// ErrQueueFull is returned when a message is sent to a Queue that has no
+// room left for it.
+var ErrQueueFull = errors.New("mail queue is full")
+
+// ErrQueueStopped is returned when a message is sent to a Queue that has
+// been stopped.
+var ErrQueueStopped = errors.New("mail queue is stopped")
+
+// Queue is a Mailer that delivers messages through another Mailer in the
+// background, so callers are not held up by the time delivery takes.
+type Queue struct {
+	Mailer Mailer
+
+	messages chan Message
+	// Guards sending against the queue being stopped
+	mu      sync.RWMutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewQueue creates a Queue delivering through the mailer, holding up to
+// size messages waiting to be delivered.
+func NewQueue(mailer Mailer, size int) *Queue {
+	return &Queue{
+		Mailer:   mailer,
+		messages: make(chan Message, size),
+	}
+}
+
+// Send queues the message to be delivered in the background.
+//
+// Returns an error if the queue is full or has been stopped. Failures to
+// deliver are only logged.
+func (q *Queue) Send(message Message) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.stopped {
+		return ErrQueueStopped
+	}
+
+	select {
+	case q.messages <- message:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Start delivers queued messages in the background until Stop is called.
+func (q *Queue) Start() {
+	q.wg.Add(1)
+
+	go func() {
+		defer q.wg.Done()
+
+		for message := range q.messages {
+			if err := q.Mailer.Send(message); err != nil {
+				logging.Error("Queue", "failed to send email", err)
+			}
+		}
+	}()
+}
+
+// Stop stops accepting messages and waits for the ones already queued to
+// be delivered.
+func (q *Queue) Stop() {
+	q.mu.Lock()
+	if !q.stopped {
+		q.stopped = true
+		close(q.messages)
+	}
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
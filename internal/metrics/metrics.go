@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"database/sql"
+	stdErrors "errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+)
+
+// Namespace prefixes the names of the metrics the app defines
+const Namespace = "integra_partners"
+
+// UnmatchedRoute is the route label of requests that matched no route,
+// so unknown paths don't each add a new series
+const UnmatchedRoute = "unmatched"
+
+// Registry holds every metric the app exposes, along with the Go runtime
+// and process metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests handled by route, method and status.",
+	}, []string{"route", "method", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to handle HTTP requests by route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	Errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "errors_total",
+		Help:      "Number of errors returned to clients by error code.",
+	}, []string{"code", "name"})
+
+	SecretReloadFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "secret_reload_failures_total",
+		Help:      "Number of failed reloads of secrets read from files, by secret.",
+	}, []string{"secret"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		Errors,
+		SecretReloadFailures,
+	)
+}
+
+// Handler returns the handler writing the metrics in the Registry in the
+// Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB adds the connection pool stats of the DB to the Registry,
+// labelled with the DB name.
+//
+// Returns an error if the stats of a DB with the same name are already
+// registered.
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveError counts an error with the code being returned to a client
+func ObserveError(code errors.ErrorCode) {
+	Errors.WithLabelValues(strconv.Itoa(int(code)), code.Name()).Inc()
+}
+
+// Middleware returns middleware counting and timing every request by the
+// route it matched, its method and the status written.
+//
+// Errors returned by the handler are passed to the HTTPErrorHandler first
+// so the status counted is the one the client received.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+
+			err := next(ctx)
+			if err != nil {
+				ctx.Error(err)
+			}
+
+			route := ctx.Path()
+			if route == "" || stdErrors.Is(err, echo.ErrNotFound) {
+				route = UnmatchedRoute
+			}
+			status := strconv.Itoa(ctx.Response().Status)
+
+			HTTPRequests.WithLabelValues(route, ctx.Request().Method, status).Inc()
+			HTTPRequestDuration.WithLabelValues(route, ctx.Request().Method, status).
+				Observe(time.Since(start).Seconds())
+
+			// The error has been handled, it is returned for the middleware
+			// before this one the same way Echo's request logger does
+			return err
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/metrics"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+)
+
+var _ = Describe("Metrics", func() {
+	var e *echo.Echo
+
+	serve := func(method string, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	BeforeEach(func() {
+		e = echo.New()
+		e.HTTPErrorHandler = response.NewHTTPErrorHandler(e, response.FormatDefault)
+		e.Use(metrics.Middleware())
+		e.GET("/users/:id", func(ctx echo.Context) error {
+			return ctx.String(http.StatusOK, "ok")
+		})
+		e.POST("/users", func(ctx echo.Context) error {
+			return errors.New(errors.UsersRepoUserDuplicateEmail, nil)
+		})
+		e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+	})
+
+	Describe("Middleware", func() {
+		It("should count and time requests by the route they matched", func() {
+			counter := metrics.HTTPRequests.WithLabelValues("/users/:id", http.MethodGet, "200")
+			before := testutil.ToFloat64(counter)
+
+			serve(http.MethodGet, "/users/1")
+			serve(http.MethodGet, "/users/2")
+
+			Expect(testutil.ToFloat64(counter)).To(Equal(before + 2))
+			Expect(testutil.CollectAndCount(metrics.HTTPRequestDuration)).To(BeNumerically(">", 0))
+		})
+
+		It("should count requests by the status the error handler wrote", func() {
+			counter := metrics.HTTPRequests.WithLabelValues("/users", http.MethodPost, "409")
+			before := testutil.ToFloat64(counter)
+
+			rec := serve(http.MethodPost, "/users")
+
+			Expect(rec.Code).To(Equal(http.StatusConflict))
+			Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+		})
+
+		It("should count requests that matched no route under one label", func() {
+			counter := metrics.HTTPRequests.WithLabelValues(metrics.UnmatchedRoute, http.MethodGet, "404")
+			before := testutil.ToFloat64(counter)
+
+			serve(http.MethodGet, "/unknown/path")
+
+			Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+		})
+	})
+
+	Describe("ObserveError", func() {
+		It("should count errors by their code and name", func() {
+			code := errors.UsersRepoUserDuplicateEmail
+			counter := metrics.Errors.WithLabelValues(strconv.Itoa(int(code)), code.Name())
+			before := testutil.ToFloat64(counter)
+
+			serve(http.MethodPost, "/users")
+
+			Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+		})
+	})
+
+	Describe("RegisterDB", func() {
+		It("should expose the pool stats of the DB", func() {
+			db, _, err := sqlmock.New()
+			Expect(err).To(BeNil())
+			defer db.Close()
+
+			Expect(metrics.RegisterDB(db, "metrics_test")).To(Succeed())
+
+			body := serve(http.MethodGet, "/metrics").Body.String()
+			Expect(body).To(ContainSubstring(`go_sql_open_connections{db_name="metrics_test"}`))
+			Expect(body).To(ContainSubstring(`go_sql_idle_connections{db_name="metrics_test"}`))
+			Expect(body).To(ContainSubstring(`go_sql_wait_count_total{db_name="metrics_test"}`))
+			Expect(body).To(ContainSubstring(`go_sql_wait_duration_seconds_total{db_name="metrics_test"}`))
+		})
+
+		It("should return an error when a DB with the same name is registered", func() {
+			db, _, err := sqlmock.New()
+			Expect(err).To(BeNil())
+			defer db.Close()
+
+			Expect(metrics.RegisterDB(db, "metrics_test_duplicate")).To(Succeed())
+			Expect(metrics.RegisterDB(db, "metrics_test_duplicate")).NotTo(Succeed())
+		})
+	})
+
+	Describe("Handler", func() {
+		It("should write the app metrics in the Prometheus text format", func() {
+			serve(http.MethodGet, "/users/1")
+
+			rec := serve(http.MethodGet, "/metrics")
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(ContainSubstring("integra_partners_http_requests_total"))
+			Expect(rec.Body.String()).To(ContainSubstring("integra_partners_http_request_duration_seconds_bucket"))
+			Expect(rec.Body.String()).To(ContainSubstring("go_goroutines"))
+		})
+	})
+})
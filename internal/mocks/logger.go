@@ -3,6 +3,8 @@ package mocks
 import (
 	"bytes"
 	"log/slog"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
 )
 
 type MockLogger struct {
@@ -15,7 +17,7 @@ func NewMockLogger() MockLogger {
 	var buff bytes.Buffer
 	return MockLogger{
 		buff: &buff,
-		Logger: slog.New(slog.NewJSONHandler(&buff, nil)),
+		Logger: logging.New(&buff, logging.FormatJSON),
 	}
 }
 
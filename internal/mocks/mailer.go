@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mailer"
+)
+
+type MockMailer struct {
+	mu   *sync.Mutex
+	sent *[]mailer.Message
+	// Error returned from Send when set
+	Err error
+	// How long Send takes, to mock a slow server
+	Delay time.Duration
+}
+
+// NewMockMailer creates and returns a new MockMailer for testing
+func NewMockMailer() MockMailer {
+	return MockMailer{
+		mu:   &sync.Mutex{},
+		sent: &[]mailer.Message{},
+	}
+}
+
+// Send records the message so tests can assert on it
+func (mockMailer MockMailer) Send(message mailer.Message) error {
+	time.Sleep(mockMailer.Delay)
+
+	mockMailer.mu.Lock()
+	defer mockMailer.mu.Unlock()
+
+	if mockMailer.Err != nil {
+		return mockMailer.Err
+	}
+
+	*mockMailer.sent = append(*mockMailer.sent, message)
+	return nil
+}
+
+// SentMessages returns all messages sent through the mock mailer
+func (mockMailer MockMailer) SentMessages() []mailer.Message {
+	mockMailer.mu.Lock()
+	defer mockMailer.mu.Unlock()
+
+	return append([]mailer.Message{}, *mockMailer.sent...)
+}
@@ -5,10 +5,11 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
-	errors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
 	models "github.com/jfavo/integra-partners-assessment-backend/internal/models"
 )
 
@@ -35,66 +36,270 @@ func (m *MockIRepo) EXPECT() *MockIRepoMockRecorder {
 	return m.recorder
 }
 
+// ApplyDueStatusChanges mocks base method.
+func (m *MockIRepo) ApplyDueStatusChanges(ctx context.Context, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyDueStatusChanges", ctx, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyDueStatusChanges indicates an expected call of ApplyDueStatusChanges.
+func (mr *MockIRepoMockRecorder) ApplyDueStatusChanges(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyDueStatusChanges", reflect.TypeOf((*MockIRepo)(nil).ApplyDueStatusChanges), ctx, now)
+}
+
+// CancelScheduledStatusChange mocks base method.
+func (m *MockIRepo) CancelScheduledStatusChange(ctx context.Context, changeId int, info models.AuditInfo) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledStatusChange", ctx, changeId, info)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelScheduledStatusChange indicates an expected call of CancelScheduledStatusChange.
+func (mr *MockIRepoMockRecorder) CancelScheduledStatusChange(ctx, changeId, info interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledStatusChange", reflect.TypeOf((*MockIRepo)(nil).CancelScheduledStatusChange), ctx, changeId, info)
+}
+
+// Close mocks base method.
+func (m *MockIRepo) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockIRepoMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIRepo)(nil).Close))
+}
+
+// CreatePasswordResetToken mocks base method.
+func (m *MockIRepo) CreatePasswordResetToken(ctx context.Context, email, tokenHash string, expiresAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordResetToken", ctx, email, tokenHash, expiresAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordResetToken indicates an expected call of CreatePasswordResetToken.
+func (mr *MockIRepoMockRecorder) CreatePasswordResetToken(ctx, email, tokenHash, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordResetToken", reflect.TypeOf((*MockIRepo)(nil).CreatePasswordResetToken), ctx, email, tokenHash, expiresAt)
+}
+
+// CreateScheduledStatusChange mocks base method.
+func (m *MockIRepo) CreateScheduledStatusChange(ctx context.Context, change models.ScheduledStatusChange, info models.AuditInfo) (*models.ScheduledStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledStatusChange", ctx, change, info)
+	ret0, _ := ret[0].(*models.ScheduledStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledStatusChange indicates an expected call of CreateScheduledStatusChange.
+func (mr *MockIRepoMockRecorder) CreateScheduledStatusChange(ctx, change, info interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledStatusChange", reflect.TypeOf((*MockIRepo)(nil).CreateScheduledStatusChange), ctx, change, info)
+}
+
 // CreateUser mocks base method.
-func (m *MockIRepo) CreateUser(arg0 models.User) (*models.User, errors.ErrorCode, error) {
+func (m *MockIRepo) CreateUser(ctx context.Context, user models.User, info models.AuditInfo) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0)
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user, info)
 	ret0, _ := ret[0].(*models.User)
-	ret1, _ := ret[1].(errors.ErrorCode)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateUser indicates an expected call of CreateUser.
-func (mr *MockIRepoMockRecorder) CreateUser(arg0 interface{}) *gomock.Call {
+func (mr *MockIRepoMockRecorder) CreateUser(ctx, user, info interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockIRepo)(nil).CreateUser), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockIRepo)(nil).CreateUser), ctx, user, info)
 }
 
 // DeleteUser mocks base method.
-func (m *MockIRepo) DeleteUser(userId int) (bool, errors.ErrorCode, error) {
+func (m *MockIRepo) DeleteUser(ctx context.Context, userId int, info models.AuditInfo) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", userId)
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, userId, info)
 	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(errors.ErrorCode)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockIRepoMockRecorder) DeleteUser(userId interface{}) *gomock.Call {
+func (mr *MockIRepoMockRecorder) DeleteUser(ctx, userId, info interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockIRepo)(nil).DeleteUser), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockIRepo)(nil).DeleteUser), ctx, userId, info)
 }
 
 // GetAllUsers mocks base method.
-func (m *MockIRepo) GetAllUsers() ([]models.User, errors.ErrorCode, error) {
+func (m *MockIRepo) GetAllUsers(ctx context.Context) ([]models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllUsers")
+	ret := m.ctrl.Call(m, "GetAllUsers", ctx)
 	ret0, _ := ret[0].([]models.User)
-	ret1, _ := ret[1].(errors.ErrorCode)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetAllUsers indicates an expected call of GetAllUsers.
-func (mr *MockIRepoMockRecorder) GetAllUsers() *gomock.Call {
+func (mr *MockIRepoMockRecorder) GetAllUsers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockIRepo)(nil).GetAllUsers), ctx)
+}
+
+// GetAllUsersAsOf mocks base method.
+func (m *MockIRepo) GetAllUsersAsOf(ctx context.Context, asOf time.Time) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllUsersAsOf", ctx, asOf)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllUsersAsOf indicates an expected call of GetAllUsersAsOf.
+func (mr *MockIRepoMockRecorder) GetAllUsersAsOf(ctx, asOf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsersAsOf", reflect.TypeOf((*MockIRepo)(nil).GetAllUsersAsOf), ctx, asOf)
+}
+
+// GetAuditEntries mocks base method.
+func (m *MockIRepo) GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEntries", ctx, filter)
+	ret0, _ := ret[0].([]models.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditEntries indicates an expected call of GetAuditEntries.
+func (mr *MockIRepoMockRecorder) GetAuditEntries(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEntries", reflect.TypeOf((*MockIRepo)(nil).GetAuditEntries), ctx, filter)
+}
+
+// GetPendingStatusChanges mocks base method.
+func (m *MockIRepo) GetPendingStatusChanges(ctx context.Context, userId int) ([]models.ScheduledStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingStatusChanges", ctx, userId)
+	ret0, _ := ret[0].([]models.ScheduledStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingStatusChanges indicates an expected call of GetPendingStatusChanges.
+func (mr *MockIRepoMockRecorder) GetPendingStatusChanges(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingStatusChanges", reflect.TypeOf((*MockIRepo)(nil).GetPendingStatusChanges), ctx, userId)
+}
+
+// GetUser mocks base method.
+func (m *MockIRepo) GetUser(ctx context.Context, userId int) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, userId)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockIRepoMockRecorder) GetUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockIRepo)(nil).GetUser), ctx, userId)
+}
+
+// GetUserAsOf mocks base method.
+func (m *MockIRepo) GetUserAsOf(ctx context.Context, userId int, asOf time.Time) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAsOf", ctx, userId, asOf)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAsOf indicates an expected call of GetUserAsOf.
+func (mr *MockIRepoMockRecorder) GetUserAsOf(ctx, userId, asOf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAsOf", reflect.TypeOf((*MockIRepo)(nil).GetUserAsOf), ctx, userId, asOf)
+}
+
+// GetUserHistory mocks base method.
+func (m *MockIRepo) GetUserHistory(ctx context.Context, userId int) ([]models.UserVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserHistory", ctx, userId)
+	ret0, _ := ret[0].([]models.UserVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserHistory indicates an expected call of GetUserHistory.
+func (mr *MockIRepoMockRecorder) GetUserHistory(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserHistory", reflect.TypeOf((*MockIRepo)(nil).GetUserHistory), ctx, userId)
+}
+
+// Ping mocks base method.
+func (m *MockIRepo) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockIRepoMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockIRepo)(nil).Ping), ctx)
+}
+
+// ResetPassword mocks base method.
+func (m *MockIRepo) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, tokenHash, passwordHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockIRepoMockRecorder) ResetPassword(ctx, tokenHash, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockIRepo)(nil).ResetPassword), ctx, tokenHash, passwordHash)
+}
+
+// SchemaChangeDeployed mocks base method.
+func (m *MockIRepo) SchemaChangeDeployed(ctx context.Context, change string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchemaChangeDeployed", ctx, change)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchemaChangeDeployed indicates an expected call of SchemaChangeDeployed.
+func (mr *MockIRepoMockRecorder) SchemaChangeDeployed(ctx, change interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockIRepo)(nil).GetAllUsers))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaChangeDeployed", reflect.TypeOf((*MockIRepo)(nil).SchemaChangeDeployed), ctx, change)
 }
 
 // UpdateUser mocks base method.
-func (m *MockIRepo) UpdateUser(arg0 models.User) (*models.User, errors.ErrorCode, error) {
+func (m *MockIRepo) UpdateUser(ctx context.Context, user models.User, info models.AuditInfo) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", arg0)
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, user, info)
 	ret0, _ := ret[0].(*models.User)
-	ret1, _ := ret[1].(errors.ErrorCode)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockIRepoMockRecorder) UpdateUser(arg0 interface{}) *gomock.Call {
+func (mr *MockIRepoMockRecorder) UpdateUser(ctx, user, info interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockIRepo)(nil).UpdateUser), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockIRepo)(nil).UpdateUser), ctx, user, info)
 }
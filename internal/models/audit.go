@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionCreate = "CREATE"
+	AuditActionUpdate = "UPDATE"
+	AuditActionDelete = "DELETE"
+
+	AuditActionScheduleStatusChange = "SCHEDULE_STATUS_CHANGE"
+	AuditActionCancelStatusChange   = "CANCEL_STATUS_CHANGE"
+)
+
+type AuditEntry struct {
+	AuditId   int             `db:"audit_id" json:"audit_id"`
+	Actor     string          `db:"actor" json:"actor"`
+	Action    string          `db:"action" json:"action"`
+	UserId    int             `db:"user_id" json:"user_id"`
+	Changes   json.RawMessage `db:"changes" json:"changes" swaggertype:"object"`
+	RequestId string          `db:"request_id" json:"request_id"`
+	Reason    string          `db:"reason" json:"reason"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// FieldChange is the before and after value of a single changed field
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditInfo identifies who made a change, the request it was made in and
+// optionally why it was made
+type AuditInfo struct {
+	Actor     string
+	RequestId string
+	Reason    string
+}
+
+// AuditFilter narrows the audit entries returned. Zero values are ignored.
+type AuditFilter struct {
+	UserId int
+	Actor  string
+	From   time.Time
+	To     time.Time
+	Limit  uint64
+}
@@ -0,0 +1,10 @@
+package models
+
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+type PasswordResetCompletion struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
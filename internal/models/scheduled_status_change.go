@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type ScheduledStatusChange struct {
+	ChangeId    int        `db:"change_id" json:"change_id"`
+	UserId      int        `db:"user_id" json:"user_id"`
+	UserStatus  string     `db:"user_status" json:"user_status"`
+	EffectiveAt time.Time  `db:"effective_at" json:"effective_at"`
+	Actor       string     `db:"actor" json:"actor"`
+	Reason      string     `db:"reason" json:"reason"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	AppliedAt   *time.Time `db:"applied_at" json:"applied_at"`
+	CancelledAt *time.Time `db:"cancelled_at" json:"cancelled_at"`
+}
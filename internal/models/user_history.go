@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// UserVersion is the state of a user over the time range it was valid for.
+type UserVersion struct {
+	User
+	// The change that created this version, one of the AuditAction values
+	Operation string    `db:"operation" json:"operation"`
+	ValidFrom time.Time `db:"valid_from" json:"valid_from"`
+	// Nil while this is the current version of the user
+	ValidTo *time.Time `db:"valid_to" json:"valid_to"`
+}
@@ -0,0 +1,58 @@
+package models
+
+const (
+	UserStatusInactive   = "I"
+	UserStatusActive     = "A"
+	UserStatusTerminated = "T"
+)
+
+// StatusTransitions maps a user status to the statuses it is allowed to
+// change to.
+type StatusTransitions map[string][]string
+
+// DefaultStatusTransitions lets users move freely between inactive and
+// active, while terminated users can never be changed back.
+var DefaultStatusTransitions = StatusTransitions{
+	UserStatusInactive: {UserStatusActive, UserStatusTerminated},
+	UserStatusActive:   {UserStatusInactive, UserStatusTerminated},
+}
+
+// Clone returns a copy of the transitions that can be changed without
+// changing t.
+func (t StatusTransitions) Clone() StatusTransitions {
+	if t == nil {
+		return nil
+	}
+
+	clone := make(StatusTransitions, len(t))
+	for from, to := range t {
+		clone[from] = append([]string(nil), to...)
+	}
+
+	return clone
+}
+
+// Allows returns true if a user is allowed to change from the from status
+// to the to status.
+//
+// Keeping the same status is always allowed, as is setting a status on a
+// user that does not have one yet.
+func (t StatusTransitions) Allows(from string, to string) bool {
+	if from == to || from == "" {
+		return true
+	}
+
+	for _, status := range t[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UserUpdate is the body for updating a user along with why it is changing
+type UserUpdate struct {
+	User
+	Reason string `json:"reason"`
+}
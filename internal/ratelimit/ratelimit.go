@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is an in-memory sliding window rate limiter keyed by
+// an arbitrary string (e.g. an email address).
+type Limiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	hits   map[string][]time.Time
+	// When keys with no hits left in the window were last removed
+	lastSweep time.Time
+}
+
+// NewLimiter creates a Limiter allowing limit hits per key within window.
+func NewLimiter(limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		limit:  limit,
+		window: window,
+		hits:   map[string][]time.Time{},
+
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow records a hit for the key and reports whether it is within the limit.
+//
+// Hits that are rejected are not recorded, so a key becomes available again
+// once its oldest allowed hit falls out of the window.
+//
+// Keys that have not been hit within the window are removed at most once
+// every window, so keys that are never seen again do not build up.
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-l.window)
+
+	if now.Sub(l.lastSweep) >= l.window {
+		l.sweep(cutoff)
+		l.lastSweep = now
+	}
+
+	kept := inWindow(l.hits[key], cutoff)
+	if len(kept) >= l.limit {
+		if len(kept) == 0 {
+			delete(l.hits, key)
+		} else {
+			l.hits[key] = kept
+		}
+		return false
+	}
+
+	l.hits[key] = append(kept, now)
+	return true
+}
+
+// Len returns the number of keys being tracked.
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.hits)
+}
+
+// SetLimit changes the limit and window, keeping the hits already recorded
+// so keys that are over the new limit stay limited.
+func (l *Limiter) SetLimit(limit int, window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = limit
+	l.window = window
+}
+
+// sweep removes every key without hits after the cutoff
+func (l *Limiter) sweep(cutoff time.Time) {
+	for key, hits := range l.hits {
+		if kept := inWindow(hits, cutoff); len(kept) > 0 {
+			l.hits[key] = kept
+		} else {
+			delete(l.hits, key)
+		}
+	}
+}
+
+// inWindow drops the hits that have fallen out of the window, reusing the
+// slice
+func inWindow(hits []time.Time, cutoff time.Time) []time.Time {
+	kept := hits[:0]
+	for _, hit := range hits {
+		if hit.After(cutoff) {
+			kept = append(kept, hit)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,80 @@
+package ratelimit_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/ratelimit"
+)
+
+var _ = Describe("RateLimit", func() {
+
+	Describe("Allow", func() {
+		It("should allow hits up to the limit for a key", func() {
+			limiter := ratelimit.NewLimiter(2, time.Minute)
+
+			Expect(limiter.Allow("test@user.com")).To(Equal(true))
+			Expect(limiter.Allow("test@user.com")).To(Equal(true))
+			Expect(limiter.Allow("test@user.com")).To(Equal(false))
+		})
+
+		It("should track each key separately", func() {
+			limiter := ratelimit.NewLimiter(1, time.Minute)
+
+			Expect(limiter.Allow("test@user.com")).To(Equal(true))
+			Expect(limiter.Allow("test2@user.com")).To(Equal(true))
+			Expect(limiter.Allow("test@user.com")).To(Equal(false))
+		})
+
+		It("should allow hits again once the window has passed", func() {
+			limiter := ratelimit.NewLimiter(1, 20*time.Millisecond)
+
+			Expect(limiter.Allow("test@user.com")).To(Equal(true))
+			Expect(limiter.Allow("test@user.com")).To(Equal(false))
+
+			time.Sleep(30 * time.Millisecond)
+
+			Expect(limiter.Allow("test@user.com")).To(Equal(true))
+		})
+	})
+
+	Describe("Len", func() {
+		It("should stop tracking keys once their hits have fallen out of the window", func() {
+			limiter := ratelimit.NewLimiter(1, 20*time.Millisecond)
+
+			Expect(limiter.Allow("test@user.com")).To(Equal(true))
+			Expect(limiter.Allow("test2@user.com")).To(Equal(true))
+			Expect(limiter.Len()).To(Equal(2))
+
+			time.Sleep(30 * time.Millisecond)
+
+			// Any key being hit removes the keys that haven't been
+			Expect(limiter.Allow("test3@user.com")).To(Equal(true))
+			Expect(limiter.Len()).To(Equal(1))
+		})
+
+		It("should not track keys that are never allowed", func() {
+			limiter := ratelimit.NewLimiter(0, time.Minute)
+
+			Expect(limiter.Allow("test@user.com")).To(Equal(false))
+			Expect(limiter.Len()).To(Equal(0))
+		})
+	})
+
+	Describe("SetLimit", func() {
+		It("should apply the new limit to the hits already recorded", func() {
+			limiter := ratelimit.NewLimiter(3, time.Minute)
+
+			Expect(limiter.Allow("test@user.com")).To(Equal(true))
+			Expect(limiter.Allow("test@user.com")).To(Equal(true))
+
+			limiter.SetLimit(2, time.Minute)
+			Expect(limiter.Allow("test@user.com")).To(Equal(false))
+
+			limiter.SetLimit(4, time.Minute)
+			Expect(limiter.Allow("test@user.com")).To(Equal(true))
+		})
+	})
+})
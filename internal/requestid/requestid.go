@@ -0,0 +1,67 @@
+// package requestid identifies each request so that everything done for it,
+// from log lines to audit entries, can be correlated.
+package requestid
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/random"
+)
+
+// maxLength is the longest incoming request id that will be honored
+const maxLength = 128
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the request id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request id carried by the context.
+//
+// Returns an empty string if the context has no request id.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Middleware assigns every request an id, stores it in the request's
+// context and echoes it in the X-Request-ID response header.
+//
+// An id passed by the client in the X-Request-ID header is honored if it is
+// valid, otherwise a new one is generated.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+
+			id := req.Header.Get(echo.HeaderXRequestID)
+			if !valid(id) {
+				id = random.String(32)
+			}
+
+			ctx.SetRequest(req.WithContext(NewContext(req.Context(), id)))
+			ctx.Response().Header().Set(echo.HeaderXRequestID, id)
+
+			return next(ctx)
+		}
+	}
+}
+
+// valid returns true if the id is not empty, not too long and only
+// contains visible ASCII characters, so it is safe to log and echo.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
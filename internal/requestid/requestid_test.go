@@ -0,0 +1,80 @@
+package requestid_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/requestid"
+)
+
+var _ = Describe("RequestId", func() {
+
+	var (
+		e   *echo.Echo
+		req *http.Request
+		rec *httptest.ResponseRecorder
+
+		// contextId is the request id the handler found in the context
+		contextId string
+	)
+
+	BeforeEach(func() {
+		e = echo.New()
+		e.Use(requestid.Middleware())
+		e.GET("/", func(ctx echo.Context) error {
+			contextId = requestid.FromContext(ctx.Request().Context())
+			return ctx.NoContent(http.StatusOK)
+		})
+
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		rec = httptest.NewRecorder()
+		contextId = ""
+	})
+
+	It("should honor the request id passed by the client", func() {
+		req.Header.Set(echo.HeaderXRequestID, "request-1")
+		e.ServeHTTP(rec, req)
+
+		Expect(contextId).To(Equal("request-1"))
+		Expect(rec.Header().Get(echo.HeaderXRequestID)).To(Equal("request-1"))
+	})
+
+	It("should generate a request id when none is passed", func() {
+		e.ServeHTTP(rec, req)
+
+		Expect(contextId).NotTo(BeEmpty())
+		Expect(rec.Header().Get(echo.HeaderXRequestID)).To(Equal(contextId))
+	})
+
+	It("should generate a different request id for each request", func() {
+		e.ServeHTTP(rec, req)
+		first := contextId
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		Expect(contextId).NotTo(Equal(first))
+	})
+
+	DescribeTable("should replace an invalid request id",
+		func(id string) {
+			req.Header.Set(echo.HeaderXRequestID, id)
+			e.ServeHTTP(rec, req)
+
+			Expect(contextId).NotTo(Equal(id))
+			Expect(contextId).NotTo(BeEmpty())
+			Expect(rec.Header().Get(echo.HeaderXRequestID)).To(Equal(contextId))
+		},
+		Entry("too long", strings.Repeat("a", 129)),
+		Entry("with spaces", "request 1"),
+		Entry("with control characters", "request-1\x1b[31m"),
+	)
+
+	It("should return an empty id for a context without one", func() {
+		Expect(requestid.FromContext(req.Context())).To(BeEmpty())
+	})
+})
@@ -0,0 +1,45 @@
+package response
+
+import (
+	"errors"
+
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+// NewHTTPErrorHandler returns the handler writing the errors returned by
+// routes to the client.
+//
+// An errors.Error is logged with its cause, counted by its code and
+// written with its status, code and message, along with any fields that
+// failed validation, in the locale the client accepts. Errors are written
+// in the format, FormatDefault or FormatProblem, unless the client asks for
+// a problem document. Any other error is left to Echo's default handler.
+func NewHTTPErrorHandler(e *echo.Echo, format string) echo.HTTPErrorHandler {
+	return func(err error, ctx echo.Context) {
+		if ctx.Response().Committed {
+			return
+		}
+
+		var appErr *ipErrors.Error
+		if !errors.As(err, &appErr) {
+			e.DefaultHTTPErrorHandler(err, ctx)
+			return
+		}
+
+		logging.ErrorWithCodeContext(ctx.Request().Context(), appErr.Code, appErr.Message, appErr.Err)
+		metrics.ObserveError(appErr.Code)
+
+		locale := Locale(ctx.Request())
+		res := Failure(appErr.Code, appErr.LocalizedMessage(locale))
+		if fieldErrs := appErr.LocalizedFields(locale); len(fieldErrs) > 0 {
+			res = ValidationFailure(appErr.Code, appErr.LocalizedMessage(locale), fieldErrs)
+		}
+
+		if err := Error(ctx, appErr.Status, res, format); err != nil {
+			logging.ErrorContext(ctx.Request().Context(), "HTTPErrorHandler", "failed to write error response", err)
+		}
+	}
+}
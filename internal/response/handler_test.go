@@ -0,0 +1,131 @@
+package response_test
+
+import (
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/requestid"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+)
+
+var _ = Describe("HTTPErrorHandler", func() {
+
+	var (
+		mockLogger mocks.MockLogger
+		e          *echo.Echo
+		ctx        echo.Context
+		rec        *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		mockLogger = mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+
+		e = echo.New()
+		e.HTTPErrorHandler = response.NewHTTPErrorHandler(e, response.FormatDefault)
+
+		rec = httptest.NewRecorder()
+		ctx = e.NewContext(httptest.NewRequest(http.MethodGet, "/users", nil), rec)
+	})
+
+	It("should write the status, code and message of the error", func() {
+		code := errors.UsersRepoUserDuplicateEmail
+		e.HTTPErrorHandler(errors.New(code, stdErrors.New("duplicate key value")), ctx)
+
+		var body response.Response
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusConflict))
+		Expect(body).To(Equal(response.Failure(code, errors.GetErrorMessage(code, errors.DefaultLocale))))
+	})
+
+	It("should log the cause without writing it to the client", func() {
+		e.HTTPErrorHandler(errors.New(errors.UsersRepoGetAllUsersDBQueryFail, stdErrors.New("connection refused")), ctx)
+
+		Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		Expect(rec.Body.String()).NotTo(ContainSubstring("connection refused"))
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring("connection refused"))
+	})
+
+	It("should log the request id of the request", func() {
+		req := ctx.Request()
+		ctx.SetRequest(req.WithContext(requestid.NewContext(req.Context(), "request-1")))
+		e.HTTPErrorHandler(errors.New(errors.UsersRepoGetAllUsersDBQueryFail, nil), ctx)
+
+		Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"request_id":"request-1"`))
+	})
+
+	It("should write the fields that failed validation", func() {
+		code := errors.UsersControllerUserValidationFailed
+		fieldErrs := []errors.FieldError{
+			errors.NewFieldError("email", errors.FieldRequired),
+		}
+		e.HTTPErrorHandler(errors.New(code, nil).WithFields(fieldErrs), ctx)
+
+		var body response.Response
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(Equal(response.ValidationFailure(code, errors.GetErrorMessage(code, errors.DefaultLocale), fieldErrs)))
+	})
+
+	It("should write the message in the locale the client accepts", func() {
+		code := errors.UsersControllerUserFailedToBindBody
+		ctx.Request().Header.Set(response.HeaderAcceptLanguage, "es-MX,es;q=0.9,en;q=0.8")
+		e.HTTPErrorHandler(errors.New(code, nil).WithDetail("unexpected EOF"), ctx)
+
+		var body response.Response
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(rec.Header().Get(response.HeaderContentLanguage)).To(Equal(string(errors.LocaleSpanish)))
+		Expect(body.ErrorMessage).To(Equal(errors.GetErrorMessage(code, errors.LocaleSpanish) + ". unexpected EOF"))
+	})
+
+	It("should write the fields that failed validation in the locale the client accepts", func() {
+		ctx.Request().Header.Set(response.HeaderAcceptLanguage, "es")
+		e.HTTPErrorHandler(errors.New(errors.UsersControllerUserValidationFailed, nil).WithFields([]errors.FieldError{
+			errors.NewFieldError("email", errors.FieldRequired),
+			errors.NewFieldError("user_name", errors.FieldTooLong, 50),
+		}), ctx)
+
+		var body response.Response
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+		Expect(body.Errors).To(HaveLen(2))
+		Expect(body.Errors[0].Code).To(Equal(errors.FieldRequired))
+		Expect(body.Errors[0].Message).To(Equal("es obligatorio"))
+		Expect(body.Errors[1].Code).To(Equal(errors.FieldTooLong))
+		Expect(body.Errors[1].Message).To(Equal("debe tener como máximo 50 caracteres"))
+	})
+
+	It("should write problem documents when configured to", func() {
+		e.HTTPErrorHandler = response.NewHTTPErrorHandler(e, response.FormatProblem)
+		e.HTTPErrorHandler(errors.New(errors.UsersControllerInvalidUserIdParam, nil), ctx)
+
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(rec.Header().Get(echo.HeaderContentType)).To(Equal(response.MIMEApplicationProblemJSON))
+	})
+
+	It("should find the error when it is wrapped", func() {
+		code := errors.UsersControllerInvalidUserIdParam
+		e.HTTPErrorHandler(fmt.Errorf("handler: %w", errors.New(code, nil)), ctx)
+
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should leave other errors to Echo's default handler", func() {
+		e.HTTPErrorHandler(echo.ErrNotFound, ctx)
+
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,101 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// FormatDefault renders errors as a Response
+	FormatDefault = "default"
+	// FormatProblem renders errors as RFC 7807 problem documents
+	FormatProblem = "problem"
+
+	MIMEApplicationProblemJSON = "application/problem+json"
+
+	HeaderAcceptLanguage  = "Accept-Language"
+	HeaderContentLanguage = "Content-Language"
+
+	// ProblemTypePrefix is prefixed to an error code to create the
+	// problem type URI identifying it
+	ProblemTypePrefix = "urn:integra-partners:problem:"
+)
+
+// Problem is an RFC 7807 problem document, extended with the error code and
+// any fields that failed validation.
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Code     errors.ErrorCode    `json:"code"`
+	Errors   []errors.FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem creates a problem document for the failed Response.
+//
+// The title is the message mapped to the error code in the locale, while the
+// detail is the message of the Response, which may describe this occurrence further.
+func NewProblem(status int, res Response, instance string, locale errors.Locale) Problem {
+	return Problem{
+		Type:     ProblemType(res.ErrorCode),
+		Title:    errors.GetErrorMessage(res.ErrorCode, locale),
+		Status:   status,
+		Detail:   res.ErrorMessage,
+		Instance: instance,
+		Code:     res.ErrorCode,
+		Errors:   res.Errors,
+	}
+}
+
+// ProblemType returns the problem type URI for the error code
+func ProblemType(code errors.ErrorCode) string {
+	return fmt.Sprintf("%s%d", ProblemTypePrefix, code)
+}
+
+// Locale returns the locale the client accepts error messages in.
+func Locale(req *http.Request) errors.Locale {
+	return errors.MatchLocale(req.Header.Get(HeaderAcceptLanguage))
+}
+
+// Error writes the failed Response to the client with the status code.
+//
+// The Response is written as a problem document if the client accepts
+// application/problem+json or format is FormatProblem, otherwise it is
+// written as is.
+func Error(ctx echo.Context, status int, res Response, format string) error {
+	locale := Locale(ctx.Request())
+	ctx.Response().Header().Set(HeaderContentLanguage, string(locale))
+
+	if !wantsProblem(ctx.Request(), format) {
+		return ctx.JSON(status, res)
+	}
+
+	// The request id is used as the instance so problems can be
+	// traced back to the request that caused them
+	instance := ctx.Response().Header().Get(echo.HeaderXRequestID)
+	if instance == "" {
+		instance = ctx.Request().Header.Get(echo.HeaderXRequestID)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	return ctx.JSON(status, NewProblem(status, res, instance, locale))
+}
+
+// wantsProblem returns true if errors for the request should be written
+// as problem documents, when the format is used by default.
+func wantsProblem(req *http.Request, format string) bool {
+	for _, accept := range strings.Split(req.Header.Get(echo.HeaderAccept), ",") {
+		mediaType := strings.TrimSpace(strings.Split(accept, ";")[0])
+		if strings.EqualFold(mediaType, MIMEApplicationProblemJSON) {
+			return true
+		}
+	}
+
+	return format == FormatProblem
+}
@@ -0,0 +1,119 @@
+package response_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+)
+
+var _ = Describe("Problem", func() {
+
+	Describe("NewProblem", func() {
+		It("should map the error code to a problem document", func() {
+			code := errors.UsersControllerUserFailedToBindBody
+			res := response.Failure(code, "user input body is invalid. unexpected EOF")
+
+			Expect(response.NewProblem(http.StatusBadRequest, res, "request-1", errors.DefaultLocale)).To(Equal(response.Problem{
+				Type:     fmt.Sprintf("urn:integra-partners:problem:%d", code),
+				Title:    errors.GetErrorMessage(code, errors.DefaultLocale),
+				Status:   http.StatusBadRequest,
+				Detail:   "user input body is invalid. unexpected EOF",
+				Instance: "request-1",
+				Code:     code,
+			}))
+		})
+
+		It("should keep the fields that failed validation", func() {
+			code := errors.UsersControllerUserValidationFailed
+			fieldErrs := []errors.FieldError{
+				errors.NewFieldError("email", errors.FieldRequired),
+			}
+			res := response.ValidationFailure(code, errors.GetErrorMessage(code, errors.DefaultLocale), fieldErrs)
+
+			Expect(response.NewProblem(http.StatusUnprocessableEntity, res, "", errors.DefaultLocale).Errors).To(Equal(fieldErrs))
+		})
+	})
+
+	Describe("Error", func() {
+		var (
+			e   *echo.Echo
+			req *http.Request
+			rec *httptest.ResponseRecorder
+			res response.Response
+		)
+
+		BeforeEach(func() {
+			e = echo.New()
+			req = httptest.NewRequest(http.MethodGet, "/users", nil)
+			rec = httptest.NewRecorder()
+
+			code := errors.UsersRepoGetAllUsersDBQueryFail
+			res = response.Failure(code, errors.GetErrorMessage(code, errors.DefaultLocale))
+		})
+
+		It("should write the Response by default", func() {
+			Expect(response.Error(e.NewContext(req, rec), http.StatusInternalServerError, res, response.FormatDefault)).To(Succeed())
+
+			var body response.Response
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			Expect(rec.Header().Get(echo.HeaderContentType)).To(Equal(echo.MIMEApplicationJSON))
+			Expect(body).To(Equal(res))
+		})
+
+		It("should write a problem document when the client accepts one", func() {
+			req.Header.Set(echo.HeaderAccept, "application/json;q=0.9, application/problem+json")
+			req.Header.Set(echo.HeaderXRequestID, "request-1")
+
+			Expect(response.Error(e.NewContext(req, rec), http.StatusInternalServerError, res, response.FormatDefault)).To(Succeed())
+
+			var body response.Problem
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			Expect(rec.Header().Get(echo.HeaderContentType)).To(Equal(response.MIMEApplicationProblemJSON))
+			Expect(body).To(Equal(response.NewProblem(http.StatusInternalServerError, res, "request-1", errors.DefaultLocale)))
+		})
+
+		It("should write a problem document when configured to", func() {
+			Expect(response.Error(e.NewContext(req, rec), http.StatusInternalServerError, res, response.FormatProblem)).To(Succeed())
+
+			Expect(rec.Header().Get(echo.HeaderContentType)).To(Equal(response.MIMEApplicationProblemJSON))
+		})
+
+		It("should prefer the request id set on the response", func() {
+			req.Header.Set(echo.HeaderAccept, response.MIMEApplicationProblemJSON)
+			req.Header.Set(echo.HeaderXRequestID, "client-id")
+			rec.Header().Set(echo.HeaderXRequestID, "server-id")
+
+			Expect(response.Error(e.NewContext(req, rec), http.StatusInternalServerError, res, response.FormatDefault)).To(Succeed())
+
+			var body response.Problem
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+			Expect(body.Instance).To(Equal("server-id"))
+		})
+
+		It("should write the title in the locale the client accepts", func() {
+			req.Header.Set(echo.HeaderAccept, response.MIMEApplicationProblemJSON)
+			req.Header.Set(response.HeaderAcceptLanguage, "es")
+
+			Expect(response.Error(e.NewContext(req, rec), http.StatusInternalServerError, res, response.FormatDefault)).To(Succeed())
+
+			var body response.Problem
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+
+			Expect(rec.Header().Get(response.HeaderContentLanguage)).To(Equal(string(errors.LocaleSpanish)))
+			Expect(body.Title).To(Equal(errors.GetErrorMessage(res.ErrorCode, errors.LocaleSpanish)))
+		})
+	})
+})
@@ -1,11 +1,14 @@
 package response
 
-import "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+import (
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+)
 
 type Response struct {
-	Data         interface{}      `json:"data,omitempty"`
-	ErrorCode    errors.ErrorCode `json:"error_code,omitempty"`
-	ErrorMessage string           `json:"error_message,omitempty"`
+	Data         interface{}         `json:"data,omitempty"`
+	ErrorCode    errors.ErrorCode    `json:"error_code,omitempty"`
+	ErrorMessage string              `json:"error_message,omitempty"`
+	Errors       []errors.FieldError `json:"errors,omitempty"`
 }
 
 // Success returns a successful response object to the user containing
@@ -24,3 +27,14 @@ func Failure(errCode errors.ErrorCode, errMessage string) Response {
 		ErrorMessage: errMessage,
 	}
 }
+
+// ValidationFailure returns a non-successful response object to the user
+// containing the error code and message along with each field that failed
+// validation.
+func ValidationFailure(errCode errors.ErrorCode, errMessage string, fieldErrors []errors.FieldError) Response {
+	return Response{
+		ErrorCode:    errCode,
+		ErrorMessage: errMessage,
+		Errors:       fieldErrors,
+	}
+}
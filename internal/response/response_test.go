@@ -23,7 +23,7 @@ var _ = Describe("Response", func() {
 	Describe("Failure", func() {
 		It("Should return Response object with data", func() {
 			code := errors.UsersControllerInvalidUserIdParam
-			errMsg := errors.GetErrorMessage(errors.UsersControllerInvalidUserIdParam)
+			errMsg := errors.GetErrorMessage(errors.UsersControllerInvalidUserIdParam, errors.DefaultLocale)
 			expected := response.Response{
 				ErrorCode:    code,
 				ErrorMessage: errMsg,
@@ -32,4 +32,21 @@ var _ = Describe("Response", func() {
 			Expect(response.Failure(code, errMsg)).To(Equal(expected))
 		})
 	})
+
+	Describe("ValidationFailure", func() {
+		It("Should return Response object with the field errors", func() {
+			code := errors.UsersControllerUserValidationFailed
+			errMsg := errors.GetErrorMessage(code, errors.DefaultLocale)
+			fieldErrs := []errors.FieldError{
+				errors.NewFieldError("email", errors.FieldRequired),
+			}
+			expected := response.Response{
+				ErrorCode:    code,
+				ErrorMessage: errMsg,
+				Errors:       fieldErrs,
+			}
+
+			Expect(response.ValidationFailure(code, errMsg, fieldErrs)).To(Equal(expected))
+		})
+	})
 })
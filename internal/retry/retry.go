@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+)
+
+// Backoff configures how long to wait between attempts. The wait doubles
+// after every attempt from Initial up to Max, with jitter so instances
+// retrying together spread out.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	// How long to keep retrying for before giving up. 0 retries until the
+	// context is done.
+	MaxWait time.Duration
+}
+
+// Delay returns how long to wait after the attempt, counting from 1.
+//
+// The delay is picked at random from the upper half of the attempt's
+// backoff, so it never drops below half of it.
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Initial
+	for i := 1; i < attempt && delay < b.Max; i++ {
+		delay *= 2
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+
+	if delay <= 1 {
+		return delay
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// Do calls fn until it succeeds, waiting between attempts as configured
+// by the backoff. Each failed attempt is logged as a warning with the
+// name of the operation being retried.
+//
+// Returns the last error if the backoff's MaxWait would be exceeded or the
+// context is done before fn succeeds.
+func Do(ctx context.Context, name string, backoff Backoff, fn func(ctx context.Context) error) error {
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		delay := backoff.Delay(attempt)
+		if backoff.MaxWait > 0 && time.Since(start)+delay > backoff.MaxWait {
+			return fmt.Errorf("%s: gave up after %d attempts: %w", name, attempt, err)
+		}
+
+		logging.Logger.WarnContext(ctx, "Attempt failed, retrying",
+			"operation", name,
+			"attempt", attempt,
+			"retry_in_ms", delay.Milliseconds(),
+			logging.Err(err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%s: stopped after %d attempts: %w", name, attempt, err)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/retry"
+)
+
+var _ = Describe("Retry", func() {
+	var mockLogger mocks.MockLogger
+
+	BeforeEach(func() {
+		mockLogger = mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+	})
+
+	Describe("Delay", func() {
+		backoff := retry.Backoff{Initial: 100 * time.Millisecond, Max: time.Second}
+
+		DescribeTable("should double the delay every attempt up to the max, with jitter",
+			func(attempt int, expected time.Duration) {
+				for i := 0; i < 50; i++ {
+					delay := backoff.Delay(attempt)
+
+					Expect(delay).To(BeNumerically(">=", expected/2))
+					Expect(delay).To(BeNumerically("<=", expected))
+				}
+			},
+			Entry("first attempt", 1, 100*time.Millisecond),
+			Entry("second attempt", 2, 200*time.Millisecond),
+			Entry("fourth attempt", 4, 800*time.Millisecond),
+			Entry("capped at the max", 5, time.Second),
+			Entry("far past the max", 100, time.Second),
+		)
+	})
+
+	Describe("Do", func() {
+		backoff := retry.Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond}
+		failure := errors.New("connection refused")
+
+		It("should retry until the call succeeds, logging each failed attempt", func() {
+			attempts := 0
+
+			err := retry.Do(context.Background(), "connect", backoff, func(context.Context) error {
+				attempts++
+				if attempts < 3 {
+					return failure
+				}
+				return nil
+			})
+
+			Expect(err).To(BeNil())
+			Expect(attempts).To(Equal(3))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"operation":"connect","attempt":1`))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"operation":"connect","attempt":2`))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring("connection refused"))
+		})
+
+		It("should give up with the last error once the max wait would be exceeded", func() {
+			backoff := backoff
+			backoff.MaxWait = 20 * time.Millisecond
+			attempts := 0
+
+			start := time.Now()
+			err := retry.Do(context.Background(), "connect", backoff, func(context.Context) error {
+				attempts++
+				return failure
+			})
+
+			Expect(err).To(MatchError(failure))
+			Expect(err.Error()).To(ContainSubstring("gave up"))
+			Expect(attempts).To(BeNumerically(">", 1))
+			// Timers can fire a little late, but it never waits out another delay
+			Expect(time.Since(start)).To(BeNumerically("<", backoff.MaxWait+backoff.Max))
+		})
+
+		It("should stop retrying once the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			attempts := 0
+
+			err := retry.Do(ctx, "connect", retry.Backoff{Initial: time.Hour}, func(context.Context) error {
+				attempts++
+				cancel()
+				return failure
+			})
+
+			Expect(err).To(MatchError(failure))
+			Expect(attempts).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/database"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+)
+
+// Scheduler periodically applies scheduled status changes that have
+// taken effect.
+type Scheduler struct {
+	Repo     database.Repo
+	Interval time.Duration
+
+	stop   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a Scheduler that checks for due status changes every interval.
+func New(repo database.Repo, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		Repo:     repo,
+		Interval: interval,
+	}
+}
+
+// Start runs the scheduler in the background until Stop is called.
+//
+// Due changes are applied straight away, then again every interval.
+func (s *Scheduler) Start() {
+	s.stop = make(chan struct{})
+
+	// Cancelled on Stop so a run in progress does not hold it up
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+
+		for {
+			s.RunOnce(ctx, time.Now())
+
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduler and waits for any run in progress to finish.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	close(s.stop)
+	s.wg.Wait()
+}
+
+// RunOnce applies every status change that has taken effect by now.
+//
+// Stops early if the context is cancelled.
+// Returns the number of changes applied.
+func (s *Scheduler) RunOnce(ctx context.Context, now time.Time) int {
+	applied, err := s.Repo.ApplyDueStatusChanges(ctx, now)
+	if err != nil {
+		logging.ErrorContext(ctx, "RunOnce", "failed to apply scheduled status changes", err)
+		return 0
+	}
+
+	if applied > 0 {
+		logging.Logger.Info("Applied scheduled status changes", "applied", applied)
+	}
+
+	return applied
+}
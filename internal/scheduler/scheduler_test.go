@@ -0,0 +1,66 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/scheduler"
+)
+
+var _ = Describe("Scheduler", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockRepo   *mocks.MockIRepo
+		mockLogger mocks.MockLogger
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockIRepo(mockCtrl)
+		mockLogger = mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+	})
+
+	Describe("RunOnce", func() {
+		It("should apply the changes due at the time", func() {
+			now := time.Now()
+			mockRepo.EXPECT().ApplyDueStatusChanges(gomock.Any(), now).Return(2, nil)
+
+			applied := scheduler.New(mockRepo, time.Minute).RunOnce(context.Background(), now)
+
+			Expect(applied).To(Equal(2))
+		})
+
+		It("should log when applying the changes fails", func() {
+			code := ipErrors.StatusChangesRepoApplyDBQueryFail
+			mockRepo.EXPECT().ApplyDueStatusChanges(gomock.Any(), gomock.Any()).Return(0, ipErrors.New(code, errors.New("DB error occurred!")))
+
+			applied := scheduler.New(mockRepo, time.Minute).RunOnce(context.Background(), time.Now())
+
+			Expect(applied).To(Equal(0))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring("DB error occurred!"))
+		})
+	})
+
+	Describe("Start", func() {
+		It("should apply due changes every interval until stopped", func() {
+			mockRepo.EXPECT().
+				ApplyDueStatusChanges(gomock.Any(), gomock.Any()).
+				Return(0, nil).
+				MinTimes(2)
+
+			s := scheduler.New(mockRepo, 10*time.Millisecond)
+			s.Start()
+			time.Sleep(35 * time.Millisecond)
+			s.Stop()
+		})
+	})
+})
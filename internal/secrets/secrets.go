@@ -0,0 +1,130 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/metrics"
+)
+
+// Provider returns the current value of a secret from wherever it is
+// kept, e.g. a file mounted from a secret store.
+type Provider interface {
+	Secret(ctx context.Context) (string, error)
+}
+
+// File provides the secret kept in a file, such as a Docker or Kubernetes
+// secret. The trailing newline most editors add is dropped.
+type File struct {
+	Path string
+}
+
+func (f File) Secret(context.Context) (string, error) {
+	contents, err := os.ReadFile(f.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+
+	return strings.TrimRight(string(contents), "\r\n"), nil
+}
+
+// Reloader keeps the latest value of a secret from its provider, reloading
+// it every interval so a rotated secret is picked up without a restart.
+//
+// If reloading fails, the last value loaded is kept.
+type Reloader struct {
+	// Name of the secret in logs
+	Name     string
+	Provider Provider
+	Interval time.Duration
+
+	value  atomic.Pointer[string]
+	stop   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewReloader creates a Reloader for the secret from the provider that
+// reloads it every interval once started.
+func NewReloader(name string, provider Provider, interval time.Duration) *Reloader {
+	return &Reloader{
+		Name:     name,
+		Provider: provider,
+		Interval: interval,
+	}
+}
+
+// Load loads the secret from its provider, logging when its value changes.
+//
+// Returns an error if the provider fails, keeping the last value loaded.
+func (r *Reloader) Load(ctx context.Context) error {
+	secret, err := r.Provider.Secret(ctx)
+	if err != nil {
+		return err
+	}
+
+	previous := r.value.Swap(&secret)
+	if previous != nil && *previous != secret {
+		logging.Logger.InfoContext(ctx, "Secret rotated", "secret", r.Name)
+	}
+
+	return nil
+}
+
+// Value returns the last value of the secret loaded.
+func (r *Reloader) Value() string {
+	if value := r.value.Load(); value != nil {
+		return *value
+	}
+
+	return ""
+}
+
+// Start reloads the secret in the background every interval until Stop is
+// called.
+func (r *Reloader) Start() {
+	r.stop = make(chan struct{})
+
+	// Cancelled on Stop so a reload in progress does not hold it up
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.Load(ctx); err != nil {
+					metrics.SecretReloadFailures.WithLabelValues(r.Name).Inc()
+					logging.ErrorContext(ctx, "Reloader", "failed to reload secret, keeping the last value", err)
+				}
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops reloading the secret and waits for any reload in progress to
+// finish. It does nothing if the reloader was never started.
+func (r *Reloader) Stop() {
+	if r.stop == nil {
+		return
+	}
+
+	r.cancel()
+	close(r.stop)
+	r.wg.Wait()
+}
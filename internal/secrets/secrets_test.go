@@ -0,0 +1,104 @@
+package secrets_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/logging"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/metrics"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/mocks"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/secrets"
+)
+
+var _ = Describe("Secrets", func() {
+	var (
+		mockLogger mocks.MockLogger
+		path       string
+	)
+
+	// writeSecret writes the secret to the file at path
+	writeSecret := func(secret string) {
+		Expect(os.WriteFile(path, []byte(secret), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		mockLogger = mocks.NewMockLogger()
+		logging.Logger = mockLogger.Logger
+
+		path = filepath.Join(GinkgoT().TempDir(), "db_password")
+	})
+
+	Describe("File", func() {
+		It("should return the contents of the file without the trailing newline", func() {
+			writeSecret("s3cret \n")
+
+			secret, err := secrets.File{Path: path}.Secret(context.Background())
+
+			Expect(err).To(BeNil())
+			Expect(secret).To(Equal("s3cret "))
+		})
+
+		It("should return an error if the file can't be read", func() {
+			_, err := secrets.File{Path: path}.Secret(context.Background())
+
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+	})
+
+	Describe("Reloader", func() {
+		var reloader *secrets.Reloader
+
+		BeforeEach(func() {
+			writeSecret("first")
+			reloader = secrets.NewReloader("POSTGRES_PASSWORD_FILE", secrets.File{Path: path}, 10*time.Millisecond)
+			Expect(reloader.Load(context.Background())).To(Succeed())
+		})
+
+		It("should return the loaded secret", func() {
+			Expect(reloader.Value()).To(Equal("first"))
+			Expect(mockLogger.GetBufferValue()).To(BeEmpty())
+		})
+
+		It("should pick up the rotated secret every interval until stopped", func() {
+			reloader.Start()
+			writeSecret("second")
+
+			Eventually(reloader.Value).Should(Equal("second"))
+			reloader.Stop()
+
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring(`"msg":"Secret rotated","secret":"POSTGRES_PASSWORD_FILE"`))
+			Expect(mockLogger.GetBufferValue()).NotTo(ContainSubstring("second"))
+		})
+
+		It("should keep the last secret if reloading fails", func() {
+			Expect(os.Remove(path)).To(Succeed())
+
+			Expect(reloader.Load(context.Background())).NotTo(Succeed())
+			Expect(reloader.Value()).To(Equal("first"))
+		})
+
+		It("should count failed reloads", func() {
+			failures := metrics.SecretReloadFailures.WithLabelValues("POSTGRES_PASSWORD_FILE")
+			before := testutil.ToFloat64(failures)
+			Expect(os.Remove(path)).To(Succeed())
+
+			reloader.Start()
+			Eventually(func() float64 { return testutil.ToFloat64(failures) }).Should(BeNumerically(">", before))
+			reloader.Stop()
+
+			Expect(reloader.Value()).To(Equal("first"))
+			Expect(mockLogger.GetBufferValue()).To(ContainSubstring("failed to reload secret"))
+		})
+
+		It("should do nothing when stopped without being started", func() {
+			Expect(reloader.Stop).NotTo(Panic())
+		})
+	})
+})
@@ -0,0 +1,161 @@
+package tracing
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+)
+
+// Exporters spans can be sent to
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// InstrumentationName names the tracer the app's spans are created with
+const InstrumentationName = "github.com/jfavo/integra-partners-assessment-backend"
+
+// ErrorCodeKey is the attribute key spans carry an error code under
+const ErrorCodeKey = attribute.Key("error_code")
+
+// Tracer returns the tracer the app's spans are created with, from the
+// provider set by Setup
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// Setup sets the W3C trace context propagator and a tracer provider
+// exporting spans to the exporter in the config.
+//
+// No provider is set for the "none" exporter, so spans are not recorded.
+// Returns a func that flushes the spans not yet exported and closes the
+// exporter, or an error if the exporter is not known or fails to start.
+func Setup(ctx context.Context, tracingConfig config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var file *os.File
+	var err error
+
+	switch tracingConfig.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	case ExporterFile:
+		file, err = os.OpenFile(tracingConfig.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if tracingConfig.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(tracingConfig.OTLPEndpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", tracingConfig.Exporter)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(tracingConfig.ServiceName))))
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			err = stdErrors.Join(err, file.Close())
+		}
+
+		return err
+	}, nil
+}
+
+// RecordError records the error on the span, along with its code and name
+// if it is an errors.Error.
+func RecordError(span trace.Span, err error) {
+	span.RecordError(err)
+
+	var appErr *errors.Error
+	if stdErrors.As(err, &appErr) {
+		span.SetAttributes(
+			ErrorCodeKey.Int(int(appErr.Code)),
+			semconv.ErrorTypeKey.String(appErr.Code.Name()))
+	}
+}
+
+// Middleware returns middleware starting a server span for every request,
+// continuing the trace in the request's W3C trace context headers if any.
+//
+// The span's context is set on the request so spans started while
+// handling it, e.g. for DB queries, are its children. Errors returned by
+// the handler are passed to the HTTPErrorHandler first so the status
+// recorded is the one the client received.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			parent := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			// Span names are kept to the route so they don't grow with
+			// every id requested
+			name := req.Method
+			if route := ctx.Path(); route != "" {
+				name = fmt.Sprintf("%s %s", req.Method, route)
+			}
+
+			spanCtx, span := Tracer().Start(parent, name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.HTTPRoute(ctx.Path()),
+					semconv.URLPath(req.URL.Path)))
+			defer span.End()
+
+			ctx.SetRequest(req.WithContext(spanCtx))
+
+			err := next(ctx)
+			if err != nil {
+				RecordError(span, err)
+				ctx.Error(err)
+			}
+
+			status := ctx.Response().Status
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/config"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/response"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/tracing"
+)
+
+var _ = Describe("Tracing", func() {
+	var previousProvider trace.TracerProvider
+
+	BeforeEach(func() {
+		previousProvider = otel.GetTracerProvider()
+	})
+
+	AfterEach(func() {
+		otel.SetTracerProvider(previousProvider)
+	})
+
+	Describe("Middleware", func() {
+		var e *echo.Echo
+		var recorder *tracetest.SpanRecorder
+
+		// attrs returns the attributes of the span by key
+		attrs := func(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+			values := map[attribute.Key]attribute.Value{}
+			for _, attr := range span.Attributes() {
+				values[attr.Key] = attr.Value
+			}
+			return values
+		}
+
+		serve := func(req *http.Request) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			return rec
+		}
+
+		BeforeEach(func() {
+			_, err := tracing.Setup(context.Background(), config.TracingConfig{Exporter: tracing.ExporterNone})
+			Expect(err).To(BeNil())
+
+			recorder = tracetest.NewSpanRecorder()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			e = echo.New()
+			e.HTTPErrorHandler = response.NewHTTPErrorHandler(e, response.FormatDefault)
+			e.Use(tracing.Middleware())
+			e.GET("/users/:id", func(ctx echo.Context) error {
+				// Spans started while handling the request are its children
+				_, span := tracing.Tracer().Start(ctx.Request().Context(), "child")
+				span.End()
+
+				return ctx.String(http.StatusOK, "ok")
+			})
+			e.POST("/users", func(ctx echo.Context) error {
+				return errors.New(errors.UsersRepoCreateUserDBQueryFail, nil)
+			})
+		})
+
+		It("should start a server span named after the route", func() {
+			serve(httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+			spans := recorder.Ended()
+			Expect(spans).To(HaveLen(2))
+
+			child, span := spans[0], spans[1]
+			Expect(span.Name()).To(Equal("GET /users/:id"))
+			Expect(span.SpanKind()).To(Equal(trace.SpanKindServer))
+			Expect(attrs(span)).To(HaveKeyWithValue(semconv.HTTPRouteKey, attribute.StringValue("/users/:id")))
+			Expect(attrs(span)).To(HaveKeyWithValue(semconv.URLPathKey, attribute.StringValue("/users/1")))
+			Expect(attrs(span)).To(HaveKeyWithValue(semconv.HTTPResponseStatusCodeKey, attribute.IntValue(http.StatusOK)))
+			Expect(child.Parent().SpanID()).To(Equal(span.SpanContext().SpanID()))
+		})
+
+		It("should continue the trace in the W3C trace context headers", func() {
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+			serve(req)
+
+			span := recorder.Ended()[1]
+			Expect(span.SpanContext().TraceID().String()).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(span.Parent().SpanID().String()).To(Equal("00f067aa0ba902b7"))
+			Expect(span.Parent().IsRemote()).To(BeTrue())
+		})
+
+		It("should record the error code and status of failed requests", func() {
+			code := errors.UsersRepoCreateUserDBQueryFail
+
+			rec := serve(httptest.NewRequest(http.MethodPost, "/users", nil))
+
+			span := recorder.Ended()[0]
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			Expect(span.Status().Code).To(Equal(codes.Error))
+			Expect(attrs(span)).To(HaveKeyWithValue(tracing.ErrorCodeKey, attribute.IntValue(int(code))))
+			Expect(attrs(span)).To(HaveKeyWithValue(semconv.ErrorTypeKey, attribute.StringValue(code.Name())))
+			Expect(attrs(span)).To(HaveKeyWithValue(semconv.HTTPResponseStatusCodeKey, attribute.IntValue(http.StatusInternalServerError)))
+		})
+	})
+
+	Describe("Setup", func() {
+		It("should export spans to the file", func() {
+			file := filepath.Join(GinkgoT().TempDir(), "traces.json")
+
+			shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{
+				Exporter:    tracing.ExporterFile,
+				File:        file,
+				ServiceName: "tracing-test",
+			})
+			Expect(err).To(BeNil())
+
+			_, span := tracing.Tracer().Start(context.Background(), "GET /users")
+			span.End()
+			Expect(shutdown(context.Background())).To(Succeed())
+
+			contents, err := os.ReadFile(file)
+			Expect(err).To(BeNil())
+			Expect(string(contents)).To(ContainSubstring(`"Name":"GET /users"`))
+			Expect(string(contents)).To(ContainSubstring("tracing-test"))
+		})
+
+		It("should not record spans without an exporter", func() {
+			otel.SetTracerProvider(noop.NewTracerProvider())
+
+			shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{Exporter: tracing.ExporterNone})
+			Expect(err).To(BeNil())
+			Expect(shutdown(context.Background())).To(Succeed())
+
+			_, span := tracing.Tracer().Start(context.Background(), "GET /users")
+			Expect(span.IsRecording()).To(BeFalse())
+		})
+
+		It("should return an error for an unknown exporter", func() {
+			_, err := tracing.Setup(context.Background(), config.TracingConfig{Exporter: "zipkin"})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
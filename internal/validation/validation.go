@@ -0,0 +1,161 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+)
+
+// UserStatuses are the values the user_status enum accepts
+var UserStatuses = []string{
+	models.UserStatusInactive,
+	models.UserStatusActive,
+	models.UserStatusTerminated,
+}
+
+// ValidateUser checks the fields of a user being created.
+//
+// Returns every field that failed validation, in the order they appear
+// on models.User. Returns an empty slice if the user is valid.
+func ValidateUser(user models.User) []ipErrors.FieldError {
+	return validateUser(user, false)
+}
+
+// ValidateUserUpdate checks the fields of a user being updated.
+//
+// Only fields that are set are checked, since empty fields are left
+// out of the update.
+// Returns an empty slice if the user is valid.
+func ValidateUserUpdate(user models.User) []ipErrors.FieldError {
+	return validateUser(user, true)
+}
+
+// ValidateStatusChange checks the fields of a status change being
+// scheduled.
+//
+// Returns an empty slice if the change is valid.
+func ValidateStatusChange(change models.ScheduledStatusChange) []ipErrors.FieldError {
+	v := validator{errors: []ipErrors.FieldError{}}
+
+	if v.required("user_status", change.UserStatus) {
+		v.oneOf("user_status", change.UserStatus, UserStatuses)
+	}
+
+	return v.errors
+}
+
+// ValidateActor checks the actor a client claims to be in the actor
+// header, which is recorded with the changes it makes.
+//
+// Returns an empty slice if the actor is valid.
+func ValidateActor(actor string) []ipErrors.FieldError {
+	v := validator{errors: []ipErrors.FieldError{}}
+
+	v.length(constants.AuditActorHeader, actor, false, constants.AuditActorMaxLength)
+
+	return v.errors
+}
+
+// FromBindError converts an error from binding a JSON body into the
+// field that could not be decoded.
+//
+// Returns false if the error is not tied to a specific field.
+func FromBindError(err error) (ipErrors.FieldError, bool) {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		return ipErrors.FieldError{}, false
+	}
+
+	return ipErrors.NewFieldError(typeErr.Field, ipErrors.FieldInvalidType, typeErr.Type.String()), true
+}
+
+// validateUser checks every field of user, skipping empty fields when partial
+// is true.
+func validateUser(user models.User, partial bool) []ipErrors.FieldError {
+	v := validator{partial: partial, errors: []ipErrors.FieldError{}}
+
+	v.length("user_name", user.Username, true, constants.UserNameMaxLength)
+	v.length("first_name", user.Firstname, true, constants.UserFieldsMaxLength)
+	v.length("last_name", user.Lastname, true, constants.UserFieldsMaxLength)
+	if v.length("email", user.Email, true, constants.UserFieldsMaxLength) {
+		v.email("email", user.Email)
+	}
+	v.oneOf("user_status", user.UserStatus, UserStatuses)
+	v.length("department", user.Department, false, constants.UserFieldsMaxLength)
+
+	return v.errors
+}
+
+// validator collects the FieldErrors found while checking a model
+type validator struct {
+	partial bool
+	errors  []ipErrors.FieldError
+}
+
+func (v *validator) add(field string, code ipErrors.ErrorCode, params ...any) {
+	v.errors = append(v.errors, ipErrors.NewFieldError(field, code, params...))
+}
+
+// length checks value is present when required and no longer than
+// maxLength characters.
+//
+// Returns true if the value is set and passed, so further checks can be run.
+func (v *validator) length(field string, value string, required bool, maxLength int) bool {
+	if strings.TrimSpace(value) == "" {
+		if required && !v.partial {
+			v.add(field, ipErrors.FieldRequired)
+		}
+		return false
+	}
+
+	// VARCHAR lengths are counted in characters rather than bytes
+	if utf8.RuneCountInString(value) > maxLength {
+		v.add(field, ipErrors.FieldTooLong, maxLength)
+		return false
+	}
+
+	return true
+}
+
+// required checks value is present, unless the validator is partial.
+//
+// Returns true if the value is set, so further checks can be run.
+func (v *validator) required(field string, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		if !v.partial {
+			v.add(field, ipErrors.FieldRequired)
+		}
+		return false
+	}
+
+	return true
+}
+
+// email checks value is a plain email address without a display name
+func (v *validator) email(field string, value string) {
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		v.add(field, ipErrors.FieldInvalidEmail)
+	}
+}
+
+// oneOf checks value is one of allowed, if it is set
+func (v *validator) oneOf(field string, value string, allowed []string) {
+	if value == "" {
+		return
+	}
+
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+
+	v.add(field, ipErrors.FieldInvalidValue, strings.Join(allowed, ", "))
+}
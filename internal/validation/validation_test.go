@@ -0,0 +1,150 @@
+package validation_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jfavo/integra-partners-assessment-backend/internal/constants"
+	ipErrors "github.com/jfavo/integra-partners-assessment-backend/internal/errors"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/models"
+	"github.com/jfavo/integra-partners-assessment-backend/internal/validation"
+)
+
+var _ = Describe("Validation", func() {
+
+	Describe("ValidateUser", func() {
+		It("should pass a valid user", func() {
+			Expect(validation.ValidateUser(constants.TestUsers[0])).To(BeEmpty())
+		})
+
+		It("should require the non-nullable fields", func() {
+			fieldErrs := validation.ValidateUser(models.User{Department: "sales"})
+
+			Expect(fieldErrs).To(Equal([]ipErrors.FieldError{
+				ipErrors.NewFieldError("user_name", ipErrors.FieldRequired),
+				ipErrors.NewFieldError("first_name", ipErrors.FieldRequired),
+				ipErrors.NewFieldError("last_name", ipErrors.FieldRequired),
+				ipErrors.NewFieldError("email", ipErrors.FieldRequired),
+			}))
+		})
+
+		It("should treat whitespace as missing", func() {
+			user := constants.TestUsers[0]
+			user.Firstname = "   "
+
+			Expect(validation.ValidateUser(user)).To(Equal([]ipErrors.FieldError{
+				ipErrors.NewFieldError("first_name", ipErrors.FieldRequired),
+			}))
+		})
+
+		It("should reject fields longer than their columns", func() {
+			user := constants.TestUsers[0]
+			user.Username = strings.Repeat("a", constants.UserNameMaxLength+1)
+			user.Department = strings.Repeat("a", constants.UserFieldsMaxLength+1)
+
+			Expect(validation.ValidateUser(user)).To(Equal([]ipErrors.FieldError{
+				ipErrors.NewFieldError("user_name", ipErrors.FieldTooLong, 50),
+				ipErrors.NewFieldError("department", ipErrors.FieldTooLong, 255),
+			}))
+		})
+
+		It("should count lengths in characters", func() {
+			user := constants.TestUsers[0]
+			user.Username = strings.Repeat("é", constants.UserNameMaxLength)
+
+			Expect(validation.ValidateUser(user)).To(BeEmpty())
+		})
+
+		DescribeTable("should reject invalid emails",
+			func(email string) {
+				user := constants.TestUsers[0]
+				user.Email = email
+
+				Expect(validation.ValidateUser(user)).To(Equal([]ipErrors.FieldError{
+					ipErrors.NewFieldError("email", ipErrors.FieldInvalidEmail),
+				}))
+			},
+			Entry("missing domain", "test@"),
+			Entry("missing at sign", "test.user.com"),
+			Entry("display name", "Test User <test@user.com>"),
+			Entry("surrounding spaces", " test@user.com "),
+		)
+
+		It("should reject unknown user statuses", func() {
+			user := constants.TestUsers[0]
+			user.UserStatus = "X"
+
+			Expect(validation.ValidateUser(user)).To(Equal([]ipErrors.FieldError{
+				ipErrors.NewFieldError("user_status", ipErrors.FieldInvalidValue, "I, A, T"),
+			}))
+		})
+	})
+
+	Describe("ValidateUserUpdate", func() {
+		It("should allow fields to be left out", func() {
+			Expect(validation.ValidateUserUpdate(models.User{UserId: 1, Department: "warehouse"})).To(BeEmpty())
+		})
+
+		It("should still check the fields that are set", func() {
+			fieldErrs := validation.ValidateUserUpdate(models.User{UserId: 1, Email: "not-an-email", UserStatus: "X"})
+
+			Expect(fieldErrs).To(HaveLen(2))
+			Expect(fieldErrs[0].Field).To(Equal("email"))
+			Expect(fieldErrs[1].Field).To(Equal("user_status"))
+		})
+	})
+
+	Describe("ValidateStatusChange", func() {
+		It("should pass a known user status", func() {
+			Expect(validation.ValidateStatusChange(models.ScheduledStatusChange{UserStatus: "I"})).To(BeEmpty())
+		})
+
+		It("should require the user status", func() {
+			Expect(validation.ValidateStatusChange(models.ScheduledStatusChange{UserStatus: " "})).To(ConsistOf(
+				ipErrors.NewFieldError("user_status", ipErrors.FieldRequired)))
+		})
+
+		It("should reject unknown user statuses", func() {
+			Expect(validation.ValidateStatusChange(models.ScheduledStatusChange{UserStatus: "X"})).To(ConsistOf(
+				ipErrors.NewFieldError("user_status", ipErrors.FieldInvalidValue, "I, A, T")))
+		})
+	})
+
+	Describe("ValidateActor", func() {
+		It("should allow the actor to be left out", func() {
+			Expect(validation.ValidateActor("")).To(BeEmpty())
+		})
+
+		It("should reject actors longer than the audit columns, counting characters", func() {
+			Expect(validation.ValidateActor(strings.Repeat("é", constants.AuditActorMaxLength))).To(BeEmpty())
+
+			fieldErrs := validation.ValidateActor(strings.Repeat("é", constants.AuditActorMaxLength+1))
+
+			Expect(fieldErrs).To(ConsistOf(
+				ipErrors.NewFieldError(constants.AuditActorHeader, ipErrors.FieldTooLong, constants.AuditActorMaxLength)))
+		})
+	})
+
+	Describe("FromBindError", func() {
+		It("should return the field that failed to decode", func() {
+			var user models.User
+			err := json.Unmarshal([]byte(`{"user_name":5}`), &user)
+
+			fieldErr, ok := validation.FromBindError(err)
+
+			Expect(ok).To(BeTrue())
+			Expect(fieldErr).To(Equal(ipErrors.NewFieldError("user_name", ipErrors.FieldInvalidType, "string")))
+			Expect(fieldErr.Message).To(Equal("must be of type string"))
+		})
+
+		It("should return false for errors without a field", func() {
+			_, ok := validation.FromBindError(errors.New("unexpected EOF"))
+
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
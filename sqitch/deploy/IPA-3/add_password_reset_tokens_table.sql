@@ -0,0 +1,20 @@
+-- Deploy the password_reset_tokens table to the integra_partners schema
+
+BEGIN;
+
+-- Only the SHA-256 hash of a token is stored, the raw token is only
+-- ever sent to the user via email.
+CREATE TABLE IF NOT EXISTS integra_partners.password_reset_tokens (
+    token_id    BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY NOT NULL,
+    user_id     BIGINT NOT NULL
+                REFERENCES integra_partners.users (user_id) ON DELETE CASCADE,
+    token_hash  CHAR(64) NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX password_reset_tokens_token_hash_idx
+    ON integra_partners.password_reset_tokens (token_hash);
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- Deploy the user_credentials table to the integra_partners schema
+
+BEGIN;
+
+-- Credentials are kept out of the users table so that user reads
+-- never have a chance of returning a password hash.
+CREATE TABLE IF NOT EXISTS integra_partners.user_credentials (
+    user_id         BIGINT PRIMARY KEY NOT NULL
+                    REFERENCES integra_partners.users (user_id) ON DELETE CASCADE,
+    password_hash   VARCHAR(255) NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,40 @@
+-- Deploy the audit_log table to the integra_partners schema
+
+BEGIN;
+
+CREATE TYPE integra_partners.audit_action AS ENUM (
+    'CREATE',
+    'UPDATE',
+    'DELETE'
+);
+
+-- user_id intentionally has no foreign key so entries outlive the
+-- users they describe.
+CREATE TABLE IF NOT EXISTS integra_partners.audit_log (
+    audit_id    BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY NOT NULL,
+    actor       VARCHAR(255) NOT NULL,
+    action      integra_partners.audit_action NOT NULL,
+    user_id     BIGINT NOT NULL,
+    changes     JSONB NOT NULL,
+    request_id  VARCHAR(255),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX audit_log_user_id_idx ON integra_partners.audit_log (user_id);
+CREATE INDEX audit_log_actor_idx ON integra_partners.audit_log (actor);
+CREATE INDEX audit_log_created_at_idx ON integra_partners.audit_log (created_at);
+
+-- The audit log is append-only, any attempt to modify or remove
+-- entries will raise an exception.
+CREATE FUNCTION integra_partners.prevent_audit_log_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'integra_partners.audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON integra_partners.audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION integra_partners.prevent_audit_log_modification();
+
+COMMIT;
@@ -0,0 +1,67 @@
+-- Deploy the users_history table and the trigger that maintains it
+
+BEGIN;
+
+-- Every version of a user is kept along with the time range it was valid
+-- for. The current version has a NULL valid_to. Deletes are recorded as a
+-- version where valid_from and valid_to are equal, so they appear in the
+-- history without ever being returned by a point-in-time read.
+CREATE TABLE IF NOT EXISTS integra_partners.users_history (
+    history_id  BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY NOT NULL,
+    user_id     BIGINT NOT NULL,
+    user_name   VARCHAR(50) NOT NULL,
+    first_name  VARCHAR(255) NOT NULL,
+    last_name   VARCHAR(255) NOT NULL,
+    email       VARCHAR(255) NOT NULL,
+    user_status integra_partners.user_status,
+    department  VARCHAR(255),
+    operation   integra_partners.audit_action NOT NULL,
+    valid_from  TIMESTAMPTZ NOT NULL,
+    valid_to    TIMESTAMPTZ
+);
+
+CREATE INDEX users_history_user_id_valid_from_idx
+    ON integra_partners.users_history (user_id, valid_from);
+
+CREATE FUNCTION integra_partners.record_user_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Close off the current version of the user
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE integra_partners.users_history
+        SET valid_to = NOW()
+        WHERE user_id = OLD.user_id
+        AND valid_to IS NULL;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO integra_partners.users_history
+            (user_id, user_name, first_name, last_name, email, user_status, department, operation, valid_from, valid_to)
+        VALUES
+            (OLD.user_id, OLD.user_name, OLD.first_name, OLD.last_name, OLD.email, OLD.user_status, OLD.department, 'DELETE', NOW(), NOW());
+
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO integra_partners.users_history
+        (user_id, user_name, first_name, last_name, email, user_status, department, operation, valid_from)
+    VALUES
+        (NEW.user_id, NEW.user_name, NEW.first_name, NEW.last_name, NEW.email, NEW.user_status, NEW.department,
+        CASE TG_OP WHEN 'INSERT' THEN 'CREATE' ELSE 'UPDATE' END::integra_partners.audit_action, NOW());
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_record_history
+    AFTER INSERT OR UPDATE OR DELETE ON integra_partners.users
+    FOR EACH ROW EXECUTE FUNCTION integra_partners.record_user_history();
+
+-- Existing users have no known history, so their current state becomes
+-- their first version.
+INSERT INTO integra_partners.users_history
+    (user_id, user_name, first_name, last_name, email, user_status, department, operation, valid_from)
+SELECT user_id, user_name, first_name, last_name, email, user_status, department, 'CREATE', NOW()
+FROM integra_partners.users;
+
+COMMIT;
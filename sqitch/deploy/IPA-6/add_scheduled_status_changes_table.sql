@@ -0,0 +1,23 @@
+-- Deploy the scheduled_status_changes table to the integra_partners schema
+
+BEGIN;
+
+-- A change is pending while both applied_at and cancelled_at are NULL.
+CREATE TABLE IF NOT EXISTS integra_partners.scheduled_status_changes (
+    change_id       BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY NOT NULL,
+    user_id         BIGINT NOT NULL
+                    REFERENCES integra_partners.users (user_id) ON DELETE CASCADE,
+    user_status     integra_partners.user_status NOT NULL,
+    effective_at    TIMESTAMPTZ NOT NULL,
+    actor           VARCHAR(255) NOT NULL,
+    request_id      VARCHAR(255),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    applied_at      TIMESTAMPTZ,
+    cancelled_at    TIMESTAMPTZ
+);
+
+CREATE INDEX scheduled_status_changes_pending_idx
+    ON integra_partners.scheduled_status_changes (effective_at)
+    WHERE applied_at IS NULL AND cancelled_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Deploy the reason column to the audit_log and scheduled_status_changes tables
+
+BEGIN;
+
+-- Optional free text explaining why a change was made
+ALTER TABLE integra_partners.audit_log
+    ADD COLUMN reason TEXT;
+
+ALTER TABLE integra_partners.scheduled_status_changes
+    ADD COLUMN reason TEXT;
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Deploy the status change actions to the audit_action type
+
+BEGIN;
+
+-- Scheduling and cancelling a status change are recorded against the
+-- user the change is for
+ALTER TYPE integra_partners.audit_action ADD VALUE 'SCHEDULE_STATUS_CHANGE';
+ALTER TYPE integra_partners.audit_action ADD VALUE 'CANCEL_STATUS_CHANGE';
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Revert integra-partners-assessment-db:IPA-3/add_password_reset_tokens_table from pg
+
+BEGIN;
+
+DROP TABLE integra_partners.password_reset_tokens;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Revert integra-partners-assessment-db:IPA-3/add_user_credentials_table from pg
+
+BEGIN;
+
+DROP TABLE integra_partners.user_credentials;
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Revert integra-partners-assessment-db:IPA-4/add_audit_log_table from pg
+
+BEGIN;
+
+DROP TABLE integra_partners.audit_log;
+DROP FUNCTION integra_partners.prevent_audit_log_modification();
+DROP TYPE integra_partners.audit_action;
+
+COMMIT;
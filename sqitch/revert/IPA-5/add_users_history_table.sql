@@ -0,0 +1,9 @@
+-- Revert integra-partners-assessment-db:IPA-5/add_users_history_table from pg
+
+BEGIN;
+
+DROP TRIGGER users_record_history ON integra_partners.users;
+DROP FUNCTION integra_partners.record_user_history();
+DROP TABLE integra_partners.users_history;
+
+COMMIT;
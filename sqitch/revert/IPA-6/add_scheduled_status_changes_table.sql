@@ -0,0 +1,7 @@
+-- Revert integra-partners-assessment-db:IPA-6/add_scheduled_status_changes_table from pg
+
+BEGIN;
+
+DROP TABLE integra_partners.scheduled_status_changes;
+
+COMMIT;
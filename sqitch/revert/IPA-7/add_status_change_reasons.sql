@@ -0,0 +1,11 @@
+-- Revert integra-partners-assessment-db:IPA-7/add_status_change_reasons from pg
+
+BEGIN;
+
+ALTER TABLE integra_partners.scheduled_status_changes
+    DROP COLUMN reason;
+
+ALTER TABLE integra_partners.audit_log
+    DROP COLUMN reason;
+
+COMMIT;
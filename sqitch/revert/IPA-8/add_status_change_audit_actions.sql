@@ -0,0 +1,25 @@
+-- Revert integra-partners-assessment-db:IPA-8/add_status_change_audit_actions from pg
+
+BEGIN;
+
+-- Enum values can't be dropped, so the type is recreated without them.
+-- This fails if the append-only audit log already has entries using them.
+ALTER TYPE integra_partners.audit_action RENAME TO audit_action_old;
+
+CREATE TYPE integra_partners.audit_action AS ENUM (
+    'CREATE',
+    'UPDATE',
+    'DELETE'
+);
+
+ALTER TABLE integra_partners.audit_log
+    ALTER COLUMN action TYPE integra_partners.audit_action
+    USING action::text::integra_partners.audit_action;
+
+ALTER TABLE integra_partners.users_history
+    ALTER COLUMN operation TYPE integra_partners.audit_action
+    USING operation::text::integra_partners.audit_action;
+
+DROP TYPE integra_partners.audit_action_old;
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Verify integra-partners-assessment-db:IPA-3/add_password_reset_tokens_table on pg
+
+BEGIN;
+
+-- Will throw an exception if the table or any of the columns do not exist
+SELECT token_id, user_id, token_hash, expires_at, used_at, created_at
+FROM integra_partners.password_reset_tokens
+WHERE FALSE;
+
+DO $$
+BEGIN
+    ASSERT (
+        SELECT 1
+        FROM pg_indexes
+        WHERE schemaname = 'integra_partners'
+        AND indexname = 'password_reset_tokens_token_hash_idx'
+    );
+END $$;
+
+ROLLBACK;
@@ -0,0 +1,10 @@
+-- Verify integra-partners-assessment-db:IPA-3/add_user_credentials_table on pg
+
+BEGIN;
+
+-- Will throw an exception if the table or any of the columns do not exist
+SELECT user_id, password_hash, updated_at
+FROM integra_partners.user_credentials
+WHERE FALSE;
+
+ROLLBACK;
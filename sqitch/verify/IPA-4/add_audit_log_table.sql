@@ -0,0 +1,20 @@
+-- Verify integra-partners-assessment-db:IPA-4/add_audit_log_table on pg
+
+BEGIN;
+
+-- Will throw an exception if the table or any of the columns do not exist
+SELECT audit_id, actor, action, user_id, changes, request_id, created_at
+FROM integra_partners.audit_log
+WHERE FALSE;
+
+-- Verify the append-only trigger exists
+DO $$
+BEGIN
+    ASSERT (
+        SELECT 1
+        FROM pg_trigger
+        WHERE tgname = 'audit_log_append_only'
+    );
+END $$;
+
+ROLLBACK;
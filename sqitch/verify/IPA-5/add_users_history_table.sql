@@ -0,0 +1,21 @@
+-- Verify integra-partners-assessment-db:IPA-5/add_users_history_table on pg
+
+BEGIN;
+
+-- Will throw an exception if the table or any of the columns do not exist
+SELECT history_id, user_id, user_name, first_name, last_name, email,
+    user_status, department, operation, valid_from, valid_to
+FROM integra_partners.users_history
+WHERE FALSE;
+
+-- Verify the trigger maintaining the history exists
+DO $$
+BEGIN
+    ASSERT (
+        SELECT 1
+        FROM pg_trigger
+        WHERE tgname = 'users_record_history'
+    );
+END $$;
+
+ROLLBACK;
@@ -0,0 +1,21 @@
+-- Verify integra-partners-assessment-db:IPA-6/add_scheduled_status_changes_table on pg
+
+BEGIN;
+
+-- Will throw an exception if the table or any of the columns do not exist
+SELECT change_id, user_id, user_status, effective_at, actor, request_id,
+    created_at, applied_at, cancelled_at
+FROM integra_partners.scheduled_status_changes
+WHERE FALSE;
+
+DO $$
+BEGIN
+    ASSERT (
+        SELECT 1
+        FROM pg_indexes
+        WHERE schemaname = 'integra_partners'
+        AND indexname = 'scheduled_status_changes_pending_idx'
+    );
+END $$;
+
+ROLLBACK;
@@ -0,0 +1,14 @@
+-- Verify integra-partners-assessment-db:IPA-7/add_status_change_reasons on pg
+
+BEGIN;
+
+-- Will throw an exception if either of the columns do not exist
+SELECT reason
+FROM integra_partners.audit_log
+WHERE FALSE;
+
+SELECT reason
+FROM integra_partners.scheduled_status_changes
+WHERE FALSE;
+
+ROLLBACK;
@@ -0,0 +1,9 @@
+-- Verify integra-partners-assessment-db:IPA-8/add_status_change_audit_actions on pg
+
+BEGIN;
+
+-- Will throw an exception if either of the values do not exist
+SELECT 'SCHEDULE_STATUS_CHANGE'::integra_partners.audit_action,
+       'CANCEL_STATUS_CHANGE'::integra_partners.audit_action;
+
+ROLLBACK;